@@ -7,6 +7,7 @@ import (
 	"os/signal"
 	"syscall"
 	"time"
+	_ "time/tzdata" // Часовые пояса расписания не зависят от tzdata в образе
 
 	log "github.com/sirupsen/logrus"
 
@@ -15,7 +16,9 @@ import (
 	"github.com/lsdpls/schulze_election_telegram_bot/internal/chain"
 	"github.com/lsdpls/schulze_election_telegram_bot/internal/config"
 	"github.com/lsdpls/schulze_election_telegram_bot/internal/db"
+	"github.com/lsdpls/schulze_election_telegram_bot/internal/polling"
 	"github.com/lsdpls/schulze_election_telegram_bot/internal/schulze"
+	"github.com/lsdpls/schulze_election_telegram_bot/internal/session"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 )
@@ -47,25 +50,71 @@ func main() {
 
 	schulze := schulze.NewSchulze(voteChain)
 
-	// Инициализация объекта бота
-	botHandler := bot.NewBot(botAPI, voteChain, schulze)
+	// Хранилище сессий переживает перезапуски, истекшие сессии периодически удаляются
+	sessions := session.NewPostgresStore(voteChain)
+	backgroundCtx, stopBackground := context.WithCancel(context.Background())
+	defer stopBackground()
+	go sessions.RunCleanup(backgroundCtx, time.Hour)
+
+	// Инициализация объекта бота. При завершении Close дожидается обработки обновлений, уже принятых в очередь
+	botHandler := bot.NewBot(botAPI, voteChain, schulze, sessions)
 	defer botHandler.Close()
+	if err := botHandler.RestoreState(context.Background()); err != nil {
+		log.Errorf("Failed to restore bot state: %v", err)
+	}
+	// Меню команд строится из реестра команд с учетом ролей администраторов
+	if err := botHandler.SyncCommands(context.Background()); err != nil {
+		log.Errorf("Failed to sync bot commands: %v", err)
+	}
+	// Планировщик открывает и закрывает регистрацию и голосование по расписанию
+	go botHandler.RunScheduler(backgroundCtx, 30*time.Second)
+	// Закрепленное сообщение с явкой в чате администраторов
+	go botHandler.RunTurnoutDashboard(backgroundCtx, config.TurnoutRefreshInterval)
 
 	// Инициализируем API handler
-	apiHandler := api.NewHandler(voteChain)
+	apiHandler := api.NewHandler(voteChain, schulze)
 
-	// Health check должен быть ПЕРЕД catch-all обработчиком
+	// Health check
 	http.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusOK)
 		w.Write([]byte("healthy"))
 	})
 	http.HandleFunc("/votes", apiHandler.GetVotes)
+	http.HandleFunc("/votes/{token}", apiHandler.GetVote)
 	http.HandleFunc("/candidates", apiHandler.GetCandidates)
 	http.HandleFunc("/result", apiHandler.GetResults)
-	// Catch-all обработчик для webhook (должен быть последним)
-	http.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
-		botHandler.HandleWebhook(w, r)
-	})
+	http.HandleFunc("/protocol", apiHandler.GetProtocol)
+	http.HandleFunc("/api/v2/results", apiHandler.GetResultsV2)
+	// Mini App с бюллетенем
+	http.HandleFunc("/webapp", botHandler.HandleWebAppPage)
+	http.HandleFunc("/webapp/candidates", botHandler.HandleWebAppCandidates)
+	http.HandleFunc("/webapp/vote", botHandler.HandleWebAppVote)
+	// Обновления Telegram: вебхук или long polling (UPDATES_MODE)
+	pollingDone := make(chan struct{})
+	pollingCtx, stopPolling := context.WithCancel(context.Background())
+	if config.UpdatesMode == config.UpdatesModeWebhook {
+		close(pollingDone)
+		// Вебхук принимается только на пути, выведенном из секрета, и с секретом в заголовке
+		http.HandleFunc(bot.WebhookPath(config.WebhookSecret), botHandler.HandleWebhook)
+		if err := botHandler.SetWebhook(config.WebhookURL, config.WebhookSecret); err != nil {
+			log.Fatalf("Failed to set webhook: %v", err)
+		}
+		log.Infof("Webhook set on %s", config.WebhookURL)
+	} else {
+		// Long polling: публичный адрес не нужен, HTTP сервер обслуживает только API и Mini App
+		// Смещение продвигается только после обработки обновления диспетчером, а не после постановки в очередь
+		poller := polling.NewPoller(botAPI, sessions, botHandler.Logger(), func(ctx context.Context, update tgbotapi.Update, done func()) {
+			if err := botHandler.DispatchNotify(ctx, update, done); err != nil {
+				log.Errorf("Failed to dispatch update %d: %v", update.UpdateID, err)
+			}
+		})
+		go func() {
+			defer close(pollingDone)
+			if err := poller.Run(pollingCtx); err != nil {
+				log.Fatalf("Error running long polling: %v", err)
+			}
+		}()
+	}
 
 	// Запускаем HTTP сервер
 	srv := &http.Server{Addr: ":" + config.AppPort}
@@ -84,6 +133,9 @@ func main() {
 	<-quit
 	log.Infoln("Shutting down bot...")
 	botAPI.StopReceivingUpdates()
+	// Дожидаемся обработки текущего обновления, чтобы сохранить смещение
+	stopPolling()
+	<-pollingDone
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 	if err := srv.Shutdown(ctx); err != nil {
@@ -0,0 +1,9 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE results ADD COLUMN computed_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP; -- Время вычисления результатов
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE results DROP COLUMN IF EXISTS computed_at;
+-- +goose StatementEnd
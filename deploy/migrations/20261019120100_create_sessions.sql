@@ -0,0 +1,16 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE sessions (
+    key TEXT PRIMARY KEY,                               -- Ключ сессии (например, ballot:<telegram_id>)
+    value JSONB NOT NULL,                               -- Состояние в формате JSON
+    expires_at TIMESTAMP,                               -- Время истечения (NULL — бессрочно)
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP      -- Время последнего изменения
+);
+
+CREATE INDEX sessions_expires_at_idx ON sessions (expires_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS sessions CASCADE;
+-- +goose StatementEnd
@@ -0,0 +1,18 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE election_schedule (
+    kind TEXT PRIMARY KEY CHECK (kind IN ('registration', 'voting')), -- Окно: регистрация или голосование
+    starts_at TIMESTAMPTZ NOT NULL,                                    -- Начало окна
+    ends_at TIMESTAMPTZ NOT NULL CHECK (ends_at > starts_at),          -- Окончание окна
+    timezone TEXT NOT NULL,                                            -- Часовой пояс для отображения времени
+    opened_at TIMESTAMPTZ,                                             -- Когда планировщик открыл окно
+    closed_at TIMESTAMPTZ,                                             -- Когда планировщик закрыл окно
+    announcements TEXT[] NOT NULL DEFAULT '{}',                        -- Уже отправленные объявления
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP                   -- Время последнего изменения
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS election_schedule CASCADE;
+-- +goose StatementEnd
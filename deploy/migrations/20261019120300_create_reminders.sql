@@ -0,0 +1,20 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE reminders (
+    id SERIAL PRIMARY KEY,                            -- Уникальный идентификатор напоминания
+    text TEXT NOT NULL,                               -- Текст напоминания
+    send_at TIMESTAMPTZ NOT NULL,                     -- Запланированное время отправки
+    sent_at TIMESTAMPTZ,                              -- Когда началась рассылка (NULL — еще не отправлено)
+    delivered INT NOT NULL DEFAULT 0,                 -- Доставлено сообщений
+    blocked INT NOT NULL DEFAULT 0,                   -- Делегаты, заблокировавшие бота
+    failed INT NOT NULL DEFAULT 0,                    -- Ошибки отправки
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP  -- Время создания
+);
+
+CREATE INDEX reminders_pending_idx ON reminders (send_at) WHERE sent_at IS NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS reminders CASCADE;
+-- +goose StatementEnd
@@ -0,0 +1,14 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE admins (
+    telegram_id BIGINT PRIMARY KEY,                                                      -- ID пользователя в Telegram
+    role TEXT NOT NULL CHECK (role IN ('superadmin', 'operator', 'observer', 'tally_officer')), -- Роль
+    granted_by BIGINT,                                                                   -- Кто выдал роль
+    granted_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP                                     -- Когда выдана роль
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS admins CASCADE;
+-- +goose StatementEnd
@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE candidates ADD COLUMN photo_file_id TEXT NOT NULL DEFAULT '';  -- file_id фото кандидата в Telegram
+ALTER TABLE candidates ADD COLUMN manifesto_url TEXT NOT NULL DEFAULT '';  -- Ссылка на программу кандидата
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE candidates DROP COLUMN IF EXISTS manifesto_url;
+ALTER TABLE candidates DROP COLUMN IF EXISTS photo_file_id;
+-- +goose StatementEnd
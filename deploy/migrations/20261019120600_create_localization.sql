@@ -0,0 +1,23 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE user_languages (
+    telegram_id BIGINT PRIMARY KEY,                         -- ID пользователя в Telegram
+    language TEXT NOT NULL CHECK (language IN ('ru', 'en')), -- Выбранный язык интерфейса
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP        -- Когда выбран язык
+);
+
+CREATE TABLE text_overrides (
+    language TEXT NOT NULL CHECK (language IN ('ru', 'en')), -- Язык текста
+    key TEXT NOT NULL,                                      -- Ключ сообщения в каталоге
+    text TEXT NOT NULL,                                     -- Текст, заданный администратором
+    updated_by BIGINT,                                      -- Кто изменил текст
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,       -- Когда изменен текст
+    PRIMARY KEY (language, key)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS text_overrides CASCADE;
+DROP TABLE IF EXISTS user_languages CASCADE;
+-- +goose StatementEnd
@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE audit_log (
+    id SERIAL PRIMARY KEY,                            -- Уникальный идентификатор записи
+    action TEXT NOT NULL,                             -- Действие (reset_registration и т. д.)
+    delegate_id INT NOT NULL,                         -- Делегат; без внешнего ключа, чтобы история пережила удаление делегата
+    telegram_id BIGINT,                               -- Telegram аккаунт, которого касается действие
+    performed_by BIGINT,                              -- Кто выполнил действие
+    details TEXT NOT NULL DEFAULT '',                 -- Подробности
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP  -- Время действия
+);
+
+CREATE INDEX audit_log_delegate_idx ON audit_log (delegate_id, created_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS audit_log CASCADE;
+-- +goose StatementEnd
@@ -0,0 +1,26 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE proxies (
+    id SERIAL PRIMARY KEY,                                                    -- Уникальный идентификатор доверенности
+    delegate_id INT NOT NULL REFERENCES delegates(delegate_id) ON DELETE CASCADE, -- Делегат, передавший голос
+    substitute_id INT NOT NULL,                                               -- Шестизначный код заместителя из st-email
+    telegram_id BIGINT,                                                       -- Telegram аккаунт заместителя после подтверждения почты
+    nominated_by BIGINT,                                                      -- Кто назначил заместителя (делегат или администратор)
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,                         -- Время назначения
+    revoked_at TIMESTAMPTZ,                                                   -- Время отзыва (NULL — доверенность действует)
+    revoked_by BIGINT                                                         -- Кто отозвал доверенность
+);
+
+-- У делегата и у заместителя не больше одной действующей доверенности
+CREATE UNIQUE INDEX proxies_active_delegate_idx ON proxies (delegate_id) WHERE revoked_at IS NULL;
+CREATE UNIQUE INDEX proxies_active_substitute_idx ON proxies (substitute_id) WHERE revoked_at IS NULL;
+
+-- Голос заместителя хранится под ID делегата с отметкой доверенности
+ALTER TABLE votes ADD COLUMN proxy_id INT REFERENCES proxies(id) ON DELETE SET NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE votes DROP COLUMN IF EXISTS proxy_id;
+DROP TABLE IF EXISTS proxies CASCADE;
+-- +goose StatementEnd
@@ -0,0 +1,14 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE vote_receipts (
+    delegate_id INT PRIMARY KEY REFERENCES delegates(delegate_id) ON DELETE CASCADE, -- Делегат, за которого подан бюллетень
+    candidate_rankings INT[] NOT NULL,                                              -- Ранжирование, которое бот подтвердил голосующему
+    telegram_id BIGINT NOT NULL,                                                    -- Аккаунт, которому отправлено подтверждение
+    confirmed_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP                     -- Время подтверждения
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS vote_receipts CASCADE;
+-- +goose StatementEnd
@@ -60,33 +60,12 @@ echo "✅ SSL сертификат успешно получен!"
 echo "🔄 Настраиваем автообновление сертификата..."
 (crontab -l 2>/dev/null; echo "0 2 * * 0 /usr/bin/certbot renew --quiet && cd $DEPLOY_DIR && docker-compose restart nginx") | crontab -
 
-# 8. Проверяем и настраиваем webhook
-echo "🔗 Проверяем текущий webhook..."
-CURRENT_WEBHOOK=$(curl -s "https://api.telegram.org/bot$TELEGRAM_APITOKEN/getWebhookInfo" | grep -o '"url":"[^"]*"' | cut -d'"' -f4)
-WEBHOOK_URL="https://$DOMAIN/election_bot/"
-    
-if [ "$CURRENT_WEBHOOK" = "$WEBHOOK_URL" ]; then
-    echo "✅ Webhook уже настроен правильно: $CURRENT_WEBHOOK"
-else
-    echo "📱 Настраиваем webhook для домена $DOMAIN..."
-
-    RESPONSE=$(curl -s -X POST "https://api.telegram.org/bot$TELEGRAM_APITOKEN/setWebhook" \
-        -H "Content-Type: application/json" \
-        -d "{\"url\": \"$WEBHOOK_URL\"}")
-    
-    if echo "$RESPONSE" | grep -q '"ok":true'; then
-        # Проверяем, что webhook действительно настроился правильно
-        sleep 2  # Даем время Telegram API обновиться
-        CURRENT_WEBHOOK=$(curl -s "https://api.telegram.org/bot$TELEGRAM_APITOKEN/getWebhookInfo" | grep -o '"url":"[^"]*"' | cut -d'"' -f4)
-        
-        if [ "$CURRENT_WEBHOOK" = "$WEBHOOK_URL" ]; then
-            echo "✅ Webhook подтвержден: $CURRENT_WEBHOOK"
-        else
-            echo "⚠️  Webhook настроен, но проверка не прошла. Текущий URL: $CURRENT_WEBHOOK"
-        fi
-    else
-        echo "❌ Ошибка настройки webhook: $RESPONSE"
-    fi
+# 8. Webhook устанавливает сам бот при запуске: адрес из WEBHOOK_URL, секрет из WEBHOOK_SECRET
+if ! grep -q "^WEBHOOK_URL=https://$DOMAIN/election_bot$" "$DEPLOY_DIR/.env"; then
+    echo "⚠️  Укажите в .env WEBHOOK_URL=https://$DOMAIN/election_bot"
+fi
+if ! grep -qE "^WEBHOOK_SECRET=[A-Za-z0-9_-]{32,256}$" "$DEPLOY_DIR/.env"; then
+    echo "⚠️  Укажите в .env WEBHOOK_SECRET, например: $(openssl rand -hex 32)"
 fi
 
 echo "✅ Настройка завершена!"
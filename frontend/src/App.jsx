@@ -17,75 +17,57 @@ function App() {
     return text.split(' ').map(word => word[0]).join('');
   };
 
-  const MatrixTable = ({ data, type = 'preferences', candidates: candidatesData }) => {
+  const MatrixTable = ({ data, order, type = 'preferences' }) => {
     const [hoveredCell, setHoveredCell] = useState(null);
-    
-    try {
-      const matrix = typeof data === 'string' ? JSON.parse(data) : data;
-      const candidates = Object.keys(matrix).sort((a, b) => parseInt(a) - parseInt(b));
-      
-      // Функция для определения, нужно ли выделить ячейку
-      const shouldHighlight = (rowCandidate, colCandidate) => {
-        if (rowCandidate === colCandidate) return false;
-        
-        if (type === 'preferences') {
-          // В preferences выделяем ячейки где A > B (прямое предпочтение)
-          const directPreference = matrix[rowCandidate] && matrix[rowCandidate][colCandidate] || 0;
-          const reversePreference = matrix[colCandidate] && matrix[colCandidate][rowCandidate] || 0;
-          return directPreference > reversePreference;
-        } else if (type === 'strongest_paths') {
-          // В strongest paths выделяем ячейки где A > B (парные победы)
-          const directPath = matrix[rowCandidate] && matrix[rowCandidate][colCandidate] || 0;
-          const reversePath = matrix[colCandidate] && matrix[colCandidate][rowCandidate] || 0;
-          return directPath > reversePath;
-        }
-        return false;
-      };
 
-      // Функция для определения, является ли ячейка частью пары при hover
-      const isHoveredPair = (rowCandidate, colCandidate) => {
-        if (!hoveredCell) return false;
-        const [hoverRow, hoverCol] = hoveredCell;
-        return (rowCandidate === hoverRow && colCandidate === hoverCol) ||
-               (rowCandidate === hoverCol && colCandidate === hoverRow);
-      };
-      
-      return (
-        <div className="matrix-table">
-          <table>
-            <thead>
-              <tr>
-                <th></th>
-                {candidates.map(candidate => (
-                  <th key={candidate}>{candidatesData[candidate]?.name || `К${candidate}`}</th>
+    // Матрица приходит массивом в порядке candidate_order, поэтому работаем с индексами
+    const shouldHighlight = (row, col) => {
+      if (row === col) return false;
+      // В preferences и strongest paths выделяем ячейки где A > B
+      return type === 'preferences' || type === 'strongest_paths'
+        ? data[row][col] > data[col][row]
+        : false;
+    };
+
+    // Функция для определения, является ли ячейка частью пары при hover
+    const isHoveredPair = (row, col) => {
+      if (!hoveredCell) return false;
+      const [hoverRow, hoverCol] = hoveredCell;
+      return (row === hoverRow && col === hoverCol) ||
+             (row === hoverCol && col === hoverRow);
+    };
+
+    return (
+      <div className="matrix-table">
+        <table>
+          <thead>
+            <tr>
+              <th></th>
+              {order.map(candidate => (
+                <th key={candidate.candidate_id}>{candidate.name || `К${candidate.candidate_id}`}</th>
+              ))}
+            </tr>
+          </thead>
+          <tbody>
+            {order.map((rowCandidate, row) => (
+              <tr key={rowCandidate.candidate_id}>
+                <td className="matrix-label">{rowCandidate.name || `К${rowCandidate.candidate_id}`}</td>
+                {order.map((colCandidate, col) => (
+                  <td
+                    key={colCandidate.candidate_id}
+                    className={`matrix-cell ${shouldHighlight(row, col) ? 'matrix-highlight' : ''} ${isHoveredPair(row, col) ? 'matrix-hover-pair' : ''}`}
+                    onMouseEnter={() => setHoveredCell([row, col])}
+                    onMouseLeave={() => setHoveredCell(null)}
+                  >
+                    {row === col ? '-' : data[row][col]}
+                  </td>
                 ))}
               </tr>
-            </thead>
-            <tbody>
-              {candidates.map(rowCandidate => (
-                <tr key={rowCandidate}>
-                  <td className="matrix-label">{candidatesData[rowCandidate]?.name || `К${rowCandidate}`}</td>
-                  {candidates.map(colCandidate => (
-                    <td 
-                      key={colCandidate} 
-                      className={`matrix-cell ${shouldHighlight(rowCandidate, colCandidate) ? 'matrix-highlight' : ''} ${isHoveredPair(rowCandidate, colCandidate) ? 'matrix-hover-pair' : ''}`}
-                      onMouseEnter={() => setHoveredCell([rowCandidate, colCandidate])}
-                      onMouseLeave={() => setHoveredCell(null)}
-                    >
-                      {matrix[rowCandidate] && matrix[rowCandidate][colCandidate] !== undefined 
-                        ? matrix[rowCandidate][colCandidate] 
-                        : '-'}
-                    </td>
-                  ))}
-                </tr>
-              ))}
-            </tbody>
-          </table>
-        </div>
-      );
-    } catch (error) {
-      return <div className="matrix-error">Ошибка отображения матрицы</div>;
-    }
+            ))}
+          </tbody>
+        </table>
+      </div>
+    );
   };
 
   const loadData = async () => {
@@ -93,7 +75,7 @@ function App() {
       const [votesRes, candidatesRes, resultsRes] = await Promise.all([
         axios.get('/election_bot/votes'),
         axios.get('/election_bot/candidates'),
-        axios.get('/election_bot/result')
+        axios.get('/election_bot/api/v2/results')
       ]);
       
       const sortedVotes = votesRes.data.sort((a, b) => 
@@ -110,8 +92,8 @@ function App() {
       });
       setCandidates(candidatesMap);
       
-      const sortedResults = resultsRes.data.sort((a, b) => a.course.localeCompare(b.course));
-      setResults(sortedResults);
+      // API v2 уже возвращает результаты в нужном порядке
+      setResults(resultsRes.data);
       
       setLoading(false);
     } catch (err) {
@@ -246,15 +228,15 @@ function App() {
                     <div className="info-item">
                       <span className="info-label">Победители:</span>
                       <div className="winners-list">
-                        {result.winner_candidate_id.map((candidateId, idx) => (
-                          <span key={idx} className="winner-badge">
-                            {candidates[candidateId]?.name || `#${candidateId}`}
+                        {result.winners.map((winner) => (
+                          <span key={winner.candidate_id} className="winner-badge" title={`${winner.name}, ${winner.course}`}>
+                            {winner.name || `#${winner.candidate_id}`}
                           </span>
                         ))}
                       </div>
                     </div>
                     <div className="info-item">
-                      <span className="result-stage">{result.stage}</span>
+                      <span className="result-stage" title={result.stage_description}>{result.stage}</span>
                     </div>
                   </div>
                 </div>
@@ -263,7 +245,7 @@ function App() {
                   <div className="result-matrix">
                     <h4>Парные предпочтения:</h4>
                     <div className="matrix-container">
-                      <MatrixTable data={result.preferences} type="preferences" candidates={candidates} />
+                      <MatrixTable data={result.preferences} order={result.candidate_order} type="preferences" />
                     </div>
                   </div>
                 )}
@@ -272,7 +254,7 @@ function App() {
                   <div className="result-matrix">
                     <h4>Сильнейшие пути:</h4>
                     <div className="matrix-container">
-                      <MatrixTable data={result.strongest_paths} type="strongest_paths" candidates={candidates} />
+                      <MatrixTable data={result.strongest_paths} order={result.candidate_order} type="strongest_paths" />
                     </div>
                   </div>
                 )}
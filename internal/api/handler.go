@@ -8,17 +8,25 @@ import (
 
 type Handler struct {
 	voteChain voteChain
+	schulze   schulze
 }
 
-func NewHandler(voteChain voteChain) *Handler {
+func NewHandler(voteChain voteChain, schulze schulze) *Handler {
 	return &Handler{
 		voteChain: voteChain,
+		schulze:   schulze,
 	}
 }
 
 type voteChain interface {
 	GetAllVotes(ctx context.Context) ([]models.Vote, error)
+	GetVoteWithReceipt(ctx context.Context, delegateID int) (*models.Vote, *models.VoteReceipt, error)
 	GetAllDelegates(ctx context.Context) ([]models.Delegate, error)
+	GetAllProxies(ctx context.Context) ([]models.Proxy, error)
 	GetAllCandidates(ctx context.Context) ([]models.Candidate, error)
 	GetAllResults(ctx context.Context) ([]models.Result, error)
 }
+
+type schulze interface {
+	BuildProtocolPDF(ctx context.Context) ([]byte, error)
+}
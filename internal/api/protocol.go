@@ -0,0 +1,54 @@
+package api
+
+import (
+	"context"
+	"crypto/subtle"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/lsdpls/schulze_election_telegram_bot/internal/config"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// GetProtocol отдает PDF протокол по токену PROTOCOL_API_TOKEN в заголовке Authorization: Bearer.
+// Как и команда /protocol, доступен только комиссии; без токена в конфигурации адрес отключен
+func (h *Handler) GetProtocol(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if config.ProtocolAPIToken == "" {
+		http.NotFound(w, r)
+		return
+	}
+	if !protocolAuthorized(r, config.ProtocolAPIToken) {
+		log.Warnf("Unauthorized protocol request from %s", r.RemoteAddr)
+		w.Header().Set("WWW-Authenticate", "Bearer")
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	ctx := context.Background()
+
+	protocol, err := h.schulze.BuildProtocolPDF(ctx)
+	if err != nil {
+		log.Errorf("Failed to build protocol: %v", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/pdf")
+	w.Header().Set("Content-Disposition", `inline; filename="protocol.pdf"`)
+	w.Header().Set("Content-Length", strconv.Itoa(len(protocol)))
+	if _, err := w.Write(protocol); err != nil {
+		log.Errorf("Failed to write protocol: %v", err)
+	}
+}
+
+// protocolAuthorized сравнивает токен из заголовка Authorization с ожидаемым за постоянное время
+func protocolAuthorized(r *http.Request, token string) bool {
+	got, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	return ok && subtle.ConstantTimeCompare([]byte(got), []byte(token)) == 1
+}
@@ -0,0 +1,35 @@
+package api
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProtocolAuthorized(t *testing.T) {
+	t.Parallel()
+
+	const token = "0123456789abcdef0123456789abcdef"
+	tests := []struct {
+		name   string
+		header string
+		want   bool
+	}{
+		{name: "Valid", header: "Bearer " + token, want: true},
+		{name: "WrongToken", header: "Bearer " + token[1:], want: false},
+		{name: "NoScheme", header: token, want: false},
+		{name: "Missing", header: "", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			r := httptest.NewRequest("GET", "/protocol", nil)
+			if tt.header != "" {
+				r.Header.Set("Authorization", tt.header)
+			}
+			assert.Equal(t, tt.want, protocolAuthorized(r, token))
+		})
+	}
+}
@@ -0,0 +1,157 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/lsdpls/schulze_election_telegram_bot/internal/models"
+	schulzepkg "github.com/lsdpls/schulze_election_telegram_bot/internal/schulze"
+
+	log "github.com/sirupsen/logrus"
+)
+
+type CandidateRef struct {
+	CandidateID int    `json:"candidate_id"`
+	Name        string `json:"name"`
+	Course      string `json:"course"`
+}
+
+type RankingEntry struct {
+	Rank      int          `json:"rank"`      // Место (у кандидатов в ничьей одинаковое место)
+	Wins      int          `json:"wins"`      // Количество побед по сильнейшим путям
+	Candidate CandidateRef `json:"candidate"` // Кандидат
+}
+
+type ResultV2Response struct {
+	Course           string         `json:"course"`
+	Stage            string         `json:"stage"`
+	StageDescription string         `json:"stage_description"`
+	Winners          []CandidateRef `json:"winners"`
+	Ranking          []RankingEntry `json:"ranking"`
+	CandidateOrder   []CandidateRef `json:"candidate_order"` // Порядок строк и столбцов матриц
+	Preferences      [][]int        `json:"preferences"`     // d[i][j] в порядке candidate_order, диагональ равна 0
+	StrongestPaths   [][]int        `json:"strongest_paths"` // p[i][j] в порядке candidate_order, диагональ равна 0
+	ComputedAt       time.Time      `json:"computed_at"`
+}
+
+func (h *Handler) GetResultsV2(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	ctx := context.Background()
+
+	results, err := h.voteChain.GetAllResults(ctx)
+	if err != nil {
+		log.Errorf("Failed to get results: %v", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	candidates, err := h.voteChain.GetAllCandidates(ctx)
+	if err != nil {
+		log.Errorf("Failed to get candidates: %v", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	candidateMap := make(map[int]CandidateRef, len(candidates))
+	for _, candidate := range candidates {
+		candidateMap[candidate.CandidateID] = CandidateRef{
+			CandidateID: candidate.CandidateID,
+			Name:        candidate.Name,
+			Course:      candidate.Course,
+		}
+	}
+	lookup := func(candidateID int) CandidateRef {
+		if candidate, ok := candidateMap[candidateID]; ok {
+			return candidate
+		}
+		return CandidateRef{CandidateID: candidateID}
+	}
+
+	// Общие места выводим после результатов по курсам
+	sort.SliceStable(results, func(i, j int) bool {
+		if (results[i].Stage == "common") != (results[j].Stage == "common") {
+			return results[j].Stage == "common"
+		}
+		return results[i].Course < results[j].Course
+	})
+
+	response := make([]ResultV2Response, 0, len(results))
+	for _, result := range results {
+		order := make([]int, 0, len(result.Preferences))
+		for candidateID := range result.Preferences {
+			order = append(order, candidateID)
+		}
+		slices.Sort(order)
+
+		candidateOrder := make([]CandidateRef, 0, len(order))
+		for _, candidateID := range order {
+			candidateOrder = append(candidateOrder, lookup(candidateID))
+		}
+		winners := make([]CandidateRef, 0, len(result.WinnerCandidateID))
+		for _, candidateID := range result.WinnerCandidateID {
+			winners = append(winners, lookup(candidateID))
+		}
+
+		response = append(response, ResultV2Response{
+			Course:           result.Course,
+			Stage:            result.Stage,
+			StageDescription: schulzepkg.StageDescription(result.Stage),
+			Winners:          winners,
+			Ranking:          buildRanking(result, order, lookup),
+			CandidateOrder:   candidateOrder,
+			Preferences:      matrixToSlice(result.Preferences, order),
+			StrongestPaths:   matrixToSlice(result.StrongestPaths, order),
+			ComputedAt:       result.ComputedAt,
+		})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		log.Errorf("Failed to encode response: %v", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+}
+
+// buildRanking строит полный рейтинг кандидатов в порядке метода Шульце (schulze.Ranking): кандидаты одной группы
+// делят место. Wins — число побед по сильнейшим путям, справочно
+func buildRanking(result models.Result, order []int, lookup func(int) CandidateRef) []RankingEntry {
+	wins := make(map[int]int, len(order))
+	for _, a := range order {
+		for _, b := range order {
+			if a != b && result.StrongestPaths[a][b] > result.StrongestPaths[b][a] {
+				wins[a]++
+			}
+		}
+	}
+
+	ranking := make([]RankingEntry, 0, len(order))
+	for _, group := range schulzepkg.Ranking(result) {
+		rank := len(ranking) + 1
+		for _, candidateID := range group {
+			ranking = append(ranking, RankingEntry{Rank: rank, Wins: wins[candidateID], Candidate: lookup(candidateID)})
+		}
+	}
+	return ranking
+}
+
+// matrixToSlice преобразует матрицу кандидатов в двумерный массив в заданном порядке
+func matrixToSlice(matrix map[int]map[int]int, order []int) [][]int {
+	rows := make([][]int, 0, len(order))
+	for _, rowID := range order {
+		row := make([]int, 0, len(order))
+		for _, colID := range order {
+			row = append(row, matrix[rowID][colID])
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
@@ -0,0 +1,125 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/lsdpls/schulze_election_telegram_bot/internal/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// rank — ожидаемое место кандидата
+type rank struct {
+	candidateID int
+	rank        int
+}
+
+func TestBuildRanking(t *testing.T) {
+	t.Parallel()
+
+	// 1 побеждает 2 и 3, 2 побеждает 3
+	strict := map[int]map[int]int{
+		1: {2: 5, 3: 6},
+		2: {1: 3, 3: 4},
+		3: {1: 2, 2: 1},
+	}
+	// 1 и 2 равны, 2 и 3 равны, 1 побеждает 3: счет побед ставит 2 ниже 1, а по Шульце они делят место
+	incomparable := map[int]map[int]int{
+		1: {2: 4, 3: 5},
+		2: {1: 4, 3: 3},
+		3: {1: 2, 2: 3},
+	}
+	tests := []struct {
+		name   string
+		result models.Result
+		want   []rank
+	}{
+		{
+			name:   "Absolute",
+			result: models.Result{Stage: "absolute", WinnerCandidateID: []int{1}, StrongestPaths: strict},
+			want:   []rank{{1, 1}, {2, 2}, {3, 3}},
+		},
+		{
+			name:   "TieShareFirstPlace",
+			result: models.Result{Stage: "tie", WinnerCandidateID: []int{1, 2}, StrongestPaths: incomparable},
+			want:   []rank{{1, 1}, {2, 1}, {3, 3}},
+		},
+		{
+			name:   "TieBreakerWinnerFirst",
+			result: models.Result{Stage: "tie-breaker", WinnerCandidateID: []int{2}, StrongestPaths: incomparable},
+			want:   []rank{{2, 1}, {1, 2}, {3, 3}},
+		},
+		{
+			name: "TieBelowWinner",
+			result: models.Result{Stage: "absolute", WinnerCandidateID: []int{1}, StrongestPaths: map[int]map[int]int{
+				1: {2: 5, 3: 5},
+				2: {1: 2, 3: 3},
+				3: {1: 2, 2: 3},
+			}},
+			want: []rank{{1, 1}, {2, 2}, {3, 2}},
+		},
+		{
+			name:   "CommonStrictOrder",
+			result: models.Result{Stage: "common", WinnerCandidateID: []int{3, 1}, StrongestPaths: strict},
+			want:   []rank{{3, 1}, {1, 2}, {2, 3}},
+		},
+		{
+			// Снятый кандидат 4 не участвовал в подсчете: его нет в матрицах и рейтинге, даже если он в победителях
+			name:   "BannedCandidate",
+			result: models.Result{Stage: "absolute", WinnerCandidateID: []int{4, 1}, StrongestPaths: strict},
+			want:   []rank{{1, 1}, {2, 2}, {3, 3}},
+		},
+		{
+			name:   "Empty",
+			result: models.Result{Stage: "absolute"},
+			want:   []rank{},
+		},
+	}
+
+	lookup := func(candidateID int) CandidateRef {
+		return CandidateRef{CandidateID: candidateID, Name: "candidate"}
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			order := []int{1, 2, 3}
+			if len(tt.result.StrongestPaths) == 0 {
+				order = nil
+			}
+			got := make([]rank, 0, len(order))
+			for _, entry := range buildRanking(tt.result, order, lookup) {
+				assert.Equal(t, "candidate", entry.Candidate.Name)
+				got = append(got, rank{entry.Candidate.CandidateID, entry.Rank})
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestMatrixToSlice(t *testing.T) {
+	t.Parallel()
+
+	matrix := map[int]map[int]int{
+		1: {2: 5, 3: 6},
+		2: {1: 3, 3: 4},
+		3: {1: 2},
+	}
+	tests := []struct {
+		name  string
+		order []int
+		want  [][]int
+	}{
+		{name: "Sorted", order: []int{1, 2, 3}, want: [][]int{{0, 5, 6}, {3, 0, 4}, {2, 0, 0}}},
+		{name: "Reordered", order: []int{3, 1}, want: [][]int{{0, 2}, {6, 0}}},
+		{name: "UnknownCandidate", order: []int{1, 4}, want: [][]int{{0, 0}, {0, 0}}},
+		{name: "Empty", order: nil, want: [][]int{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.want, matrixToSlice(matrix, tt.order))
+		})
+	}
+}
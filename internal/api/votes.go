@@ -3,8 +3,10 @@ package api
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
 
+	"github.com/lsdpls/schulze_election_telegram_bot/internal/models"
 	"github.com/lsdpls/schulze_election_telegram_bot/internal/utils"
 
 	log "github.com/sirupsen/logrus"
@@ -16,6 +18,23 @@ type VoteResponse struct {
 	CreatedAt         string `json:"created_at"`
 }
 
+// VoteCheckResponse — бюллетень по токену и результат сверки с бюллетенем, который подтвердил бот
+type VoteCheckResponse struct {
+	VoteResponse
+	ByProxy           bool   `json:"by_proxy"`                     // Голос подан заместителем по доверенности
+	Receipt           string `json:"receipt"`                      // matches, mismatch или unconfirmed
+	ConfirmedRankings []int  `json:"confirmed_rankings,omitempty"` // Ранжирование, которое подтвердил бот
+	ConfirmedAt       string `json:"confirmed_at,omitempty"`       // Время подтверждения
+	ConfirmedToken    string `json:"confirmed_token,omitempty"`    // Токен аккаунта, которому бот отправил подтверждение
+	Warning           string `json:"warning,omitempty"`            // Предупреждение, если голос не совпадает с подтвержденным
+}
+
+// Предупреждения сверки бюллетеня
+var receiptWarnings = map[string]string{
+	models.ReceiptMismatch:    "The stored ballot differs from the last ballot confirmed by the bot",
+	models.ReceiptUnconfirmed: "No delivered confirmation was recorded for this ballot",
+}
+
 func (h *Handler) GetVotes(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
@@ -30,14 +49,105 @@ func (h *Handler) GetVotes(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "Internal server error", http.StatusInternalServerError)
 		return
 	}
+	tokens, err := h.voteTokens(ctx, votes)
+	if err != nil {
+		log.Errorf("Failed to get vote tokens: %v", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
 
-	delegates, err := h.voteChain.GetAllDelegates(ctx)
+	response := make([]VoteResponse, 0, len(votes))
+	for _, vote := range votes {
+		voteToken, ok := tokens[vote.ID]
+		if !ok {
+			continue
+		}
+		response = append(response, voteResponse(vote, voteToken))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		log.Errorf("Failed to encode response: %v", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+}
+
+// GetVote возвращает бюллетень по токену голоса и сверяет его с бюллетенем, который подтвердил бот
+func (h *Handler) GetVote(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	ctx := context.Background()
+	token := r.PathValue("token")
+
+	votes, err := h.voteChain.GetAllVotes(ctx)
+	if err != nil {
+		log.Errorf("Failed to get votes: %v", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	tokens, err := h.voteTokens(ctx, votes)
+	if err != nil {
+		log.Errorf("Failed to get vote tokens: %v", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	var found *models.Vote
+	for i, vote := range votes {
+		if tokens[vote.ID] == token {
+			found = &votes[i]
+			break
+		}
+	}
+	if found == nil {
+		http.Error(w, "Vote not found", http.StatusNotFound)
+		return
+	}
+
+	vote, receipt, err := h.voteChain.GetVoteWithReceipt(ctx, found.DelegateID)
 	if err != nil {
-		log.Errorf("Failed to get delegates: %v", err)
+		log.Errorf("Failed to get vote receipt: %v", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	// Голос могли удалить или переподать с другого аккаунта между запросами: токен больше не указывает на него
+	if vote == nil || vote.ProxyID != found.ProxyID {
+		http.Error(w, "Vote not found", http.StatusNotFound)
+		return
+	}
+	response := VoteCheckResponse{
+		VoteResponse: voteResponse(*vote, token),
+		ByProxy:      vote.ProxyID.Valid,
+		Receipt:      receipt.Check(vote),
+	}
+	if receipt != nil {
+		response.ConfirmedRankings = receipt.CandidateRankings
+		response.ConfirmedAt = receipt.ConfirmedAt.Format("2006-01-02 15:04:05")
+		response.ConfirmedToken = utils.GenerateVoteToken(receipt.TelegramID)
+	}
+	response.Warning = receiptWarnings[response.Receipt]
+	if response.Receipt == models.ReceiptMismatch {
+		log.Warnf("Vote of delegate %d differs from the confirmed ballot", vote.DelegateID)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		log.Errorf("Failed to encode response: %v", err)
 		http.Error(w, "Internal server error", http.StatusInternalServerError)
 		return
 	}
+}
 
+// voteTokens возвращает токены голосов по ID голоса. Токен вычисляется из Telegram ID того,
+// кто голосовал: делегата или заместителя. Голоса без привязанного аккаунта пропускаются
+func (h *Handler) voteTokens(ctx context.Context, votes []models.Vote) (map[int]string, error) {
+	delegates, err := h.voteChain.GetAllDelegates(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("voteTokens: %w", err)
+	}
 	delegateMap := make(map[int]int64)
 	for _, delegate := range delegates {
 		if delegate.TelegramID.Valid {
@@ -45,26 +155,36 @@ func (h *Handler) GetVotes(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	response := make([]VoteResponse, 0, len(votes))
+	proxies, err := h.voteChain.GetAllProxies(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("voteTokens: %w", err)
+	}
+	proxyMap := make(map[int]int64)
+	for _, proxy := range proxies {
+		if proxy.TelegramID.Valid {
+			proxyMap[proxy.ID] = proxy.TelegramID.Int64
+		}
+	}
+
+	tokens := make(map[int]string, len(votes))
 	for _, vote := range votes {
 		telegramID, ok := delegateMap[vote.DelegateID]
+		if vote.ProxyID.Valid {
+			telegramID, ok = proxyMap[int(vote.ProxyID.Int64)]
+		}
 		if !ok {
 			log.Warnf("Delegate %d has no telegram ID", vote.DelegateID)
 			continue
 		}
-
-		voteToken := utils.GenerateVoteToken(telegramID)
-		response = append(response, VoteResponse{
-			VoteToken:         voteToken,
-			CandidateRankings: vote.CandidateRankings,
-			CreatedAt:         vote.CreatedAt.Format("2006-01-02 15:04:05"),
-		})
+		tokens[vote.ID] = utils.GenerateVoteToken(telegramID)
 	}
+	return tokens, nil
+}
 
-	w.Header().Set("Content-Type", "application/json")
-	if err := json.NewEncoder(w).Encode(response); err != nil {
-		log.Errorf("Failed to encode response: %v", err)
-		http.Error(w, "Internal server error", http.StatusInternalServerError)
-		return
+func voteResponse(vote models.Vote, voteToken string) VoteResponse {
+	return VoteResponse{
+		VoteToken:         voteToken,
+		CandidateRankings: vote.CandidateRankings,
+		CreatedAt:         vote.CreatedAt.Format("2006-01-02 15:04:05"),
 	}
 }
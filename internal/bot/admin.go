@@ -3,186 +3,203 @@ package bot
 import (
 	"context"
 	"fmt"
+	"html"
 	"io"
+	"net/url"
 	"os"
 	"path/filepath"
 	"regexp"
+	"slices"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/lsdpls/schulze_election_telegram_bot/internal/models"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 )
 
-// Обработчик команды /help
-func (b *Bot) handleHelpAdmin(_ context.Context, message *tgbotapi.Message) {
-	chatID := message.Chat.ID
-	msg := tgbotapi.NewMessage(chatID, "Список доступных команд:\n"+
-		"/add_delegate <delegate_id> <name> <group> - добавить делегата\n"+
-		"/delete_delegate <delegate_id> - удалить делегата\n"+
-		"/add_candidate <candidate_id> <name> <course> <description> - добавить кандидата\n"+
-		"/ban_candidate <candidate_id> - заблокировать кандидата\n"+
-		"/delete_candidate <candidate_id> - удалить кандидата\n"+
-		"/show_delegates - показать список делегатов\n"+
-		"/show_candidates - показать список кандидатов\n"+
-		"/show_votes - показать список голосов\n"+
-		"/start_voting - начать голосование\n"+
-		"/stop_voting - остановить голосование\n"+
-		"/results - вычислить результаты голосования\n"+
-		"/print - вывести результаты голосования\n"+
-		"/csv - сохранить результаты в CSV файл\n"+
-		"/log <level> - установить уровень логирования (Debug, Info, Warn, Error)\n"+
-		"/send_logs - отправить файл логов\n"+
-		"/help - показать список доступных команд\n"+
-		"\nВсегда используйте запятые между аргументами команды, если идет перечисление аргументов")
-	b.botAPI.Send(msg)
-}
-
 // Обработчик команды /add_delegate
 func (b *Bot) handleAddDelegate(ctx context.Context, message *tgbotapi.Message) {
 	chatID := message.Chat.ID
-	delegateMsg := message.CommandArguments()
-
-	// Разделяем сообщение на части
-	parts := strings.Split(delegateMsg, ",")
-	if len(parts) != 3 {
-		log.Warn(chatID, " Неверный формат команды. Используйте: /add_delegate [delegate_id], [name], [group]")
-		return
-	}
-	for part := range parts {
-		parts[part] = strings.TrimSpace(parts[part])
-	}
-
-	// Извлекаем данные из частей сообщения
-	delegateID, err := strconv.Atoi(parts[0])
-	if err != nil || !isValidID(parts[0]) {
-		log.Warn(chatID, " Неверный формат delegate_id. Используйте целое шестизначное число.")
-		return
-	}
-	name := parts[1]
-	group := parts[2]
+	args := commandArgsFromContext(ctx)
 
 	// Создаем делегата
 	delegate := models.Delegate{
-		DelegateID: delegateID,
-		Name:       name,
-		Group:      group,
+		DelegateID: args.number("delegate_id"),
+		Name:       args.text("name"),
+		Group:      args.text("group"),
 		HasVoted:   false,
 	}
 
 	// Добавляем делегата в базу данных
 	if err := b.voteChain.AddDelegate(ctx, delegate); err != nil {
 		log.Errorf("%d Ошибка при добавлении делегата: %v", chatID, err)
+		b.SendMessage(chatID, tr(ctx, "error.generic"))
 		return
 	}
 	log.Info(chatID, " Делегат успешно добавлен")
+	b.SendMessage(chatID, tr(ctx, "admin.delegate_added", delegate.DelegateID))
 }
 
 // Обработчик команды /delete_delegate
 func (b *Bot) handleDeleteDelegate(ctx context.Context, message *tgbotapi.Message) {
 	chatID := message.Chat.ID
-	delegateMsg := message.CommandArguments()
+	delegateID := commandArgsFromContext(ctx).number("delegate_id")
 
-	// Извлекаем ID делегата из сообщения
-	delegateID, err := strconv.Atoi(delegateMsg)
-	if err != nil || !isValidID(delegateMsg) {
-		log.Warn(chatID, " Неверный формат delegate_id. Используйте целое шестизначное число.")
+	// Удаление делегата каскадно удаляет его голос, поэтому сначала показываем, что будет удалено
+	delegate, err := b.voteChain.GetDelegateByDelegateID(ctx, delegateID)
+	if err != nil {
+		log.Errorf("%d Ошибка при получении делегата: %v", chatID, err)
 		return
 	}
-
-	// Удаляем делегата из базы данных
-	if err := b.voteChain.DeleteDelegate(ctx, delegateID); err != nil {
-		log.Errorf("%d Ошибка при удалении делегата: %v", chatID, err)
+	if delegate == nil {
+		b.SendMessage(chatID, tr(ctx, "admin.delegate_not_found", delegateID))
 		return
 	}
-	log.Info(chatID, " Делегат успешно удален")
+	summary := tr(ctx, "confirm.delete_delegate", delegateID, html.EscapeString(delegate.Name), html.EscapeString(delegate.Group))
+	if delegate.TelegramID.Valid {
+		summary += "\n" + tr(ctx, "confirm.delegate_registered", delegate.TelegramID.Int64, delegate.TelegramID.Int64)
+	} else {
+		summary += "\n" + tr(ctx, "confirm.delegate_not_registered")
+	}
+	if delegate.HasVoted {
+		summary += "\n" + tr(ctx, "confirm.delegate_has_vote")
+	} else {
+		summary += "\n" + tr(ctx, "confirm.delegate_no_vote")
+	}
+	b.askConfirmation(ctx, message, confirmationSession{Command: "delete_delegate", TargetID: delegateID, HasVote: delegate.HasVoted}, summary)
 }
 
 // Обработчик команды /add_candidate
 func (b *Bot) handleAddCandidate(ctx context.Context, message *tgbotapi.Message) {
 	chatID := message.Chat.ID
-	candidateMsg := message.CommandArguments()
-
-	// Разделяем сообщение на части
-	parts := strings.Split(candidateMsg, ",")
-	if len(parts) != 4 {
-		log.Warn(chatID, " Неверный формат команды. Используйте: /add_candidate [candidate_id], [name], [course], [description]")
-		return
-	}
-	for part := range parts {
-		parts[part] = strings.TrimSpace(parts[part])
-	}
-
-	// Извлекаем данные из частей сообщения
-	candidateID, err := strconv.Atoi(parts[0])
-	if err != nil || !isValidID(parts[0]) {
-		log.Warn(chatID, " Неверный формат candidate_id. Используйте целое шестизначное число.")
-		return
-	}
-	name := parts[1]
-	course := parts[2]
-	if !isValidCourse(course) {
-		log.Warn(chatID, " Неверный формат course. Используйте: 1 бакалавриат/магистратура")
-		return
-	}
-	description := parts[3]
+	args := commandArgsFromContext(ctx)
 
 	// Создаем кандидата
 	candidate := models.Candidate{
-		CandidateID: candidateID,
-		Name:        name,
-		Course:      course,
-		Description: description,
+		CandidateID: args.number("candidate_id"),
+		Name:        args.text("name"),
+		Course:      args.text("course"),
+		Description: args.text("description"),
 		IsEligible:  true,
 	}
 
 	// Добавляем кандидата в базу данных
 	if err := b.voteChain.AddCandidate(ctx, candidate); err != nil {
 		log.Errorf("%d Ошибка при добавлении кандидата: %v", chatID, err)
+		b.SendMessage(chatID, tr(ctx, "error.generic"))
 		return
 	}
 	log.Info(chatID, " Кандидат успешно добавлен")
+	b.SendMessage(chatID, tr(ctx, "admin.candidate_added", candidate.CandidateID))
 }
 
 // Обработчик команды /ban_candidate
 func (b *Bot) handleBanCandidate(ctx context.Context, message *tgbotapi.Message) {
 	chatID := message.Chat.ID
-	candidateMsg := message.CommandArguments()
-
-	// Извлекаем ID кандидата из сообщения
-	candidateID, err := strconv.Atoi(candidateMsg)
-	if err != nil || !isValidID(candidateMsg) {
-		log.Warn(chatID, " Неверный формат candidate_id. Используйте целое шестизначное число.")
-		return
-	}
+	candidateID := commandArgsFromContext(ctx).number("candidate_id")
 
 	// Запрещаем кандидата
 	if err := b.voteChain.BanCandidate(ctx, candidateID); err != nil {
 		log.Errorf("%d Ошибка при запрете кандидата: %v", chatID, err)
+		b.SendMessage(chatID, tr(ctx, "error.generic"))
 		return
 	}
 	log.Info(chatID, " Кандидат успешно заблокирован")
+	b.SendMessage(chatID, tr(ctx, "admin.candidate_banned", candidateID))
+}
+
+// Обработчик команды /set_photo: ответ на сообщение с фото
+func (b *Bot) handleSetPhoto(ctx context.Context, message *tgbotapi.Message) {
+	chatID := message.Chat.ID
+	candidateID := commandArgsFromContext(ctx).number("candidate_id")
+
+	// Берем фото наибольшего размера из сообщения, на которое ответили командой
+	if message.ReplyToMessage == nil || len(message.ReplyToMessage.Photo) == 0 {
+		b.SendMessage(chatID, tr(ctx, "admin.photo_reply_required"))
+		return
+	}
+	photos := message.ReplyToMessage.Photo
+	photoFileID := photos[len(photos)-1].FileID
+
+	if err := b.voteChain.SetCandidatePhoto(ctx, candidateID, photoFileID); err != nil {
+		log.Errorf("%d Ошибка при сохранении фото кандидата: %v", chatID, err)
+		b.SendMessage(chatID, tr(ctx, "error.generic"))
+		return
+	}
+	log.Info(chatID, " Фото кандидата сохранено")
+	b.SendMessage(chatID, tr(ctx, "admin.photo_saved", candidateID))
+}
+
+// Обработчик команды /set_manifesto
+func (b *Bot) handleSetManifesto(ctx context.Context, message *tgbotapi.Message) {
+	chatID := message.Chat.ID
+	args := commandArgsFromContext(ctx)
+	candidateID := args.number("candidate_id")
+
+	// Без ссылки программа кандидата удаляется
+	manifestoURL := args.text("url")
+
+	if err := b.voteChain.SetCandidateManifesto(ctx, candidateID, manifestoURL); err != nil {
+		log.Errorf("%d Ошибка при сохранении программы кандидата: %v", chatID, err)
+		b.SendMessage(chatID, tr(ctx, "error.generic"))
+		return
+	}
+	if manifestoURL == "" {
+		log.Info(chatID, " Ссылка на программу кандидата удалена")
+		b.SendMessage(chatID, tr(ctx, "admin.manifesto_removed", candidateID))
+		return
+	}
+	log.Info(chatID, " Ссылка на программу кандидата сохранена")
+	b.SendMessage(chatID, tr(ctx, "admin.manifesto_saved", candidateID))
+}
+
+// Обработчик команды /unlock
+func (b *Bot) handleUnlock(ctx context.Context, message *tgbotapi.Message) {
+	chatID := message.Chat.ID
+	telegramID := commandArgsFromContext(ctx).telegramID("telegram_id")
+
+	// Сбрасываем счетчики попыток и блокировку верификации
+	if err := b.clearVerification(ctx, telegramID); err != nil {
+		log.Errorf("%d Ошибка при снятии блокировки верификации: %v", chatID, err)
+		b.SendMessage(chatID, tr(ctx, "error.generic"))
+		return
+	}
+	log.Infof("%d Блокировка верификации пользователя %d снята", chatID, telegramID)
+	b.SendMessage(chatID, tr(ctx, "admin.unlocked", telegramID))
 }
 
 // Обработчик команды /delete_candidate
 func (b *Bot) handleDeleteCandidate(ctx context.Context, message *tgbotapi.Message) {
 	chatID := message.Chat.ID
-	candidateMsg := message.CommandArguments()
+	candidateID := commandArgsFromContext(ctx).number("candidate_id")
 
-	// Извлекаем ID кандидата из сообщения
-	candidateID, err := strconv.Atoi(candidateMsg)
-	if err != nil || !isValidID(candidateMsg) {
-		log.Warn(chatID, " Неверный формат candidate_id. Используйте целое шестизначное число.")
+	// Показываем, кто будет удален и в скольких бюллетенях он указан
+	candidates, err := b.voteChain.GetAllCandidates(ctx)
+	if err != nil {
+		log.Errorf("%d Ошибка при получении списка кандидатов: %v", chatID, err)
 		return
 	}
-
-	// Удаляем кандидата
-	if err := b.voteChain.DeleteCandidate(ctx, candidateID); err != nil {
-		log.Errorf("%d Ошибка при удалении кандидата: %v", chatID, err)
+	index := slices.IndexFunc(candidates, func(candidate models.Candidate) bool { return candidate.CandidateID == candidateID })
+	if index < 0 {
+		b.SendMessage(chatID, tr(ctx, "admin.candidate_not_found", candidateID))
+		return
+	}
+	candidate := candidates[index]
+	votes, err := b.voteChain.GetAllVotes(ctx)
+	if err != nil {
+		log.Errorf("%d Ошибка при получении голосов: %v", chatID, err)
 		return
 	}
-	log.Info(chatID, " Кандидат успешно удален")
+	ballots := models.CountBallots(votes, candidateID)
+	summary := tr(ctx, "confirm.delete_candidate", candidateID, html.EscapeString(candidate.Name), html.EscapeString(candidate.Course))
+	if ballots > 0 {
+		summary += "\n" + trN(ctx, "confirm.candidate_ballots", ballots, ballots)
+	} else {
+		summary += "\n" + tr(ctx, "confirm.candidate_no_ballots")
+	}
+	b.askConfirmation(ctx, message, confirmationSession{Command: "delete_candidate", TargetID: candidateID, Ballots: ballots}, summary)
 }
 
 // Обработчик команды /show_delegates
@@ -194,7 +211,7 @@ func (b *Bot) handleShowDelegates(ctx context.Context, message *tgbotapi.Message
 		return
 	}
 
-	msgText := "Список делегатов:\n"
+	msgText := tr(ctx, "admin.delegates_header") + "\n"
 	for _, delegate := range delegates {
 		voteStatus := "❌" // Default to cross (not voted)
 		if delegate.HasVoted {
@@ -239,7 +256,7 @@ func (b *Bot) handleShowCandidates(ctx context.Context, message *tgbotapi.Messag
 		return
 	}
 
-	msgText := "Список кандидатов:\n"
+	msgText := tr(ctx, "admin.candidates_header") + "\n"
 	for _, candidate := range candidates {
 		eligibleStatus := "❌" // Default to cross (not eligible)
 		if candidate.IsEligible {
@@ -279,7 +296,13 @@ func (b *Bot) handleShowVotes(ctx context.Context, message *tgbotapi.Message) {
 		return
 	}
 
-	msgText := "Список голосов:\n"
+	// Содержимое бюллетеней видят только суперадминистраторы и счетная комиссия
+	if role := adminRoleFromContext(ctx); role != models.RoleSuperadmin && role != models.RoleTallyOfficer {
+		b.sendVoteCounts(ctx, chatID, len(votes))
+		return
+	}
+
+	msgText := tr(ctx, "admin.votes_header") + "\n"
 
 	// msg := tgbotapi.NewMessage(chatID, "Список голосов:\n")
 	for _, vote := range votes {
@@ -289,7 +312,11 @@ func (b *Bot) handleShowVotes(ctx context.Context, message *tgbotapi.Message) {
 			return
 		}
 		delegateIDStr := toStrDelegatID(strconv.Itoa(delegate.DelegateID))
-		voteInfo := fmt.Sprintf("• st%s, %s: %s\n", delegateIDStr, vote.CreatedAt.Format("15:04:05"), fmt.Sprint(vote.CandidateRankings))
+		voteInfo := fmt.Sprintf("• st%s, %s: %s", delegateIDStr, vote.CreatedAt.Format("15:04:05"), fmt.Sprint(vote.CandidateRankings))
+		if vote.ProxyID.Valid {
+			voteInfo += " " + tr(ctx, "admin.vote_by_proxy")
+		}
+		voteInfo += "\n"
 
 		// Check if adding the vote info exceeds the limit
 		if len(msgText)+len(voteInfo) > 4096 {
@@ -314,25 +341,48 @@ func (b *Bot) handleShowVotes(ctx context.Context, message *tgbotapi.Message) {
 
 }
 
+// sendVoteCounts отправляет количество голосов без содержимого бюллетеней
+func (b *Bot) sendVoteCounts(ctx context.Context, chatID int64, votesCount int) {
+	delegates, err := b.voteChain.GetAllDelegates(ctx)
+	if err != nil {
+		log.Errorf("%d Ошибка при получении списка делегатов: %v", chatID, err)
+		return
+	}
+	registered := 0
+	for _, delegate := range delegates {
+		if delegate.TelegramID.Valid {
+			registered++
+		}
+	}
+	b.SendMessage(chatID, tr(ctx, "admin.vote_counts", len(delegates), registered, votesCount))
+}
+
 // Обработчик команды /start_voting
-func (b *Bot) handleStartVoting(_ context.Context, message *tgbotapi.Message) {
+func (b *Bot) handleStartVoting(ctx context.Context, message *tgbotapi.Message) {
 	// Обновляем список кандидатов
 	if err := b.SetCandidates(); err != nil {
 		log.Errorf("%d Ошибка при обновлении списка кандидатов: %v", message.From.ID, err)
+		b.SendMessage(message.Chat.ID, tr(ctx, "error.generic"))
+		return
+	}
+	if err := b.setActiveVoting(ctx, true); err != nil {
+		log.Errorf("%d Ошибка при открытии голосования: %v", message.From.ID, err)
+		b.SendMessage(message.Chat.ID, tr(ctx, "error.generic"))
 		return
 	}
-	b.mu.Lock()
-	b.activeVoting = true
-	b.mu.Unlock()
 	log.Warn(message.From.ID, " Голосование открыто!")
+	b.SendMessage(message.Chat.ID, tr(ctx, "admin.voting_started"))
 }
 
 // Обработчик команды /stop_voting
-func (b *Bot) handleStopVoting(_ context.Context, message *tgbotapi.Message) {
-	b.mu.Lock()
-	b.activeVoting = false
-	b.mu.Unlock()
+func (b *Bot) handleStopVoting(ctx context.Context, message *tgbotapi.Message) {
+	if err := b.setActiveVoting(ctx, false); err != nil {
+		log.Errorf("%d Ошибка при закрытии голосования: %v", message.From.ID, err)
+		b.SendMessage(message.Chat.ID, tr(ctx, "error.generic"))
+		return
+	}
 	log.Warn(message.From.ID, " Голосование закрыто!")
+	b.SendMessage(message.Chat.ID, tr(ctx, "admin.voting_stopped"))
 }
 
 // Преобразование ID делегата в строку с ведущими нулями
@@ -375,6 +425,12 @@ func isValidCourse(course string) bool {
 	return re.MatchString(course)
 }
 
+// Проверка ссылки (абсолютный адрес http или https)
+func isValidURL(rawURL string) bool {
+	u, err := url.Parse(rawURL)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
 // Deprecated: Проверка формата группы (XX.(б|м)XX-пу)
 func isValidGroup(group string) bool {
 	// курс должен быть XX.(б|м)XX-пу
@@ -386,23 +442,36 @@ func isValidGroup(group string) bool {
 func (b *Bot) handleResults(ctx context.Context, message *tgbotapi.Message) {
 	if err := b.schulze.SetCandidates(); err != nil {
 		log.Errorf("%d %v", message.Chat.ID, err)
+		b.SendMessage(message.Chat.ID, tr(ctx, "admin.results_failed"))
+		return
 	}
 	if err := b.schulze.SetVotes(); err != nil {
 		log.Errorf("%d %v", message.Chat.ID, err)
+		b.SendMessage(message.Chat.ID, tr(ctx, "admin.results_failed"))
+		return
 	}
 	if err := b.schulze.SetCandidatesByCourse(); err != nil {
 		log.Errorf("%d %v", message.Chat.ID, err)
+		b.SendMessage(message.Chat.ID, tr(ctx, "admin.results_failed"))
+		return
 	}
 	if err := b.schulze.SetVotesByCourse(); err != nil {
 		log.Errorf("%d %v", message.Chat.ID, err)
+		b.SendMessage(message.Chat.ID, tr(ctx, "admin.results_failed"))
+		return
 	}
 	if err := b.schulze.ComputeResults(ctx); err != nil {
 		log.Errorf("%d %v", message.Chat.ID, err)
+		b.SendMessage(message.Chat.ID, tr(ctx, "admin.results_failed"))
+		return
 	}
 	if err := b.schulze.ComputeGlobalTop(ctx); err != nil {
 		log.Errorf("%d %v", message.Chat.ID, err)
+		b.SendMessage(message.Chat.ID, tr(ctx, "admin.results_failed"))
+		return
 	}
 	log.Info(message.Chat.ID, " Результаты успешно вычислены")
+	b.SendMessage(message.Chat.ID, tr(ctx, "admin.results_computed"))
 	// if err := b.schulze.SaveResultsToCSV(ctx); err != nil {
 	// 	log.Errorf("%d %v", message.Chat.ID, err)
 	// }
@@ -445,6 +514,7 @@ func (b *Bot) handlePrint(_ context.Context, message *tgbotapi.Message) {
 func (b *Bot) handleCSV(ctx context.Context, message *tgbotapi.Message) {
 	if err := b.schulze.SaveResultsToCSV(ctx); err != nil {
 		log.Errorf("%d Ошибка при записи в CSV: %v", message.Chat.ID, err)
+		b.SendMessage(message.Chat.ID, tr(ctx, "admin.export_failed"))
 		return
 	}
 	log.Info(message.Chat.ID, " Результаты успешно записаны в CSV файл")
@@ -454,6 +524,7 @@ func (b *Bot) handleCSV(ctx context.Context, message *tgbotapi.Message) {
 	file, err := os.OpenFile(filePath, os.O_RDWR|os.O_APPEND, 0644)
 	if err != nil {
 		log.Errorf("%d Ошибка при открытии файла: %v", message.Chat.ID, err)
+		b.SendMessage(message.Chat.ID, tr(ctx, "admin.export_failed"))
 		return
 	}
 	defer file.Close()
@@ -462,6 +533,7 @@ func (b *Bot) handleCSV(ctx context.Context, message *tgbotapi.Message) {
 	fileBytes, err := io.ReadAll(file)
 	if err != nil {
 		log.Errorf("%d Ошибка при чтении файла: %v", message.Chat.ID, err)
+		b.SendMessage(message.Chat.ID, tr(ctx, "admin.export_failed"))
 		return
 	}
 
@@ -475,16 +547,66 @@ func (b *Bot) handleCSV(ctx context.Context, message *tgbotapi.Message) {
 	_, err = b.botAPI.Send(msg)
 	if err != nil {
 		log.Errorf("%d Ошибка при отправке файла: %v", message.Chat.ID, err)
+		b.SendMessage(message.Chat.ID, tr(ctx, "admin.export_failed"))
+		return
+	}
+}
+
+// Обработчик команды /xlsx
+func (b *Bot) handleXLSX(ctx context.Context, message *tgbotapi.Message) {
+	workbook, err := b.schulze.BuildResultsXLSX(ctx)
+	if err != nil {
+		log.Errorf("%d Ошибка при формировании XLSX: %v", message.Chat.ID, err)
+		b.SendMessage(message.Chat.ID, tr(ctx, "admin.export_failed"))
+		return
+	}
+
+	// Создаем новое сообщение с документом
+	msg := tgbotapi.NewDocument(message.Chat.ID, tgbotapi.FileBytes{
+		Name:  fmt.Sprintf("results_%s.xlsx", time.Now().Format("2006-01-02_15-04")),
+		Bytes: workbook,
+	})
+
+	// Отправляем сообщение
+	if _, err := b.botAPI.Send(msg); err != nil {
+		log.Errorf("%d Ошибка при отправке XLSX: %v", message.Chat.ID, err)
+		b.SendMessage(message.Chat.ID, tr(ctx, "admin.export_failed"))
+		return
+	}
+	log.Info(message.Chat.ID, " Результаты выгружены в XLSX")
+}
+
+// Обработчик команды /protocol
+func (b *Bot) handleProtocol(ctx context.Context, message *tgbotapi.Message) {
+	protocol, err := b.schulze.BuildProtocolPDF(ctx)
+	if err != nil {
+		log.Errorf("%d Ошибка при формировании протокола: %v", message.Chat.ID, err)
+		b.SendMessage(message.Chat.ID, tr(ctx, "admin.export_failed"))
+		return
+	}
+
+	// Создаем новое сообщение с документом
+	msg := tgbotapi.NewDocument(message.Chat.ID, tgbotapi.FileBytes{
+		Name:  fmt.Sprintf("protocol_%s.pdf", time.Now().Format("2006-01-02_15-04")),
+		Bytes: protocol,
+	})
+
+	// Отправляем сообщение
+	if _, err := b.botAPI.Send(msg); err != nil {
+		log.Errorf("%d Ошибка при отправке протокола: %v", message.Chat.ID, err)
+		b.SendMessage(message.Chat.ID, tr(ctx, "admin.export_failed"))
 		return
 	}
+	log.Info(message.Chat.ID, " Протокол голосования сформирован")
 }
 
-func (b *Bot) handleSendLogs(_ context.Context, message *tgbotapi.Message) {
+func (b *Bot) handleSendLogs(ctx context.Context, message *tgbotapi.Message) {
 	// Открываем файл для чтения
 	filePath := filepath.Join("logs", "bot.log")
 	file, err := os.OpenFile(filePath, os.O_RDWR|os.O_APPEND, 0644)
 	if err != nil {
 		log.Errorf("%d Ошибка при открытии файла: %v", message.Chat.ID, err)
+		b.SendMessage(message.Chat.ID, tr(ctx, "admin.export_failed"))
 		return
 	}
 	defer file.Close()
@@ -493,6 +615,7 @@ func (b *Bot) handleSendLogs(_ context.Context, message *tgbotapi.Message) {
 	fileBytes, err := io.ReadAll(file)
 	if err != nil {
 		log.Errorf("%d Ошибка при чтении файла: %v", message.Chat.ID, err)
+		b.SendMessage(message.Chat.ID, tr(ctx, "admin.export_failed"))
 		return
 	}
 
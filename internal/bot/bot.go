@@ -3,16 +3,18 @@ package bot
 import (
 	"context"
 	"database/sql"
-	"encoding/json"
 	"fmt"
-	"net/http"
 	"sort"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/lsdpls/schulze_election_telegram_bot/internal/config"
+	"github.com/lsdpls/schulze_election_telegram_bot/internal/dispatcher"
 	"github.com/lsdpls/schulze_election_telegram_bot/internal/logger"
 	"github.com/lsdpls/schulze_election_telegram_bot/internal/models"
+	"github.com/lsdpls/schulze_election_telegram_bot/internal/ratelimit"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 )
@@ -27,40 +29,53 @@ var log *logger.Logger
 
 // Bot struct for managing commands and Telegram API
 type Bot struct {
-	botAPI    *tgbotapi.BotAPI // Telegram API
-	voteChain voteChain        // цепочка для взаимодействия с базой данных
-	schulze   schulze          // структура для работы с алгоритмом Шульце
-	mu        sync.RWMutex     // Блокировка ресурсов
-
-	// TODO: create user session struct
-	userStates          map[int64]string // Состояния пользователей, где ключ — telegramID, а значение — текущее состояние
-	codeStore           map[int64]int    // Хранение кодов подтверждения (telegramID -> код)
-	userEmail           map[int64]int    // Хранение не верифицированных email пользователей (telegramID -> email)
-	Candidates          map[int]models.Candidate
-	sortedCandidatesIDs []int
-	rankedList          map[int64][]int // Хранение незаполненных бюллетеней
-	candidatesList      string          // Список кандидатов для отправки пользователям
-	activeVoting        bool            // Флаг активного голосования
+	botAPI          *tgbotapi.BotAPI       // Telegram API
+	voteChain       voteChain              // цепочка для взаимодействия с базой данных
+	schulze         schulze                // структура для работы с алгоритмом Шульце
+	sessions        sessionStore           // хранилище состояний пользователей (регистрация, незаполненные бюллетени)
+	dispatcher      *dispatcher.Dispatcher // Обработчики обновлений: по порядку для пользователя, параллельно для разных
+	ballotLocks     userLocks              // Блокировки чтения-изменения незаполненных бюллетеней по пользователям
+	broadcastMu     sync.Mutex             // Рассылки выполняются по одной
+	languages       sync.Map               // Кэш языков, выбранных пользователями: telegram_id -> язык
+	webhookWarnings *ratelimit.Limiter     // Предупреждения об отклоненных запросах к вебхуку
+	throttle        *throttle              // Ограничения частоты действий по пользователям
+	turnoutMu       sync.Mutex             // Обновления сообщения с явкой выполняются по одному
+	turnoutText     string                 // Последний опубликованный текст явки без времени обновления
+
+	candidates   atomic.Pointer[candidateList] // Кандидаты текущего голосования; список заменяется целиком
+	activeVoting atomic.Bool                   // Флаг активного голосования (копия сохраненного в sessions)
+}
+
+// candidateList — неизменяемый список кандидатов голосования. Обработчики читают его без блокировок
+type candidateList struct {
+	byID      map[int]models.Candidate
+	sortedIDs []int
 }
 
 // NewBot создает новый экземпляр бота
-func NewBot(botAPI *tgbotapi.BotAPI, voteChain voteChain, schulze schulze) *Bot {
+func NewBot(botAPI *tgbotapi.BotAPI, voteChain voteChain, schulze schulze, sessions sessionStore) *Bot {
 	log = logger.NewLogger(botAPI, config.LogLevel, config.TelegramLogLevel)
-	return &Bot{
-		botAPI:         botAPI,
-		voteChain:      voteChain,
-		schulze:        schulze,
-		userStates:     make(map[int64]string),
-		codeStore:      make(map[int64]int),
-		userEmail:      make(map[int64]int),
-		rankedList:     make(map[int64][]int),
-		Candidates:     make(map[int]models.Candidate),
-		candidatesList: "",
-		activeVoting:   false,
+	b := &Bot{
+		botAPI:    botAPI,
+		voteChain: voteChain,
+		schulze:   schulze,
+		sessions:  sessions,
+		throttle:  newThrottle(),
+
+		webhookWarnings: ratelimit.New(webhookWarningInterval, 1),
 	}
+	b.dispatcher = dispatcher.New(config.UpdateWorkers, b.HandleUpdate)
+	return b
+}
+
+// Logger возвращает логгер бота для компонентов, которые запускаются вместе с ним
+func (b *Bot) Logger() *logger.Logger {
+	return log
 }
 
+// Close дожидается обработки принятых обновлений и закрывает логгер
 func (b *Bot) Close() error {
+	b.dispatcher.Close()
 	if err := log.Close(); err != nil {
 		return err
 	}
@@ -72,23 +87,68 @@ type voteChain interface {
 	GetDelegateByDelegateID(ctx context.Context, delegateID int) (*models.Delegate, error)
 	GetAllDelegates(ctx context.Context) ([]models.Delegate, error)
 	VerificateDelegate(ctx context.Context, delegateID int, telegramID sql.NullInt64) error
-	DeleteDelegate(ctx context.Context, delegateID int) error
+	UpdateDelegate(ctx context.Context, delegate models.Delegate) error
+	ResetRegistration(ctx context.Context, delegateID int, discardVote bool, performedBy sql.NullInt64) (*models.Delegate, error)
+	GetAuditLog(ctx context.Context, delegateID int) ([]models.AuditRecord, error)
+	DeleteDelegate(ctx context.Context, delegateID int, hasVoted bool) error
 	CheckExistDelegateByDelegateID(ctx context.Context, delegateID int) (bool, error)
-	CheckExistDelegateByTelegramID(ctx context.Context, telegramID int64) (bool, error)
 	CheckFerification(ctx context.Context, delegateID int) (bool, error)
 
+	NominateProxy(ctx context.Context, proxy models.Proxy) (int, error)
+	RevokeProxy(ctx context.Context, delegateID int, revokedBy sql.NullInt64) (*models.Proxy, error)
+	VerifyProxy(ctx context.Context, substituteID int, telegramID sql.NullInt64) (*models.Proxy, error)
+	GetActiveProxyByDelegateID(ctx context.Context, delegateID int) (*models.Proxy, error)
+	GetActiveProxyBySubstituteID(ctx context.Context, substituteID int) (*models.Proxy, error)
+	GetAllProxies(ctx context.Context) ([]models.Proxy, error)
+	ResolveVoter(ctx context.Context, telegramID int64) (*models.Voter, error)
+
 	AddCandidate(ctx context.Context, candidate models.Candidate) error
 	GetAllCandidates(ctx context.Context) ([]models.Candidate, error)
+	GetCandidateByCandidateID(ctx context.Context, candidateID int) (*models.Candidate, error)
+	UpdateCandidate(ctx context.Context, candidate models.Candidate) error
 	BanCandidate(ctx context.Context, candidateID int) error
-	DeleteCandidate(ctx context.Context, candidateID int) error
+	UnbanCandidate(ctx context.Context, candidateID int) error
+	SetCandidatePhoto(ctx context.Context, candidateID int, photoFileID string) error
+	SetCandidateManifesto(ctx context.Context, candidateID int, manifestoURL string) error
+	DeleteCandidate(ctx context.Context, candidateID, ballots int) error
+
+	ApplyRosterImport(ctx context.Context, rosterImport models.RosterImport) error
 
 	AddVote(ctx context.Context, telegramID int64, votes []int) error
+	ConfirmVote(ctx context.Context, telegramID int64, votes []int) error
 	GetAllVotes(ctx context.Context) ([]models.Vote, error)
+	GetVoteWithReceipt(ctx context.Context, delegateID int) (*models.Vote, *models.VoteReceipt, error)
 	UpdateVote(ctx context.Context, vote models.Vote) error
 	DeleteVoteByDelegateID(ctx context.Context, delegateID int) error
 
 	AddResult(ctx context.Context, result models.Result) error
 	GetAllResults(ctx context.Context) ([]models.Result, error)
+
+	SetVotingActive(ctx context.Context, active bool) error
+	VotingActive(ctx context.Context) (bool, error)
+
+	SetScheduleWindow(ctx context.Context, window models.ScheduleWindow) error
+	GetScheduleWindow(ctx context.Context, kind string) (*models.ScheduleWindow, error)
+	GetAllScheduleWindows(ctx context.Context) ([]models.ScheduleWindow, error)
+	UpdateScheduleWindowProgress(ctx context.Context, window models.ScheduleWindow) error
+	DeleteScheduleWindow(ctx context.Context, kind string) error
+
+	AddReminder(ctx context.Context, reminder models.Reminder) (int, error)
+	GetPendingReminders(ctx context.Context) ([]models.Reminder, error)
+	ClaimDueReminders(ctx context.Context, now time.Time) ([]models.Reminder, error)
+	UpdateReminderReport(ctx context.Context, reminder models.Reminder) error
+	DeleteReminder(ctx context.Context, reminderID int) (bool, error)
+
+	SetAdmin(ctx context.Context, admin models.Admin) error
+	GetAdmin(ctx context.Context, telegramID int64) (*models.Admin, error)
+	GetAllAdmins(ctx context.Context) ([]models.Admin, error)
+	DeleteAdmin(ctx context.Context, telegramID int64) (bool, error)
+
+	SetUserLanguage(ctx context.Context, telegramID int64, language string) error
+	GetUserLanguage(ctx context.Context, telegramID int64) (string, error)
+	SetTextOverride(ctx context.Context, override models.TextOverride) error
+	GetAllTextOverrides(ctx context.Context) ([]models.TextOverride, error)
+	DeleteTextOverride(ctx context.Context, language, key string) (bool, error)
 }
 
 type schulze interface {
@@ -100,160 +160,118 @@ type schulze interface {
 	ComputeResults(ctx context.Context) error
 	ComputeGlobalTop(ctx context.Context) error
 	SaveResultsToCSV(ctx context.Context) error
+	BuildProtocolPDF(ctx context.Context) ([]byte, error)
+	BuildResultsXLSX(ctx context.Context) ([]byte, error)
 }
 
 // Установка списка кандидатов перед голосованием
 func (b *Bot) SetCandidates() error {
-	b.mu.Lock()
-	defer b.mu.Unlock()
 	candidates, err := b.voteChain.GetAllCandidates(context.Background())
 	if err != nil {
 		return fmt.Errorf("SetCandidates: %w", err)
 	}
-	b.Candidates = make(map[int]models.Candidate)
-	b.sortedCandidatesIDs = []int{}
+	list := &candidateList{byID: make(map[int]models.Candidate)}
 	for _, candidate := range candidates {
 		if candidate.IsEligible {
-			b.Candidates[candidate.CandidateID] = candidate
+			list.byID[candidate.CandidateID] = candidate
+			list.sortedIDs = append(list.sortedIDs, candidate.CandidateID)
 		}
 	}
-	for candidateID := range b.Candidates {
-		b.sortedCandidatesIDs = append(b.sortedCandidatesIDs, candidateID)
-	}
-	sort.Ints(b.sortedCandidatesIDs)
-
-	b.candidatesList = "Cписок кандидатов:\n\n"
-	for _, k := range b.sortedCandidatesIDs {
-		b.candidatesList += fmt.Sprintf("• %s, %s\n", b.Candidates[k].Name, b.Candidates[k].Course)
-	}
-
+	sort.Ints(list.sortedIDs)
+	b.candidates.Store(list)
 	return nil
 }
 
-// HandleWebhook обрабатывает вебхуки от Telegram
-func (b *Bot) HandleWebhook(w http.ResponseWriter, r *http.Request) {
-	// Создаем контекст с таймаутом для обработки запроса
-	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
-	defer cancel()
-
-	var update tgbotapi.Update
-	if err := json.NewDecoder(r.Body).Decode(&update); err != nil {
-		log.Errorf("Error decoding update: %v", err)
-		http.Error(w, "Error decoding update", http.StatusBadRequest)
-		return
+// currentCandidates возвращает кандидатов текущего голосования. До SetCandidates список пуст
+func (b *Bot) currentCandidates() *candidateList {
+	if list := b.candidates.Load(); list != nil {
+		return list
 	}
+	return &candidateList{}
+}
 
-	if update.Message != nil || update.CallbackQuery != nil {
-		b.HandleUpdate(ctx, update) // TODO добавить выход по таймауту
+// Dispatch передает обновление обработчикам и возвращается, не дожидаясь обработки
+func (b *Bot) Dispatch(ctx context.Context, update tgbotapi.Update) error {
+	if err := b.dispatcher.Dispatch(ctx, update); err != nil {
+		return fmt.Errorf("Dispatch: %w", err)
 	}
+	return nil
+}
 
-	w.WriteHeader(http.StatusOK)
+// DispatchNotify передает обновление обработчикам, как Dispatch, и вызывает done после его обработки
+func (b *Bot) DispatchNotify(ctx context.Context, update tgbotapi.Update, done func()) error {
+	if err := b.dispatcher.DispatchNotify(ctx, update, done); err != nil {
+		return fmt.Errorf("DispatchNotify: %w", err)
+	}
+	return nil
 }
 
 // HandleUpdate обрабатывает обновления от Telegram
 func (b *Bot) HandleUpdate(ctx context.Context, update tgbotapi.Update) {
+	if from := update.SentFrom(); from != nil {
+		ctx = withLanguage(ctx, b.userLanguage(ctx, from))
+	}
 	if update.Message != nil {
 		if update.Message.IsCommand() {
 			b.handleCommand(ctx, update.Message)
+		} else if update.Message.Document != nil {
+			b.handleDocument(ctx, update.Message)
 		} else {
 			b.handleText(ctx, update.Message)
 		}
 	} else if update.CallbackQuery != nil {
+		if notice, limited := b.throttled(ctx, rateCallbacks, update.CallbackQuery.From.ID); limited {
+			// Отвечаем на нажатие, чтобы у пользователя не крутился индикатор загрузки
+			b.botAPI.Request(tgbotapi.NewCallback(update.CallbackQuery.ID, notice))
+			return
+		}
+		if strings.HasPrefix(update.CallbackQuery.Data, confirmCallbackPrefix) {
+			b.handleConfirmCallback(ctx, update.CallbackQuery)
+			return
+		}
+		if strings.HasPrefix(update.CallbackQuery.Data, editCallbackPrefix) {
+			b.handleEditCallback(ctx, update.CallbackQuery)
+			return
+		}
+		if strings.HasPrefix(update.CallbackQuery.Data, importCallbackPrefix) {
+			b.handleImportCallback(ctx, update.CallbackQuery)
+			return
+		}
+		if strings.HasPrefix(update.CallbackQuery.Data, candidatesCallbackPrefix) {
+			b.handleCandidatesCallback(ctx, update.CallbackQuery)
+			return
+		}
+		if strings.HasPrefix(update.CallbackQuery.Data, languageCallbackPrefix) {
+			b.handleLanguageCallback(ctx, update.CallbackQuery)
+			return
+		}
 		b.handleCallbackQuery(ctx, update.CallbackQuery)
 	}
 }
 
-// HandleCommand обрабатывает команды пользователя
-func (b *Bot) handleCommand(ctx context.Context, message *tgbotapi.Message) {
-	// Проверка администратора
-	if message.Chat.ID == config.AdminChatID {
-		switch message.Command() {
-		// Изменение базы данных
-		case "add_delegate":
-			b.handleAddDelegate(ctx, message)
-		case "delete_delegate":
-			b.handleDeleteDelegate(ctx, message)
-		case "add_candidate":
-			b.handleAddCandidate(ctx, message)
-		case "ban_candidate":
-			b.handleBanCandidate(ctx, message)
-		case "delete_candidate":
-			b.handleDeleteCandidate(ctx, message)
-		// Показать инфу
-		case "show_delegates":
-			b.handleShowDelegates(ctx, message)
-		case "show_candidates":
-			b.handleShowCandidates(ctx, message)
-		case "show_votes":
-			b.handleShowVotes(ctx, message)
-		// Управление голосованием
-		case "start_voting":
-			b.handleStartVoting(ctx, message)
-		case "stop_voting":
-			b.handleStopVoting(ctx, message)
-		case "results":
-			b.handleResults(ctx, message)
-		case "print":
-			b.handlePrint(ctx, message)
-		case "csv":
-			b.handleCSV(ctx, message)
-		// Уровень логирования
-		case "log":
-			b.handleLog(ctx, message)
-		case "send_logs":
-			b.handleSendLogs(ctx, message)
-		// Показать доступные команды
-		case "help":
-			b.handleHelpAdmin(ctx, message)
-		default:
-			msg := tgbotapi.NewMessage(message.Chat.ID, "Неизвестная Администрирующая команда")
-			b.botAPI.Send(msg)
-		}
+// HandleText обрабатывает текстовые сообщения пользователя
+func (b *Bot) handleText(ctx context.Context, message *tgbotapi.Message) {
+	if b.handleEditInput(ctx, message) {
 		return
 	}
-	if !message.Chat.IsPrivate() {
+	registration, _, err := b.getRegistration(ctx, message.Chat.ID)
+	if err != nil {
+		log.Errorf("%d Ошибка получения состояния пользователя: %v", message.Chat.ID, err)
+		b.SendMessage(message.Chat.ID, tr(ctx, "error.generic"))
 		return
 	}
-	// Команды делегатов
-	switch message.Command() {
-	case "start":
-		b.handleStart(ctx, message)
-	case "vote":
-		b.handleVote(ctx, message)
-	case "help":
-		b.handleHelp(ctx, message)
-	default:
-		msg := tgbotapi.NewMessage(message.Chat.ID, "Неизвестная команда")
-		b.botAPI.Send(msg)
-	}
-}
-
-// HandleText обрабатывает текстовые сообщения пользователя
-func (b *Bot) handleText(ctx context.Context, message *tgbotapi.Message) {
-	b.mu.RLock()
-	state := b.userStates[message.Chat.ID]
-	b.mu.RUnlock()
 
-	switch state {
+	switch registration.State {
 	case StateWaitingForEmail:
 		b.handleEmailInput(ctx, message)
 	case StateWaitingForCode:
 		b.handleCodeInput(ctx, message)
 	default:
-		msg := tgbotapi.NewMessage(message.Chat.ID, "Используйте /start для регистрации и /vote для голосования")
+		msg := tgbotapi.NewMessage(message.Chat.ID, tr(ctx, "text.hint"))
 		b.botAPI.Send(msg)
 	}
 }
 
-// Подсказка по командам
-func (b *Bot) handleHelp(_ context.Context, message *tgbotapi.Message) {
-	msg := tgbotapi.NewMessage(message.Chat.ID, "Список доступных команд:\n"+
-		"/start - начать регистрацию\n"+
-		"/vote - начать голосование\n"+
-		"/help - показать список доступных команд")
-	b.botAPI.Send(msg)
-}
-
 func (b *Bot) SendMessage(chatID int64, text string) error {
 	msg := tgbotapi.NewMessage(chatID, text)
 	msg.ParseMode = "HTML"
@@ -261,23 +279,8 @@ func (b *Bot) SendMessage(chatID int64, text string) error {
 	return err
 }
 
-func (b *Bot) handleLog(_ context.Context, message *tgbotapi.Message) {
-	level := message.CommandArguments()
-	var err error
-	switch level {
-	case "Debug":
-		err = log.SetLevel("Debug")
-	case "Info":
-		err = log.SetLevel("Info")
-	case "Warn":
-		err = log.SetLevel("Warn")
-	case "Error":
-		err = log.SetLevel("Error")
-	default:
-		log.Errorf("%d Неизвестный уровень логирования: %s", message.Chat.ID, level)
-		return
-	}
-	if err != nil {
+func (b *Bot) handleLog(ctx context.Context, message *tgbotapi.Message) {
+	if err := log.SetLevel(commandArgsFromContext(ctx).text("level")); err != nil {
 		log.Errorf("%d Ошибка при изменении уровня логирования: %v", message.Chat.ID, err)
 		return
 	}
@@ -0,0 +1,86 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// Пауза между сообщениями рассылки: Telegram допускает около 30 сообщений в секунду
+const broadcastDelay = 40 * time.Millisecond
+
+// Сколько раз повторять отправку после ответа 429 Too Many Requests
+const broadcastMaxRetries = 3
+
+// broadcastReport итоги рассылки
+type broadcastReport struct {
+	Delivered int // Доставлено
+	Blocked   int // Пользователь заблокировал бота или удалил аккаунт
+	Failed    int // Прочие ошибки
+}
+
+func (r broadcastReport) String() string {
+	return fmt.Sprintf("доставлено: %d, заблокировали бота: %d, ошибок: %d", r.Delivered, r.Blocked, r.Failed)
+}
+
+// localizedText формирует текст сообщения на языке из контекста
+type localizedText func(ctx context.Context) string
+
+// broadcast отправляет сообщение в каждый чат на языке получателя с соблюдением ограничений Telegram.
+// Рассылки выполняются по одной, чтобы параллельные рассылки не превышали общий лимит
+func (b *Bot) broadcast(ctx context.Context, chatIDs []int64, text localizedText) broadcastReport {
+	b.broadcastMu.Lock()
+	defer b.broadcastMu.Unlock()
+
+	var report broadcastReport
+	for i, chatID := range chatIDs {
+		if i > 0 {
+			select {
+			case <-ctx.Done():
+				report.Failed += len(chatIDs) - i
+				log.Warnf("Рассылка прервана: %v", ctx.Err())
+				return report
+			case <-time.After(broadcastDelay):
+			}
+		}
+		err := b.sendWithRetry(ctx, chatID, text(withLanguage(ctx, b.chatLanguage(ctx, chatID))))
+		switch {
+		case err == nil:
+			report.Delivered++
+		case isBlockedError(err):
+			report.Blocked++
+			log.Debugf("%d Пользователь заблокировал бота: %v", chatID, err)
+		default:
+			report.Failed++
+			log.Errorf("%d Ошибка отправки рассылки: %v", chatID, err)
+		}
+	}
+	return report
+}
+
+// sendWithRetry отправляет сообщение, дожидаясь указанной Telegram паузы при превышении лимита
+func (b *Bot) sendWithRetry(ctx context.Context, chatID int64, text string) error {
+	for attempt := 0; ; attempt++ {
+		err := b.SendMessage(chatID, text)
+		var tgErr *tgbotapi.Error
+		if err == nil || !errors.As(err, &tgErr) || tgErr.RetryAfter == 0 || attempt >= broadcastMaxRetries {
+			return err
+		}
+		log.Warnf("%d Превышен лимит Telegram, повтор через %d сек.", chatID, tgErr.RetryAfter)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(time.Duration(tgErr.RetryAfter) * time.Second):
+		}
+	}
+}
+
+// isBlockedError сообщает, что пользователь недоступен: заблокировал бота или удалил аккаунт
+func isBlockedError(err error) bool {
+	var tgErr *tgbotapi.Error
+	return errors.As(err, &tgErr) && tgErr.Code == http.StatusForbidden
+}
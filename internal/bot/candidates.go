@@ -0,0 +1,249 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/lsdpls/schulze_election_telegram_bot/internal/models"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// Данные кнопок просмотра кандидатов: cand:courses, cand:course:<номер курса>:<страница>, cand:show:<candidate_id>
+const (
+	candidatesCallbackPrefix = "cand:"
+	candidatesActionCourses  = candidatesCallbackPrefix + "courses"
+	candidatesActionCourse   = candidatesCallbackPrefix + "course"
+	candidatesActionShow     = candidatesCallbackPrefix + "show"
+)
+
+// Кандидатов на одной странице курса
+const candidatesPageSize = 5
+
+// Максимальная длина подписи к фото в Telegram
+const photoCaptionLimit = 1024
+
+var courseRe = regexp.MustCompile(`^(\d) (бакалавриат|магистратура)$`)
+
+// Обработчик команды /candidates
+func (b *Bot) handleCandidates(ctx context.Context, message *tgbotapi.Message) {
+	chatID := message.Chat.ID
+	courses, byCourse, err := b.eligibleCandidatesByCourse(ctx)
+	if err != nil {
+		log.Errorf("%d Ошибка при получении списка кандидатов: %v", chatID, err)
+		b.SendMessage(chatID, tr(ctx, "candidates.load_failed"))
+		return
+	}
+	if len(courses) == 0 {
+		b.SendMessage(chatID, tr(ctx, "candidates.empty"))
+		return
+	}
+	msg := tgbotapi.NewMessage(chatID, tr(ctx, "candidates.choose_course"))
+	msg.ReplyMarkup = coursesKeyboard(ctx, courses, byCourse)
+	b.botAPI.Send(msg)
+}
+
+// handleCandidatesCallback обрабатывает кнопки просмотра кандидатов
+func (b *Bot) handleCandidatesCallback(ctx context.Context, query *tgbotapi.CallbackQuery) {
+	b.botAPI.Send(tgbotapi.NewCallback(query.ID, ""))
+	if query.Message == nil {
+		return
+	}
+	chatID := query.Message.Chat.ID
+	courses, byCourse, err := b.eligibleCandidatesByCourse(ctx)
+	if err != nil {
+		log.Errorf("%d Ошибка при получении списка кандидатов: %v", chatID, err)
+		b.SendMessage(chatID, tr(ctx, "candidates.load_failed"))
+		return
+	}
+
+	parts := strings.Split(query.Data, ":")
+	switch {
+	case query.Data == candidatesActionCourses:
+		msg := tgbotapi.NewEditMessageTextAndMarkup(chatID, query.Message.MessageID,
+			tr(ctx, "candidates.choose_course"), coursesKeyboard(ctx, courses, byCourse))
+		b.botAPI.Send(msg)
+	case strings.HasPrefix(query.Data, candidatesActionCourse+":") && len(parts) == 4:
+		courseIndex, errCourse := strconv.Atoi(parts[2])
+		page, errPage := strconv.Atoi(parts[3])
+		if errCourse != nil || errPage != nil || courseIndex < 0 || courseIndex >= len(courses) {
+			// Список курсов изменился: показываем его заново
+			msg := tgbotapi.NewEditMessageTextAndMarkup(chatID, query.Message.MessageID,
+				tr(ctx, "candidates.choose_course"), coursesKeyboard(ctx, courses, byCourse))
+			b.botAPI.Send(msg)
+			return
+		}
+		text, keyboard := coursePage(ctx, courseIndex, courses[courseIndex], byCourse[courses[courseIndex]], page)
+		msg := tgbotapi.NewEditMessageTextAndMarkup(chatID, query.Message.MessageID, text, keyboard)
+		msg.ParseMode = "HTML"
+		b.botAPI.Send(msg)
+	case strings.HasPrefix(query.Data, candidatesActionShow+":") && len(parts) == 3:
+		candidateID, err := strconv.Atoi(parts[2])
+		if err != nil {
+			return
+		}
+		for _, course := range courses {
+			for _, candidate := range byCourse[course] {
+				if candidate.CandidateID == candidateID {
+					b.sendCandidateCard(ctx, chatID, candidate)
+					return
+				}
+			}
+		}
+		b.SendMessage(chatID, tr(ctx, "candidates.not_found"))
+	}
+}
+
+// eligibleCandidatesByCourse возвращает упорядоченные курсы и допущенных кандидатов каждого курса по алфавиту
+func (b *Bot) eligibleCandidatesByCourse(ctx context.Context) ([]string, map[string][]models.Candidate, error) {
+	candidates, err := b.voteChain.GetAllCandidates(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("eligibleCandidatesByCourse: %w", err)
+	}
+	byCourse := make(map[string][]models.Candidate)
+	var courses []string
+	for _, candidate := range candidates {
+		if !candidate.IsEligible {
+			continue
+		}
+		if _, ok := byCourse[candidate.Course]; !ok {
+			courses = append(courses, candidate.Course)
+		}
+		byCourse[candidate.Course] = append(byCourse[candidate.Course], candidate)
+	}
+	for _, course := range courses {
+		sort.Slice(byCourse[course], func(i, j int) bool { return byCourse[course][i].Name < byCourse[course][j].Name })
+	}
+	sortCourses(courses)
+	return courses, byCourse, nil
+}
+
+// sortCourses упорядочивает курсы: сначала бакалавриат, затем магистратура, внутри — по номеру курса
+func sortCourses(courses []string) {
+	key := func(course string) (int, int) {
+		match := courseRe.FindStringSubmatch(course)
+		if match == nil {
+			return 2, 0
+		}
+		number, _ := strconv.Atoi(match[1])
+		if match[2] == "магистратура" {
+			return 1, number
+		}
+		return 0, number
+	}
+	sort.SliceStable(courses, func(i, j int) bool {
+		levelI, numberI := key(courses[i])
+		levelJ, numberJ := key(courses[j])
+		if levelI != levelJ {
+			return levelI < levelJ
+		}
+		if numberI != numberJ {
+			return numberI < numberJ
+		}
+		return courses[i] < courses[j]
+	})
+}
+
+// courseName возвращает название курса на языке пользователя. Курсы в нестандартном формате выводятся как есть
+func courseName(ctx context.Context, course string) string {
+	match := courseRe.FindStringSubmatch(course)
+	if match == nil {
+		return course
+	}
+	number, _ := strconv.Atoi(match[1])
+	if match[2] == "магистратура" {
+		return tr(ctx, "course.master", number)
+	}
+	return tr(ctx, "course.bachelor", number)
+}
+
+// coursesKeyboard формирует кнопки выбора курса
+func coursesKeyboard(ctx context.Context, courses []string, byCourse map[string][]models.Candidate) tgbotapi.InlineKeyboardMarkup {
+	var rows [][]tgbotapi.InlineKeyboardButton
+	for i, course := range courses {
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(tgbotapi.NewInlineKeyboardButtonData(
+			fmt.Sprintf("%s (%d)", courseName(ctx, course), len(byCourse[course])),
+			fmt.Sprintf("%s:%d:0", candidatesActionCourse, i),
+		)))
+	}
+	return tgbotapi.NewInlineKeyboardMarkup(rows...)
+}
+
+// coursePage формирует страницу списка кандидатов курса
+func coursePage(ctx context.Context, courseIndex int, course string, candidates []models.Candidate, page int) (string, tgbotapi.InlineKeyboardMarkup) {
+	pages := (len(candidates) + candidatesPageSize - 1) / candidatesPageSize
+	page = max(0, min(page, pages-1))
+
+	text := fmt.Sprintf("<b>%s</b>\n%s", html.EscapeString(courseName(ctx, course)), tr(ctx, "candidates.choose_candidate"))
+	if pages > 1 {
+		text += "\n" + tr(ctx, "candidates.page", page+1, pages)
+	}
+
+	var rows [][]tgbotapi.InlineKeyboardButton
+	start := page * candidatesPageSize
+	end := min(start+candidatesPageSize, len(candidates))
+	for _, candidate := range candidates[start:end] {
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(tgbotapi.NewInlineKeyboardButtonData(
+			candidate.Name, fmt.Sprintf("%s:%d", candidatesActionShow, candidate.CandidateID))))
+	}
+	var navigation []tgbotapi.InlineKeyboardButton
+	if page > 0 {
+		navigation = append(navigation, tgbotapi.NewInlineKeyboardButtonData("◀️",
+			fmt.Sprintf("%s:%d:%d", candidatesActionCourse, courseIndex, page-1)))
+	}
+	if page < pages-1 {
+		navigation = append(navigation, tgbotapi.NewInlineKeyboardButtonData("▶️",
+			fmt.Sprintf("%s:%d:%d", candidatesActionCourse, courseIndex, page+1)))
+	}
+	if len(navigation) > 0 {
+		rows = append(rows, navigation)
+	}
+	rows = append(rows, tgbotapi.NewInlineKeyboardRow(tgbotapi.NewInlineKeyboardButtonData(tr(ctx, "candidates.back"), candidatesActionCourses)))
+	return text, tgbotapi.NewInlineKeyboardMarkup(rows...)
+}
+
+// candidateCardText формирует карточку кандидата (HTML)
+func candidateCardText(ctx context.Context, candidate models.Candidate) string {
+	text := fmt.Sprintf("<b>%s</b>\n%s", html.EscapeString(candidate.Name), html.EscapeString(courseName(ctx, candidate.Course)))
+	if candidate.Description != "" {
+		text += "\n\n" + html.EscapeString(candidate.Description)
+	}
+	if candidate.ManifestoURL != "" {
+		text += fmt.Sprintf("\n\n<a href=\"%s\">%s</a>", html.EscapeString(candidate.ManifestoURL), tr(ctx, "candidates.manifesto"))
+	}
+	return text
+}
+
+// sendCandidateCard отправляет карточку кандидата: с фото, если оно задано
+func (b *Bot) sendCandidateCard(ctx context.Context, chatID int64, candidate models.Candidate) {
+	text := candidateCardText(ctx, candidate)
+	if candidate.PhotoFileID == "" {
+		if err := b.SendMessage(chatID, text); err != nil {
+			log.Errorf("%d Ошибка отправки карточки кандидата: %v", chatID, err)
+		}
+		return
+	}
+
+	photo := tgbotapi.NewPhoto(chatID, tgbotapi.FileID(candidate.PhotoFileID))
+	// Длинное описание не помещается в подпись к фото и отправляется отдельным сообщением
+	fitsCaption := utf8.RuneCountInString(text) <= photoCaptionLimit
+	if fitsCaption {
+		photo.Caption = text
+		photo.ParseMode = "HTML"
+	}
+	if _, err := b.botAPI.Send(photo); err != nil {
+		log.Errorf("%d Ошибка отправки фото кандидата: %v", chatID, err)
+		fitsCaption = false
+	}
+	if !fitsCaption {
+		if err := b.SendMessage(chatID, text); err != nil {
+			log.Errorf("%d Ошибка отправки карточки кандидата: %v", chatID, err)
+		}
+	}
+}
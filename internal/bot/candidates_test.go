@@ -0,0 +1,13 @@
+package bot
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSortCourses(t *testing.T) {
+	courses := []string{"2 магистратура", "другое", "3 бакалавриат", "1 магистратура", "1 бакалавриат"}
+	sortCourses(courses)
+	assert.Equal(t, []string{"1 бакалавриат", "3 бакалавриат", "1 магистратура", "2 магистратура", "другое"}, courses)
+}
@@ -0,0 +1,150 @@
+package bot
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/lsdpls/schulze_election_telegram_bot/internal/chain"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// Данные кнопок подтверждения: confirm:<id>:yes или confirm:<id>:no
+const (
+	confirmCallbackPrefix = "confirm:"
+	confirmActionYes      = "yes"
+	confirmActionNo       = "no"
+)
+
+// confirmationSession хранит необратимое действие, ожидающее подтверждения
+type confirmationSession struct {
+	Command     string `json:"command"`      // Команда, которая выполнит действие после подтверждения
+	TargetID    int    `json:"target_id"`    // ID делегата или кандидата
+	Option      string `json:"option"`       // Вариант действия, если у команды он есть
+	HasVote     bool   `json:"has_vote"`     // Был ли у удаляемого делегата голос, когда запрошено подтверждение
+	Ballots     int    `json:"ballots"`      // В скольких бюллетенях был указан удаляемый кандидат
+	ChatID      int64  `json:"chat_id"`      // Чат, в котором запрошено подтверждение
+	RequestedBy int64  `json:"requested_by"` // Кто отправил команду
+}
+
+// askConfirmation сохраняет действие и отправляет описание последствий с кнопками подтверждения и отмены.
+// Действие выполняется только после нажатия «Подтвердить», пока не истек confirmationSessionTTL
+func (b *Bot) askConfirmation(ctx context.Context, message *tgbotapi.Message, confirmation confirmationSession, summary string) {
+	chatID := message.Chat.ID
+	id := make([]byte, 8)
+	if _, err := rand.Read(id); err != nil {
+		log.Errorf("%d Ошибка генерации идентификатора подтверждения: %v", chatID, err)
+		return
+	}
+	confirmationID := hex.EncodeToString(id)
+	confirmation.ChatID = chatID
+	if message.From != nil {
+		confirmation.RequestedBy = message.From.ID
+	}
+	if err := b.sessions.Set(ctx, confirmationKey(confirmationID), confirmation, confirmationSessionTTL); err != nil {
+		log.Errorf("%d Ошибка сохранения действия, ожидающего подтверждения: %v", chatID, err)
+		return
+	}
+
+	minutes := int(confirmationSessionTTL.Minutes())
+	msg := tgbotapi.NewMessage(chatID, summary+"\n\n"+trN(ctx, "confirm.expires", minutes, minutes))
+	msg.ParseMode = "HTML"
+	msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData(tr(ctx, "confirm.yes"), confirmCallbackPrefix+confirmationID+":"+confirmActionYes),
+		tgbotapi.NewInlineKeyboardButtonData(tr(ctx, "confirm.no"), confirmCallbackPrefix+confirmationID+":"+confirmActionNo),
+	))
+	if _, err := b.botAPI.Send(msg); err != nil {
+		log.Errorf("%d Ошибка отправки запроса подтверждения: %v", chatID, err)
+	}
+}
+
+// handleConfirmCallback выполняет или отменяет действие по кнопке подтверждения
+func (b *Bot) handleConfirmCallback(ctx context.Context, query *tgbotapi.CallbackQuery) {
+	b.botAPI.Send(tgbotapi.NewCallback(query.ID, ""))
+	if query.Message == nil {
+		return
+	}
+	chatID := query.Message.Chat.ID
+	confirmationID, action, ok := strings.Cut(strings.TrimPrefix(query.Data, confirmCallbackPrefix), ":")
+	if !ok {
+		return
+	}
+
+	var confirmation confirmationSession
+	found, err := b.sessions.Get(ctx, confirmationKey(confirmationID), &confirmation)
+	if err != nil {
+		log.Errorf("%d Ошибка получения действия, ожидающего подтверждения: %v", chatID, err)
+		return
+	}
+	if found && confirmation.ChatID != chatID {
+		return
+	}
+	// Права проверяются при нажатии: подтвердить может только тот, кому доступна сама команда
+	command, _ := findCommand(confirmation.Command)
+	if found {
+		role, err := b.adminRole(ctx, query.Message.Chat, query.From)
+		if err != nil {
+			log.Errorf("%d Ошибка проверки роли администратора: %v", chatID, err)
+			return
+		}
+		if !command.allows(role) {
+			log.Warnf("%d Попытка подтвердить /%s без прав (пользователь %d)", chatID, command.name, query.From.ID)
+			return
+		}
+	}
+
+	// Убираем кнопки, чтобы действие нельзя было подтвердить повторно
+	b.botAPI.Send(tgbotapi.NewEditMessageReplyMarkup(chatID, query.Message.MessageID,
+		tgbotapi.InlineKeyboardMarkup{InlineKeyboard: [][]tgbotapi.InlineKeyboardButton{}}))
+	if !found {
+		b.SendMessage(chatID, tr(ctx, "confirm.expired"))
+		return
+	}
+	// Действие забирается из хранилища атомарно: при двойном нажатии или нажатии двумя администраторами
+	// оно выполняется один раз
+	found, err = b.sessions.Take(ctx, confirmationKey(confirmationID), &confirmation)
+	if err != nil {
+		log.Errorf("%d Ошибка получения действия, ожидающего подтверждения: %v", chatID, err)
+		return
+	}
+	if !found {
+		b.SendMessage(chatID, tr(ctx, "confirm.expired"))
+		return
+	}
+
+	if action != confirmActionYes {
+		log.Infof("%d /%s %d отменено пользователем %d", chatID, command.name, confirmation.TargetID, query.From.ID)
+		b.SendMessage(chatID, tr(ctx, "confirm.cancelled"))
+		return
+	}
+	var text string
+	switch confirmation.Command {
+	case "delete_delegate":
+		err = b.voteChain.DeleteDelegate(ctx, confirmation.TargetID, confirmation.HasVote)
+		text = tr(ctx, "admin.delegate_deleted", confirmation.TargetID)
+	case "delete_candidate":
+		err = b.voteChain.DeleteCandidate(ctx, confirmation.TargetID, confirmation.Ballots)
+		text = tr(ctx, "admin.candidate_deleted", confirmation.TargetID)
+	case "reset_registration":
+		text, err = b.resetRegistration(ctx, confirmation, query.From.ID)
+	default:
+		err = fmt.Errorf("unknown command %q", confirmation.Command)
+	}
+	if errors.Is(err, chain.ErrStateChanged) {
+		log.Warnf("%d /%s %d не выполнено: данные изменились после запроса подтверждения", chatID, command.name, confirmation.TargetID)
+		b.SendMessage(chatID, tr(ctx, "confirm.state_changed"))
+		return
+	}
+	if err != nil {
+		log.Errorf("%d Ошибка выполнения подтвержденной команды /%s %d: %v", chatID, command.name, confirmation.TargetID, err)
+		b.SendMessage(chatID, tr(ctx, "confirm.failed"))
+		return
+	}
+	log.Warnf("%d /%s %d подтверждено пользователем %d (запросил %d)", chatID, command.name, confirmation.TargetID,
+		query.From.ID, confirmation.RequestedBy)
+	b.SendMessage(chatID, text)
+}
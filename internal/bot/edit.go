@@ -0,0 +1,314 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/lsdpls/schulze_election_telegram_bot/internal/chain"
+	"github.com/lsdpls/schulze_election_telegram_bot/internal/models"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// Что редактируется: делегат или кандидат. Команда редактирования — edit_<kind>
+const (
+	editDelegate  = "delegate"
+	editCandidate = "candidate"
+)
+
+// Данные кнопок мастера: edit:<kind>:<id>:<field> и edit:cancel
+const (
+	editCallbackPrefix = "edit:"
+	editActionCancel   = editCallbackPrefix + "cancel"
+)
+
+// Поля, которые можно изменить командами /edit_delegate и /edit_candidate
+var editFields = map[string][]string{
+	editDelegate:  {"name", "group"},
+	editCandidate: {"name", "course", "description"},
+}
+
+// editSession хранит поле, для которого мастер ждет новое значение
+type editSession struct {
+	Kind     string `json:"kind"`
+	TargetID int    `json:"target_id"`
+	Field    string `json:"field"`
+}
+
+// validEditValue проверяет новое значение поля
+func validEditValue(kind, field, value string) bool {
+	if value == "" {
+		return false
+	}
+	switch {
+	case kind == editDelegate && field == "group":
+		return isValidGroup(value)
+	case kind == editCandidate && field == "course":
+		return isValidCourse(value)
+	}
+	return true
+}
+
+// Обработчик команды /edit_delegate
+func (b *Bot) handleEditDelegate(ctx context.Context, message *tgbotapi.Message) {
+	b.handleEdit(ctx, message, editDelegate, commandArgsFromContext(ctx).number("delegate_id"))
+}
+
+// Обработчик команды /edit_candidate
+func (b *Bot) handleEditCandidate(ctx context.Context, message *tgbotapi.Message) {
+	b.handleEdit(ctx, message, editCandidate, commandArgsFromContext(ctx).number("candidate_id"))
+}
+
+// handleEdit изменяет поле сразу, если указаны поле и значение. Иначе запускает мастер:
+// без поля показывает карточку с кнопками полей, без значения — просит прислать новое значение
+func (b *Bot) handleEdit(ctx context.Context, message *tgbotapi.Message, kind string, targetID int) {
+	chatID := message.Chat.ID
+	args := commandArgsFromContext(ctx)
+	values, err := b.editValues(ctx, kind, targetID)
+	if err != nil {
+		log.Errorf("%d Ошибка при получении данных для изменения: %v", chatID, err)
+		return
+	}
+	if values == nil {
+		b.SendMessage(chatID, tr(ctx, "admin."+kind+"_not_found", targetID))
+		return
+	}
+	edit := editSession{Kind: kind, TargetID: targetID, Field: args.text("field")}
+	switch {
+	case !args.has("field"):
+		b.sendEditCard(ctx, chatID, edit, values)
+	case !args.has("value"):
+		if message.From != nil {
+			b.askEditValue(ctx, chatID, message.From.ID, edit, values[edit.Field])
+		}
+	default:
+		b.applyEdit(ctx, chatID, edit, args.text("value"))
+	}
+}
+
+// editValues возвращает текущие значения редактируемых полей. nil — записи нет
+func (b *Bot) editValues(ctx context.Context, kind string, targetID int) (map[string]string, error) {
+	switch kind {
+	case editDelegate:
+		delegate, err := b.voteChain.GetDelegateByDelegateID(ctx, targetID)
+		if err != nil || delegate == nil {
+			return nil, err
+		}
+		return map[string]string{"name": delegate.Name, "group": delegate.Group}, nil
+	case editCandidate:
+		candidate, err := b.voteChain.GetCandidateByCandidateID(ctx, targetID)
+		if err != nil || candidate == nil {
+			return nil, err
+		}
+		return map[string]string{"name": candidate.Name, "course": candidate.Course, "description": candidate.Description}, nil
+	}
+	return nil, fmt.Errorf("editValues: unknown kind %q", kind)
+}
+
+// sendEditCard показывает текущие значения полей с кнопками выбора поля
+func (b *Bot) sendEditCard(ctx context.Context, chatID int64, edit editSession, values map[string]string) {
+	text := tr(ctx, "edit."+edit.Kind+"_header", edit.TargetID)
+	var rows [][]tgbotapi.InlineKeyboardButton
+	for _, field := range editFields[edit.Kind] {
+		text += "\n" + tr(ctx, "edit.field_value", tr(ctx, "edit.field."+field), html.EscapeString(values[field]))
+		data := fmt.Sprintf("%s%s:%d:%s", editCallbackPrefix, edit.Kind, edit.TargetID, field)
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(tgbotapi.NewInlineKeyboardButtonData(tr(ctx, "edit.field."+field), data)))
+	}
+	rows = append(rows, tgbotapi.NewInlineKeyboardRow(tgbotapi.NewInlineKeyboardButtonData(tr(ctx, "edit.cancel"), editActionCancel)))
+
+	msg := tgbotapi.NewMessage(chatID, text+"\n\n"+tr(ctx, "edit.choose_field"))
+	msg.ParseMode = "HTML"
+	msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(rows...)
+	if _, err := b.botAPI.Send(msg); err != nil {
+		log.Errorf("%d Ошибка отправки карточки изменения: %v", chatID, err)
+	}
+}
+
+// askEditValue запоминает выбранное поле и просит прислать новое значение ответом на сообщение:
+// в группах бот без прав администратора получает только ответы на свои сообщения
+func (b *Bot) askEditValue(ctx context.Context, chatID, telegramID int64, edit editSession, current string) {
+	if err := b.sessions.Set(ctx, editKey(chatID, telegramID), edit, editSessionTTL); err != nil {
+		log.Errorf("%d Ошибка сохранения состояния изменения: %v", chatID, err)
+		return
+	}
+	msg := tgbotapi.NewMessage(chatID, tr(ctx, "edit.prompt", tr(ctx, "edit.field."+edit.Field), html.EscapeString(current)))
+	msg.ParseMode = "HTML"
+	msg.ReplyMarkup = tgbotapi.ForceReply{ForceReply: true}
+	if _, err := b.botAPI.Send(msg); err != nil {
+		log.Errorf("%d Ошибка отправки запроса нового значения: %v", chatID, err)
+	}
+}
+
+// handleEditCallback обрабатывает кнопки карточки изменения
+func (b *Bot) handleEditCallback(ctx context.Context, query *tgbotapi.CallbackQuery) {
+	b.botAPI.Send(tgbotapi.NewCallback(query.ID, ""))
+	if query.Message == nil {
+		return
+	}
+	chatID := query.Message.Chat.ID
+	if query.Data == editActionCancel {
+		if err := b.sessions.Delete(ctx, editKey(chatID, query.From.ID)); err != nil {
+			log.Errorf("%d Ошибка удаления состояния изменения: %v", chatID, err)
+		}
+		b.botAPI.Send(tgbotapi.NewEditMessageReplyMarkup(chatID, query.Message.MessageID,
+			tgbotapi.InlineKeyboardMarkup{InlineKeyboard: [][]tgbotapi.InlineKeyboardButton{}}))
+		b.SendMessage(chatID, tr(ctx, "edit.cancelled"))
+		return
+	}
+
+	parts := strings.Split(strings.TrimPrefix(query.Data, editCallbackPrefix), ":")
+	if len(parts) != 3 || !slices.Contains(editFields[parts[0]], parts[2]) {
+		return
+	}
+	targetID, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return
+	}
+	edit := editSession{Kind: parts[0], TargetID: targetID, Field: parts[2]}
+	if !b.canEdit(ctx, query.Message.Chat, query.From, edit.Kind) {
+		return
+	}
+	values, err := b.editValues(ctx, edit.Kind, edit.TargetID)
+	if err != nil {
+		log.Errorf("%d Ошибка при получении данных для изменения: %v", chatID, err)
+		return
+	}
+	if values == nil {
+		b.SendMessage(chatID, tr(ctx, "admin."+edit.Kind+"_not_found", edit.TargetID))
+		return
+	}
+	b.askEditValue(ctx, chatID, query.From.ID, edit, values[edit.Field])
+}
+
+// handleEditInput принимает новое значение поля, если пользователь в этом чате заполняет мастер.
+// false — мастер не запущен и сообщение нужно обработать как обычный текст
+func (b *Bot) handleEditInput(ctx context.Context, message *tgbotapi.Message) bool {
+	if message.From == nil {
+		return false
+	}
+	chatID := message.Chat.ID
+	var edit editSession
+	ok, err := b.sessions.Get(ctx, editKey(chatID, message.From.ID), &edit)
+	if err != nil {
+		log.Errorf("%d Ошибка получения состояния изменения: %v", chatID, err)
+		return false
+	}
+	if !ok {
+		return false
+	}
+	if !b.canEdit(ctx, message.Chat, message.From, edit.Kind) {
+		return false
+	}
+	if b.applyEdit(ctx, chatID, edit, message.Text) {
+		if err := b.sessions.Delete(ctx, editKey(chatID, message.From.ID)); err != nil {
+			log.Errorf("%d Ошибка удаления состояния изменения: %v", chatID, err)
+		}
+	}
+	return true
+}
+
+// canEdit проверяет, что пользователю доступна команда изменения
+func (b *Bot) canEdit(ctx context.Context, chat *tgbotapi.Chat, from *tgbotapi.User, kind string) bool {
+	role, err := b.adminRole(ctx, chat, from)
+	if err != nil {
+		log.Errorf("%d Ошибка проверки роли администратора: %v", chat.ID, err)
+		return false
+	}
+	command, _ := findCommand("edit_" + kind)
+	if !command.allows(role) {
+		log.Warnf("%d Попытка изменить данные без прав (пользователь %d)", chat.ID, from.ID)
+		return false
+	}
+	return true
+}
+
+// applyEdit проверяет и сохраняет новое значение поля. false — значение не принято, мастер ждет другое
+func (b *Bot) applyEdit(ctx context.Context, chatID int64, edit editSession, value string) bool {
+	value = strings.TrimSpace(value)
+	fieldName := tr(ctx, "edit.field."+edit.Field)
+	if !validEditValue(edit.Kind, edit.Field, value) {
+		b.SendMessage(chatID, tr(ctx, "edit.invalid_value", fieldName, tr(ctx, "edit.hint."+edit.Kind+"."+edit.Field)))
+		return false
+	}
+	var old string
+	var found bool
+	var err error
+	switch edit.Kind {
+	case editDelegate:
+		var delegate *models.Delegate
+		if delegate, err = b.voteChain.GetDelegateByDelegateID(ctx, edit.TargetID); err != nil || delegate == nil {
+			break
+		}
+		found = true
+		if edit.Field == "name" {
+			old, delegate.Name = delegate.Name, value
+		} else {
+			old, delegate.Group = delegate.Group, value
+		}
+		err = b.voteChain.UpdateDelegate(ctx, *delegate)
+	case editCandidate:
+		var candidate *models.Candidate
+		if candidate, err = b.voteChain.GetCandidateByCandidateID(ctx, edit.TargetID); err != nil || candidate == nil {
+			break
+		}
+		found = true
+		switch edit.Field {
+		case "name":
+			old, candidate.Name = candidate.Name, value
+		case "course":
+			old, candidate.Course = candidate.Course, value
+		default:
+			old, candidate.Description = candidate.Description, value
+		}
+		err = b.voteChain.UpdateCandidate(ctx, *candidate)
+	}
+	// Курс кандидата и группу делегата цепочка не меняет при открытом голосовании
+	if errors.Is(err, chain.ErrLockedDuringVoting) {
+		log.Warnf("%d Попытка изменить поле %s (%s st%06d) при открытом голосовании", chatID, edit.Field, edit.Kind, edit.TargetID)
+		b.SendMessage(chatID, tr(ctx, "edit.locked_during_voting", fieldName))
+		return true
+	}
+	if err != nil {
+		log.Errorf("%d Ошибка при изменении данных (%s st%06d, %s): %v", chatID, edit.Kind, edit.TargetID, edit.Field, err)
+		b.SendMessage(chatID, tr(ctx, "edit.failed"))
+		return true
+	}
+	if !found {
+		b.SendMessage(chatID, tr(ctx, "admin."+edit.Kind+"_not_found", edit.TargetID))
+		return true
+	}
+	log.Infof("%d Изменено поле %s: %s st%06d", chatID, edit.Field, edit.Kind, edit.TargetID)
+
+	// Бюллетени показывают кандидатов из снимка, поэтому при открытом голосовании обновляем его
+	if edit.Kind == editCandidate && b.activeVoting.Load() {
+		if err := b.SetCandidates(); err != nil {
+			log.Errorf("%d Ошибка обновления списка кандидатов: %v", chatID, err)
+		}
+	}
+	b.SendMessage(chatID, tr(ctx, "edit.done", edit.TargetID, fieldName, html.EscapeString(old), html.EscapeString(value)))
+	return true
+}
+
+// Обработчик команды /unban_candidate
+func (b *Bot) handleUnbanCandidate(ctx context.Context, message *tgbotapi.Message) {
+	chatID := message.Chat.ID
+	candidateID := commandArgsFromContext(ctx).number("candidate_id")
+
+	// Список кандидатов в бюллетенях не меняется до конца голосования
+	if b.activeVoting.Load() {
+		b.SendMessage(chatID, tr(ctx, "edit.unban_during_voting"))
+		return
+	}
+	if err := b.voteChain.UnbanCandidate(ctx, candidateID); err != nil {
+		log.Errorf("%d Ошибка при допуске кандидата: %v", chatID, err)
+		b.SendMessage(chatID, tr(ctx, "error.generic"))
+		return
+	}
+	log.Info(chatID, " Кандидат снова допущен")
+	b.SendMessage(chatID, tr(ctx, "edit.unbanned", candidateID))
+}
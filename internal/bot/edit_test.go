@@ -0,0 +1,47 @@
+package bot
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidEditValue(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		kind, field, value string
+		valid              bool
+	}{
+		{editDelegate, "name", "Иванов Иван", true},
+		{editDelegate, "name", "", false},
+		{editDelegate, "group", "21.Б01-пу", true},
+		{editDelegate, "group", "21Б01", false},
+		{editCandidate, "course", "2 магистратура", true},
+		{editCandidate, "course", "5 бакалавриат", false},
+		{editCandidate, "description", "Текст, с запятыми", true},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.valid, validEditValue(tt.kind, tt.field, tt.value), "%s %s %q", tt.kind, tt.field, tt.value)
+	}
+}
+
+// Значение может содержать запятые, поле — только из списка editFields
+func TestEditCommandArgs(t *testing.T) {
+	t.Parallel()
+
+	command, ok := findCommand("edit_candidate")
+	require.True(t, ok)
+
+	args, err := command.parseArgs("123456, description, Студсовет, спорт")
+	require.NoError(t, err)
+	assert.Equal(t, commandArgs{"candidate_id": "123456", "field": "description", "value": "Студсовет, спорт"}, args)
+
+	args, err = command.parseArgs("123456")
+	require.NoError(t, err)
+	assert.Equal(t, commandArgs{"candidate_id": "123456"}, args)
+
+	_, err = command.parseArgs("123456, group, 21.Б01-пу")
+	assert.Error(t, err)
+}
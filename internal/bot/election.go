@@ -4,98 +4,104 @@ import (
 	"context"
 	"fmt"
 	"strconv"
+	"strings"
+
+	"github.com/lsdpls/schulze_election_telegram_bot/internal/config"
 	"github.com/lsdpls/schulze_election_telegram_bot/internal/utils"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 )
 
-var helpText = "Принцип голосования по методу Шульце заключается в формировании ранжированного списка кандидатов, " +
-	"в котором <b>каждый кандидат должен быть ранжирован</b> по отношению к другим.\n" +
-	"Например, если вы считаете, что кандидат А лучше кандидата Б, то вы должны поставить кандидата А выше в списке.\n\n" +
-	"В контексте использования данного бота Вы должны последовательно выбрать кандидатов, от наиболее предпочитаемого к наименее предпочитаемому.\n" +
-	"Для этого используйте кнопки меню в сообщении-бюллетени, последовательно выбирая нужного кандидата.\n\n" +
-	"Важно:\n" +
-	"• Вы должны ранжировать <b>всех кандидатов</b>.\n" +
-	"• Удостоверьтесь, что Ваш бюллетень принят, <b>получив соответствующее сообщение</b>.\n" +
-	"• Вы cможете изменить свой бюллетень ранжирования в любое время до окончания голосования.\n" +
-	"• Не выбирайте следующего кандидата, пока не увидите изменение в теле сообщения-бюллетеня.\n"
-
 // Обработчик команды /vote
 func (b *Bot) handleVote(ctx context.Context, message *tgbotapi.Message) {
 	telegramID := message.Chat.ID
-	b.mu.RLock()
-	isActive := b.activeVoting
-	b.mu.RUnlock()
-
-	if !isActive {
+	if !b.activeVoting.Load() {
 		log.Warn(telegramID, " Попытка начать голосование при закрытом голосовании")
-		b.SendMessage(telegramID, "Голосование уже завершилось или еще не началось")
+		b.SendMessage(telegramID, tr(ctx, "vote.closed"))
 		return
 	}
-	// Проверяем, зарегистрирован ли пользователь
-	ok, err := b.voteChain.CheckExistDelegateByTelegramID(ctx, telegramID)
+	// Проверяем, зарегистрирован ли пользователь как делегат или заместитель
+	voter, err := b.voteChain.ResolveVoter(ctx, telegramID)
 	if err != nil {
 		log.Errorf("%d Ошибка при начале голосования: %v", telegramID, err)
-		b.SendMessage(telegramID, "Произошла ошибка при проверке регистрации делегата. Пожалуйста, попробуйте снова")
+		b.SendMessage(telegramID, tr(ctx, "vote.registration_check_failed"))
 		return
 	}
-	if !ok {
+	if voter == nil {
 		log.Warn(telegramID, " Незарегистрированный пользователь пытается начать голосование")
-		b.SendMessage(telegramID, "Вы не зарегистрированы! Используйте команду /start для регистрации")
+		b.SendMessage(telegramID, tr(ctx, "vote.not_registered"))
 		return
 	}
-	if err := b.SendMessage(telegramID, helpText); err != nil {
+	if voter.Delegated() {
+		log.Warn(telegramID, " Делегат с действующей доверенностью пытается начать голосование")
+		b.SendMessage(telegramID, tr(ctx, "vote.proxy_active", voter.SubstituteID))
+		return
+	}
+	if voter.ProxyID.Valid {
+		b.SendMessage(telegramID, tr(ctx, "vote.as_proxy", voter.DelegateID))
+	}
+	if err := b.SendMessage(telegramID, tr(ctx, "vote.help")); err != nil {
 		log.Errorf("%d Ошибка получения памятки к голосованию: %v", telegramID, err)
 	}
 
-	b.mu.RLock()
-	candidatesListCopy := b.candidatesList
-	b.mu.RUnlock()
+	candidates := b.currentCandidates()
+	candidatesList := tr(ctx, "vote.candidates_header") + "\n\n"
+	for _, candidateID := range candidates.sortedIDs {
+		candidatesList += fmt.Sprintf("• %s, %s\n", candidates.byID[candidateID].Name, courseName(ctx, candidates.byID[candidateID].Course))
+	}
 
-	if err := b.SendMessage(telegramID, candidatesListCopy); err != nil {
+	if err := b.SendMessage(telegramID, candidatesList); err != nil {
 		log.Errorf("%d Ошибка отправки списка кандидатов: %v", telegramID, err)
 	}
 
-	// Создаем бюллетень для делегата
-	b.mu.Lock()
-	b.rankedList[message.Chat.ID] = []int{}
-	b.mu.Unlock()
-	b.sendCandidateKeyboard(ctx, message, false)
-}
-
-// Отправка бюллетеня
-func (b *Bot) sendCandidateKeyboard(_ context.Context, message *tgbotapi.Message, editMsg bool) {
-	telegramID := message.Chat.ID
-	b.mu.RLock()
-	defer b.mu.RUnlock()
-	// TODO добавить кнопку отмены последнего голоса
-
-	// Создаем кнопки выбора кандидата
-	var keyboard tgbotapi.InlineKeyboardMarkup
-	for _, candidateID := range b.sortedCandidatesIDs {
-		// Пропускаем уже записанных кандидатов
-		if contains(b.rankedList[telegramID], candidateID) {
-			continue
+	// Предлагаем бюллетень в Mini App, если он настроен
+	if config.WebAppURL != "" {
+		if err := b.sendWebAppButton(ctx, telegramID); err != nil {
+			log.Errorf("%d Ошибка отправки кнопки Mini App: %v", telegramID, err)
 		}
-		button := tgbotapi.NewInlineKeyboardButtonData(
-			fmt.Sprintf("%s, %s", b.Candidates[candidateID].Name, b.Candidates[candidateID].Course), // надпись кнопки
-			strconv.Itoa(candidateID), // данные кнопки
-		)
-		keyboard.InlineKeyboard = append(keyboard.InlineKeyboard, []tgbotapi.InlineKeyboardButton{button})
 	}
-	// Проверяем остались ли кандидаты для выбора
-	if len(keyboard.InlineKeyboard) == 0 {
-		log.Warn(message.Chat.ID, " Попытка вписать кандидатов, когда все уже вписаны")
-		b.spoilBallot(telegramID, message)
+
+	// Создаем бюллетень для делегата. Новая сессия делает кнопки прежних бюллетеней недействительными
+	ballot, err := newBallotSession()
+	if err == nil {
+		unlock := b.ballotLocks.lock(telegramID)
+		err = b.setBallot(ctx, telegramID, ballot)
+		unlock()
+	}
+	if err != nil {
+		log.Errorf("%d Ошибка создания бюллетеня: %v", telegramID, err)
+		b.SendMessage(telegramID, tr(ctx, "ballot.create_failed"))
 		return
 	}
+	b.sendCandidateKeyboard(ctx, message, ballot, false)
+}
+
+// Данные кнопок бюллетеня: ballot:<ID сессии>:<версия>:<действие>:<аргумент>.
+// Аргумент — ID кандидата для выбора или позиция в списке для перемещения
+const ballotCallbackPrefix = "ballot:"
+
+// Действия кнопок бюллетеня
+const (
+	ballotActionRank   = "rank"   // Вписать кандидата
+	ballotActionUndo   = "undo"   // Отменить последний выбор
+	ballotActionClear  = "clear"  // Очистить бюллетень
+	ballotActionUp     = "up"     // Поднять кандидата на позицию выше
+	ballotActionDown   = "down"   // Опустить кандидата на позицию ниже
+	ballotActionReview = "review" // Перейти к проверке бюллетеня
+	ballotActionEdit   = "edit"   // Вернуться к редактированию после проверки
+	ballotActionSubmit = "submit" // Отправить бюллетень
+	ballotActionNoop   = "noop"   // Кнопка-подпись без действия
+)
+
+// Отправка бюллетеня
+func (b *Bot) sendCandidateKeyboard(ctx context.Context, message *tgbotapi.Message, ballot ballotSession, editMsg bool) {
+	telegramID := message.Chat.ID
+	candidates := b.currentCandidates()
+	msgText := tr(ctx, "ballot.header") + "\n\n" + candidates.rankedListText(ballot.RankedList)
+	keyboard := candidates.ballotKeyboard(ctx, ballot)
 
 	// Отправляем сообщение с клавиатурой
 	if editMsg {
-		msgText := "Выберите всех кандидатов от наиболее к наименее предпочтительному:\n\n"
-		for i, candidateID := range b.rankedList[telegramID] {
-			msgText += fmt.Sprintf("%d. %s\n", i+1, b.Candidates[candidateID].Name)
-		}
 		msg := tgbotapi.NewEditMessageTextAndMarkup(
 			message.Chat.ID,
 			message.MessageID,
@@ -106,7 +112,7 @@ func (b *Bot) sendCandidateKeyboard(_ context.Context, message *tgbotapi.Message
 			log.Errorf("%d ошибка записи бюллетеня: %v", telegramID, err)
 		}
 	} else {
-		msg := tgbotapi.NewMessage(message.Chat.ID, "Выберите всех кандидатов от наиболее к наименее предпочтительному:\n\n")
+		msg := tgbotapi.NewMessage(message.Chat.ID, msgText)
 		msg.ReplyMarkup = keyboard
 		if _, err := b.botAPI.Send(msg); err != nil {
 			log.Errorf("%d ошибка отправки бюллетеня: %v", telegramID, err)
@@ -114,115 +120,246 @@ func (b *Bot) sendCandidateKeyboard(_ context.Context, message *tgbotapi.Message
 	}
 }
 
+// ballotKeyboard строит клавиатуру бюллетеня: ранжированные кандидаты с кнопками перемещения,
+// оставшиеся кандидаты и кнопки управления
+func (c *candidateList) ballotKeyboard(ctx context.Context, ballot ballotSession) tgbotapi.InlineKeyboardMarkup {
+	var keyboard tgbotapi.InlineKeyboardMarkup
+	rankedList := ballot.RankedList
+
+	// Уже ранжированные кандидаты: кнопки перемещения вверх и вниз
+	for i, candidateID := range rankedList {
+		keyboard.InlineKeyboard = append(keyboard.InlineKeyboard, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("⬆️", ballotCallbackData(ballot, ballotActionUp, i)),
+			tgbotapi.NewInlineKeyboardButtonData(fmt.Sprintf("%d. %s", i+1, c.byID[candidateID].Name),
+				ballotCallbackData(ballot, ballotActionNoop, 0)),
+			tgbotapi.NewInlineKeyboardButtonData("⬇️", ballotCallbackData(ballot, ballotActionDown, i)),
+		))
+	}
+
+	// Создаем кнопки выбора кандидата
+	for _, candidateID := range c.sortedIDs {
+		// Пропускаем уже записанных кандидатов
+		if contains(rankedList, candidateID) {
+			continue
+		}
+		button := tgbotapi.NewInlineKeyboardButtonData(
+			fmt.Sprintf("%s, %s", c.byID[candidateID].Name, courseName(ctx, c.byID[candidateID].Course)), // надпись кнопки
+			ballotCallbackData(ballot, ballotActionRank, candidateID),                                    // данные кнопки
+		)
+		keyboard.InlineKeyboard = append(keyboard.InlineKeyboard, []tgbotapi.InlineKeyboardButton{button})
+	}
+
+	// Кнопки управления бюллетенем
+	if len(rankedList) > 0 {
+		keyboard.InlineKeyboard = append(keyboard.InlineKeyboard, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(tr(ctx, "ballot.undo"), ballotCallbackData(ballot, ballotActionUndo, 0)),
+			tgbotapi.NewInlineKeyboardButtonData(tr(ctx, "ballot.clear"), ballotCallbackData(ballot, ballotActionClear, 0)),
+		))
+	}
+	if len(rankedList) == len(c.byID) {
+		keyboard.InlineKeyboard = append(keyboard.InlineKeyboard, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(tr(ctx, "ballot.review"), ballotCallbackData(ballot, ballotActionReview, 0)),
+		))
+	}
+	return keyboard
+}
+
+// rankedListText формирует нумерованный список выбранных кандидатов
+func (c *candidateList) rankedListText(rankedList []int) string {
+	var msgText string
+	for i, candidateID := range rankedList {
+		msgText += fmt.Sprintf("%d. %s\n", i+1, c.byID[candidateID].Name)
+	}
+	return msgText
+}
+
 // Получение ответа кнопки
 func (b *Bot) handleCallbackQuery(ctx context.Context, query *tgbotapi.CallbackQuery) {
 	telegramID := query.From.ID
-	b.mu.RLock()
-	isActive := b.activeVoting
-	b.mu.RUnlock()
-
-	if !isActive {
+	if !b.activeVoting.Load() {
 		log.Warn(telegramID, " Попытка голосования при закрытом голосовании")
-		b.SendMessage(telegramID, "Голосование уже завершилось или еще не началось")
+		b.SendMessage(telegramID, tr(ctx, "vote.closed"))
 		return
 	}
-	// Извлекаем ID кандидата из данных кнопки
-	candidateID, err := strconv.Atoi(query.Data)
+	callback, valid := parseBallotCallbackData(query.Data)
+	if valid && callback.Action == ballotActionNoop {
+		b.botAPI.Send(tgbotapi.NewCallback(query.ID, ""))
+		return
+	}
+
+	// Чтение и изменение бюллетеня выполняются под блокировкой пользователя, чтобы быстрые нажатия
+	// и бюллетень из Mini App не потеряли выбор
+	unlock := b.ballotLocks.lock(telegramID)
+	defer unlock()
+	ballot, ok, err := b.getBallot(ctx, telegramID)
 	if err != nil {
-		log.Errorf("%d Ошибка при обработке кнопки: %v", telegramID, err)
-		b.SendMessage(telegramID, "Произошла ошибка при обработке кнопки. Пожалуйста, попробуйте снова")
-		return
-	}
-	// TODO Вариант порчи бюллетеня получше того, что есть. Портит бюллетень одиножды при повторе
-	// // Проверяем, есть ли уже такой кандидат в списке ранжирования
-	// if contains(b.rankedList[telegramID], candidateID) {
-	// 	log.Warn(telegramID, "Попытка добавить уже добавленного кандидата")
-	// 	msg := tgbotapi.NewMessage(telegramID, "Этот кандидат уже добавлен в список. Пожалуйста, выберите другого кандидата.")
-	// 	b.botAPI.Send(msg)
-	// 	return
-	// }
-	b.mu.Lock()
-	// Проверяем не испорчен ли бюллетень (rankedList) делегата
-	if len(b.rankedList[telegramID]) >= len(b.Candidates) {
-		log.Warn(telegramID, " Попытка вписать кандидатов в заполненный бюллетень")
-		b.spoilBallot(telegramID, query.Message)
-		b.mu.Unlock()
-		return
-	}
-	// Добавляем ID кандидата в список ранжирования
-	b.rankedList[telegramID] = append(b.rankedList[telegramID], candidateID)
-	b.botAPI.Send(tgbotapi.NewCallback(query.ID, "Кандидат учтен"))
-
-	// Проверяем, все ли кандидаты ранжированы
-	if len(b.rankedList[telegramID]) == len(b.Candidates) {
-		// Проверяем, не испорчен ли бюллетень
-		if !isUniqueCandidates(b.rankedList[telegramID]) {
-			log.Warn(telegramID, " Испорченный бюллетень (повтор кандидатов)")
-			b.spoilBallot(telegramID, query.Message)
-			b.mu.Unlock()
+		log.Errorf("%d Ошибка получения бюллетеня: %v", telegramID, err)
+		b.SendMessage(telegramID, tr(ctx, "ballot.button_failed"))
+		return
+	}
+	switch {
+	// Бюллетень уже отправлен или не создавался: сообщение устарело
+	case !ok:
+		log.Warn(telegramID, " Попытка изменить устаревший бюллетень")
+		b.botAPI.Send(tgbotapi.NewCallbackWithAlert(query.ID, tr(ctx, "ballot.outdated")))
+		b.removeBallotKeyboard(query.Message)
+		return
+	// Кнопка из бюллетеня, замененного новым /vote: текущий бюллетень не меняется
+	case !valid || callback.SessionID != ballot.ID:
+		log.Warn(telegramID, " Нажатие кнопки в замененном бюллетене")
+		b.botAPI.Send(tgbotapi.NewCallbackWithAlert(query.ID, tr(ctx, "ballot.superseded")))
+		b.removeBallotKeyboard(query.Message)
+		return
+	// Повторное нажатие до обновления сообщения: позиции в кнопках уже не соответствуют бюллетеню
+	case callback.Seq != ballot.Seq:
+		log.Debugf("%d Нажатие кнопки в устаревшей версии бюллетеня", telegramID)
+		b.botAPI.Send(tgbotapi.NewCallback(query.ID, tr(ctx, "ballot.stale_press")))
+		return
+	}
+
+	candidates := b.currentCandidates()
+	rankedList := ballot.RankedList
+	review := false
+	switch callback.Action {
+	case ballotActionRank:
+		candidateID := callback.Arg
+		_, exists := candidates.byID[candidateID]
+		if !exists {
+			log.Warn(telegramID, " Попытка вписать неизвестного кандидата")
+			b.botAPI.Send(tgbotapi.NewCallback(query.ID, tr(ctx, "candidates.not_found")))
+			return
+		}
+		if contains(rankedList, candidateID) {
+			log.Warn(telegramID, " Попытка повторно вписать кандидата")
+			b.botAPI.Send(tgbotapi.NewCallback(query.ID, tr(ctx, "ballot.already_ranked")))
+			return
+		}
+		// Добавляем ID кандидата в список ранжирования
+		rankedList = append(rankedList, candidateID)
+		b.botAPI.Send(tgbotapi.NewCallback(query.ID, tr(ctx, "ballot.ranked")))
+	case ballotActionUndo:
+		if len(rankedList) > 0 {
+			rankedList = rankedList[:len(rankedList)-1]
+		}
+		b.botAPI.Send(tgbotapi.NewCallback(query.ID, tr(ctx, "ballot.undone")))
+	case ballotActionClear:
+		rankedList = []int{}
+		b.botAPI.Send(tgbotapi.NewCallback(query.ID, tr(ctx, "ballot.cleared")))
+	case ballotActionUp:
+		rankedList = moveCandidate(rankedList, callback.Arg, callback.Arg-1)
+		b.botAPI.Send(tgbotapi.NewCallback(query.ID, ""))
+	case ballotActionDown:
+		rankedList = moveCandidate(rankedList, callback.Arg, callback.Arg+1)
+		b.botAPI.Send(tgbotapi.NewCallback(query.ID, ""))
+	case ballotActionEdit:
+		b.botAPI.Send(tgbotapi.NewCallback(query.ID, ""))
+	case ballotActionReview, ballotActionSubmit:
+		// Проверяем, все ли кандидаты ранжированы и нет ли повторов
+		complete := len(rankedList) == len(candidates.byID) && isUniqueCandidates(rankedList)
+		if !complete {
+			log.Warn(telegramID, " Попытка отправить неполный бюллетень")
+			b.botAPI.Send(tgbotapi.NewCallback(query.ID, tr(ctx, "ballot.incomplete")))
+			break
+		}
+		b.botAPI.Send(tgbotapi.NewCallback(query.ID, ""))
+		if callback.Action == ballotActionSubmit {
+			b.sendRankedList(ctx, query, rankedList)
 			return
 		}
-		// Отправка бюллетеня
-		b.mu.Unlock()
-		b.sendRankedList(ctx, query)
-		// TODO удаление списка ранжирования (Не имеет смысла при текущей реализации порчи бюллетеня)
+		review = true
+	default:
+		log.Errorf("%d Неизвестное действие кнопки бюллетеня: %q", telegramID, query.Data)
+		b.SendMessage(telegramID, tr(ctx, "ballot.button_failed"))
 		return
 	}
 
+	// Каждое обновление сообщения получает новую версию, чтобы кнопки прежней версии не применялись
+	ballot.RankedList = rankedList
+	ballot.Seq++
+	if err := b.setBallot(ctx, telegramID, ballot); err != nil {
+		log.Errorf("%d Ошибка сохранения бюллетеня: %v", telegramID, err)
+		b.SendMessage(telegramID, tr(ctx, "ballot.button_failed"))
+		return
+	}
+	if review {
+		b.sendReview(ctx, query, ballot)
+		return
+	}
 	// ждем следующую отмеку в бюллетене
-	b.mu.Unlock()
-	b.sendCandidateKeyboard(ctx, query.Message, true)
+	b.sendCandidateKeyboard(ctx, query.Message, ballot, true)
+}
+
+// Проверка бюллетеня перед отправкой
+func (b *Bot) sendReview(ctx context.Context, query *tgbotapi.CallbackQuery, ballot ballotSession) {
+	telegramID := query.From.ID
+	msgText := tr(ctx, "ballot.review_header") + "\n\n" + b.currentCandidates().rankedListText(ballot.RankedList) + "\n" + tr(ctx, "ballot.review_footer")
+
+	keyboard := tgbotapi.NewInlineKeyboardMarkup(tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData(tr(ctx, "ballot.edit"), ballotCallbackData(ballot, ballotActionEdit, 0)),
+		tgbotapi.NewInlineKeyboardButtonData(tr(ctx, "ballot.submit"), ballotCallbackData(ballot, ballotActionSubmit, 0)),
+	))
+	msg := tgbotapi.NewEditMessageTextAndMarkup(telegramID, query.Message.MessageID, msgText, keyboard)
+	if _, err := b.botAPI.Send(msg); err != nil {
+		log.Errorf("%d ошибка отправки бюллетеня на проверку: %v", telegramID, err)
+	}
 }
 
 // Отправка заполненного бюллетеня
-func (b *Bot) sendRankedList(ctx context.Context, query *tgbotapi.CallbackQuery) {
+func (b *Bot) sendRankedList(ctx context.Context, query *tgbotapi.CallbackQuery, rankedList []int) {
 	telegramID := query.From.ID
-	b.mu.RLock()
-	defer b.mu.RUnlock()
 	// Отправляем бюллетень и удаляем клавиатуру
-	msgText := "Ваш итоговый бюллетень:\n\n"
-	for i, candidateID := range b.rankedList[telegramID] {
-		msgText += fmt.Sprintf("%d. %s\n", i+1, b.Candidates[candidateID].Name)
-	}
+	msgText := tr(ctx, "ballot.final_header") + "\n\n" + b.currentCandidates().rankedListText(rankedList)
+
 	editMsg := tgbotapi.NewEditMessageText(telegramID, query.Message.MessageID, msgText)
 	if _, err := b.botAPI.Send(editMsg); err != nil {
 		log.Errorf("%d ошибка отправки заполненного бюллетеня: %v", telegramID, err)
 	}
 	// Запись голоса в базу данных
-	log.Debugf("%d rankedList: %v", telegramID, b.rankedList[telegramID])
-	err := b.voteChain.AddVote(ctx, telegramID, b.rankedList[telegramID])
+	log.Debugf("%d rankedList: %v", telegramID, rankedList)
+	err := b.voteChain.AddVote(ctx, telegramID, rankedList)
 	if err != nil {
 		log.Errorf("%d ошибка регистрации голоса: %v", telegramID, err)
-		b.SendMessage(telegramID, "Произошла ошибка при регистрации голоса. Пожалуйста, попробуйте снова")
+		b.SendMessage(telegramID, tr(ctx, "vote.save_failed"))
 		return
 	}
+	// Бюллетень записан, остальные сообщения-бюллетени становятся устаревшими
+	if err := b.clearBallot(ctx, telegramID); err != nil {
+		log.Errorf("%d Ошибка удаления незаполненного бюллетеня: %v", telegramID, err)
+	}
 
+	b.sendVoteAccepted(ctx, telegramID, rankedList)
+	log.Info(query.From.ID, " Голос учтен")
+}
+
+// Уведомление о принятии бюллетеня, возвращает токен голоса. Квитанция для /verify сохраняется,
+// только если уведомление доставлено
+func (b *Bot) sendVoteAccepted(ctx context.Context, telegramID int64, rankedList []int) string {
 	// Генерируем токен из telegramID (детерминированный, каждый раз одинаковый)
 	voteToken := utils.GenerateVoteToken(telegramID)
 
 	// Уведомляем, что голос учтен
-	successMessage := "<b>Ваш бюллетень принят✅</b>\n\n" +
-		"Вы можете изменить свой бюллетень до окончания голосования, проголосовав заново, отправив для этого команду /vote\n\n" +
-		"🔑 <code>" + voteToken + "</code>"
+	successMessage := tr(ctx, "vote.accepted", voteToken)
 
 	if err := b.SendMessage(telegramID, successMessage); err != nil {
 		log.Errorf("%d ошибка ответа о принятии бюллетеня: %v", telegramID, err)
+		return voteToken
 	}
-	log.Info(query.From.ID, " Голос учтен")
+	if err := b.voteChain.ConfirmVote(ctx, telegramID, rankedList); err != nil {
+		log.Errorf("%d Ошибка сохранения квитанции бюллетеня: %v", telegramID, err)
+	}
+	return voteToken
 }
 
-// Порча бюллетеня
-func (b *Bot) spoilBallot(telegramID int64, message *tgbotapi.Message) {
-	spoiledTxt := fmt.Sprintf("%s\n\n❌Бюллетень испорчен❌", message.Text)
-	spoiledMsg := tgbotapi.NewEditMessageText(
-		telegramID,
-		message.MessageID,
-		spoiledTxt,
-	)
-	if _, err := b.botAPI.Send(spoiledMsg); err != nil {
-		log.Errorf("%d ошибка при попытке запретить испорченный бюллетень: %v", telegramID, err)
-	}
-	b.SendMessage(telegramID, "Пожалуйста, не используйте несколько бюллетеней одновременно. Используйте команду /vote для получения нового бюллетеня.")
+// removeBallotKeyboard убирает кнопки из устаревшего сообщения-бюллетеня, не меняя его текст
+func (b *Bot) removeBallotKeyboard(message *tgbotapi.Message) {
+	if message == nil {
+		return
+	}
+	edit := tgbotapi.NewEditMessageReplyMarkup(message.Chat.ID, message.MessageID,
+		tgbotapi.InlineKeyboardMarkup{InlineKeyboard: [][]tgbotapi.InlineKeyboardButton{}})
+	if _, err := b.botAPI.Send(edit); err != nil {
+		log.Errorf("%d Ошибка удаления кнопок устаревшего бюллетеня: %v", message.Chat.ID, err)
+	}
 }
 
 // Проверка уникальности кандидатов в списке
@@ -237,6 +374,50 @@ func isUniqueCandidates(rankedList []int) bool {
 	return true
 }
 
+// ballotCallback — разобранные данные кнопки бюллетеня
+type ballotCallback struct {
+	SessionID string // ID сессии бюллетеня
+	Seq       int    // Версия клавиатуры
+	Action    string // Действие
+	Arg       int    // ID кандидата или позиция в списке
+}
+
+// ballotCallbackData формирует данные кнопки бюллетеня для текущей версии сессии
+func ballotCallbackData(ballot ballotSession, action string, arg int) string {
+	return fmt.Sprintf("%s%s:%d:%s:%d", ballotCallbackPrefix, ballot.ID, ballot.Seq, action, arg)
+}
+
+// parseBallotCallbackData разбирает данные кнопки бюллетеня. false — данные не в формате сессии
+// (например, кнопка из бюллетеня, отправленного до появления сессий)
+func parseBallotCallbackData(data string) (ballotCallback, bool) {
+	rest, ok := strings.CutPrefix(data, ballotCallbackPrefix)
+	if !ok {
+		return ballotCallback{}, false
+	}
+	parts := strings.Split(rest, ":")
+	if len(parts) != 4 || parts[0] == "" {
+		return ballotCallback{}, false
+	}
+	seq, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return ballotCallback{}, false
+	}
+	arg, err := strconv.Atoi(parts[3])
+	if err != nil {
+		return ballotCallback{}, false
+	}
+	return ballotCallback{SessionID: parts[0], Seq: seq, Action: parts[2], Arg: arg}, true
+}
+
+// moveCandidate переставляет кандидата с позиции from на позицию to, игнорируя выход за границы списка
+func moveCandidate(rankedList []int, from, to int) []int {
+	if from < 0 || to < 0 || from >= len(rankedList) || to >= len(rankedList) {
+		return rankedList
+	}
+	rankedList[from], rankedList[to] = rankedList[to], rankedList[from]
+	return rankedList
+}
+
 // Проверка, есть ли элемент в слайсе
 func contains(s []int, e int) bool {
 	for _, a := range s {
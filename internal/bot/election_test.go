@@ -0,0 +1,25 @@
+package bot
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBallotCallbackData(t *testing.T) {
+	t.Parallel()
+
+	ballot := ballotSession{ID: "0a1b2c3d", Seq: 7}
+	data := ballotCallbackData(ballot, ballotActionRank, 123456)
+	assert.LessOrEqual(t, len(data), 64, "Telegram limits callback data to 64 bytes")
+
+	callback, ok := parseBallotCallbackData(data)
+	assert.True(t, ok)
+	assert.Equal(t, ballotCallback{SessionID: "0a1b2c3d", Seq: 7, Action: ballotActionRank, Arg: 123456}, callback)
+
+	// Кнопки бюллетеней без сессии и поврежденные данные не разбираются
+	for _, data := range []string{"123456", "up:1", "ballot:", "ballot:0a1b2c3d:x:up:1", "ballot::1:up:1", "ballot:0a1b2c3d:1:up"} {
+		_, ok := parseBallotCallbackData(data)
+		assert.False(t, ok, data)
+	}
+}
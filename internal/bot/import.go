@@ -0,0 +1,212 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	neturl "net/url"
+	"path/filepath"
+	"strings"
+
+	"github.com/lsdpls/schulze_election_telegram_bot/internal/models"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// Данные кнопок подтверждения импорта
+const (
+	importCallbackPrefix = "import:"
+	importActionApply    = importCallbackPrefix + "apply"
+	importActionCancel   = importCallbackPrefix + "cancel"
+)
+
+// Максимальный размер файла импорта
+const rosterMaxFileSize = 5 << 20
+
+// Обработчик команды /import
+func (b *Bot) handleImportHelp(ctx context.Context, message *tgbotapi.Message) {
+	b.botAPI.Send(tgbotapi.NewMessage(message.Chat.ID, tr(ctx, "import.help")))
+}
+
+// handleDocument принимает файл импорта от администратора и отправляет предпросмотр изменений
+func (b *Bot) handleDocument(ctx context.Context, message *tgbotapi.Message) {
+	chatID := message.Chat.ID
+	role, err := b.adminRole(ctx, message.Chat, message.From)
+	if err != nil {
+		log.Errorf("%d Ошибка проверки роли администратора: %v", chatID, err)
+		return
+	}
+	if role == "" {
+		b.handleText(ctx, message)
+		return
+	}
+	command, _ := findCommand("import")
+	if !command.allows(role) {
+		log.Warnf("%d Недостаточно прав для импорта (роль %s)", chatID, role)
+		b.SendMessage(chatID, tr(ctx, "import.forbidden"))
+		return
+	}
+
+	document := message.Document
+	extension := strings.ToLower(filepath.Ext(document.FileName))
+	if extension != ".csv" && extension != ".xlsx" {
+		b.SendMessage(chatID, tr(ctx, "import.unsupported_format"))
+		return
+	}
+	if document.FileSize > rosterMaxFileSize {
+		b.SendMessage(chatID, tr(ctx, "import.too_large"))
+		return
+	}
+
+	// Скачиваем и разбираем файл
+	body, err := b.downloadFile(ctx, document.FileID)
+	if err != nil {
+		log.Errorf("%d Ошибка загрузки файла импорта: %v", chatID, err)
+		b.SendMessage(chatID, tr(ctx, "import.download_failed"))
+		return
+	}
+	defer body.Close()
+	var records [][]string
+	if extension == ".csv" {
+		records, err = readRosterCSV(io.LimitReader(body, rosterMaxFileSize))
+	} else {
+		records, err = readRosterXLSX(io.LimitReader(body, rosterMaxFileSize))
+	}
+	if err != nil {
+		log.Warnf("%d Не удалось прочитать файл импорта: %v", chatID, err)
+		b.SendMessage(chatID, tr(ctx, "import.read_failed"))
+		return
+	}
+	file, errs := parseRoster(ctx, records)
+	if len(errs) > 0 {
+		log.Warnf("%d Файл импорта %s содержит %d ошибок", chatID, document.FileName, len(errs))
+		b.SendMessage(chatID, rosterErrorsText(ctx, errs))
+		return
+	}
+
+	// Сравниваем с текущими списками
+	delegates, err := b.voteChain.GetAllDelegates(ctx)
+	if err != nil {
+		log.Errorf("%d Ошибка при получении списка делегатов: %v", chatID, err)
+		return
+	}
+	candidates, err := b.voteChain.GetAllCandidates(ctx)
+	if err != nil {
+		log.Errorf("%d Ошибка при получении списка кандидатов: %v", chatID, err)
+		return
+	}
+	rosterImport, lines, warnings := diffRoster(ctx, file, delegates, candidates)
+	if rosterImport.IsEmpty() {
+		b.SendMessage(chatID, rosterImportSummary(ctx, rosterImport, lines, warnings)+"\n\n"+tr(ctx, "import.no_changes"))
+		return
+	}
+	if err := b.sessions.Set(ctx, rosterImportKey(chatID), rosterImport, rosterImportSessionTTL); err != nil {
+		log.Errorf("%d Ошибка сохранения импорта: %v", chatID, err)
+		return
+	}
+	log.Infof("%d Подготовлен импорт из файла %s", chatID, document.FileName)
+
+	msg := tgbotapi.NewMessage(chatID, rosterImportSummary(ctx, rosterImport, lines, warnings))
+	msg.ParseMode = "HTML"
+	msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData(tr(ctx, "import.apply"), importActionApply),
+		tgbotapi.NewInlineKeyboardButtonData(tr(ctx, "import.cancel"), importActionCancel),
+	))
+	if _, err := b.botAPI.Send(msg); err != nil {
+		log.Errorf("%d Ошибка отправки предпросмотра импорта: %v", chatID, err)
+	}
+}
+
+// handleImportCallback применяет или отменяет импорт по кнопке предпросмотра
+func (b *Bot) handleImportCallback(ctx context.Context, query *tgbotapi.CallbackQuery) {
+	b.botAPI.Send(tgbotapi.NewCallback(query.ID, ""))
+	if query.Message == nil {
+		return
+	}
+	chatID := query.Message.Chat.ID
+	role, err := b.adminRole(ctx, query.Message.Chat, query.From)
+	if err != nil {
+		log.Errorf("%d Ошибка проверки роли администратора: %v", chatID, err)
+		return
+	}
+	if command, _ := findCommand("import"); !command.allows(role) {
+		log.Warnf("%d Попытка подтвердить импорт без прав (пользователь %d)", chatID, query.From.ID)
+		return
+	}
+
+	// Убираем кнопки, чтобы импорт нельзя было применить повторно
+	b.botAPI.Send(tgbotapi.NewEditMessageReplyMarkup(chatID, query.Message.MessageID,
+		tgbotapi.InlineKeyboardMarkup{InlineKeyboard: [][]tgbotapi.InlineKeyboardButton{}}))
+
+	var rosterImport models.RosterImport
+	ok, err := b.sessions.Get(ctx, rosterImportKey(chatID), &rosterImport)
+	if err != nil {
+		log.Errorf("%d Ошибка получения импорта: %v", chatID, err)
+		return
+	}
+	if !ok {
+		b.SendMessage(chatID, tr(ctx, "import.expired"))
+		return
+	}
+	if err := b.sessions.Delete(ctx, rosterImportKey(chatID)); err != nil {
+		log.Errorf("%d Ошибка удаления импорта: %v", chatID, err)
+	}
+
+	if query.Data != importActionApply {
+		log.Infof("%d Импорт отменен", chatID)
+		b.SendMessage(chatID, tr(ctx, "import.cancelled"))
+		return
+	}
+	if err := b.voteChain.ApplyRosterImport(ctx, rosterImport); err != nil {
+		log.Errorf("%d Ошибка применения импорта: %v", chatID, err)
+		b.SendMessage(chatID, tr(ctx, "import.failed", html.EscapeString(err.Error())))
+		return
+	}
+	log.Warnf("%d Импорт применен пользователем %d: делегаты +%d ~%d -%d, кандидаты +%d ~%d -%d", chatID, query.From.ID,
+		len(rosterImport.AddDelegates), len(rosterImport.UpdateDelegates), len(rosterImport.DeleteDelegates),
+		len(rosterImport.AddCandidates), len(rosterImport.UpdateCandidates), len(rosterImport.DeleteCandidates))
+	b.SendMessage(chatID, tr(ctx, "import.applied"))
+}
+
+// downloadFile скачивает файл, отправленный боту
+func (b *Bot) downloadFile(ctx context.Context, fileID string) (io.ReadCloser, error) {
+	url, err := b.botAPI.GetFileDirectURL(fileID)
+	if err != nil {
+		return nil, fmt.Errorf("downloadFile: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("downloadFile: %w", err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		// Адрес файла содержит токен бота, поэтому в ошибку попадает только причина
+		var urlErr *neturl.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return nil, fmt.Errorf("downloadFile: request failed: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("downloadFile: unexpected status %s", resp.Status)
+	}
+	return resp.Body, nil
+}
+
+// rosterErrorsText формирует сообщение об ошибках в файле импорта
+func rosterErrorsText(ctx context.Context, errs []string) string {
+	const maxErrors = 20
+	text := tr(ctx, "import.errors", len(errs)) + "\n\n"
+	for i, err := range errs {
+		if i == maxErrors {
+			text += tr(ctx, "list.more", len(errs)-maxErrors) + "\n"
+			break
+		}
+		text += "• " + html.EscapeString(err) + "\n"
+	}
+	return text
+}
@@ -0,0 +1,105 @@
+package bot
+
+import (
+	"context"
+	"strings"
+
+	"github.com/lsdpls/schulze_election_telegram_bot/internal/i18n"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// Данные кнопок выбора языка: lang:<язык>
+const languageCallbackPrefix = "lang:"
+
+type languageKey struct{}
+
+// withLanguage сохраняет язык интерфейса пользователя в контексте
+func withLanguage(ctx context.Context, lang string) context.Context {
+	return context.WithValue(ctx, languageKey{}, lang)
+}
+
+// languageFromContext возвращает язык интерфейса пользователя или язык по умолчанию
+func languageFromContext(ctx context.Context) string {
+	if lang, ok := ctx.Value(languageKey{}).(string); ok {
+		return lang
+	}
+	return i18n.DefaultLanguage
+}
+
+// tr возвращает текст сообщения на языке пользователя
+func tr(ctx context.Context, key string, args ...any) string {
+	return i18n.T(languageFromContext(ctx), key, args...)
+}
+
+// trN возвращает текст сообщения на языке пользователя в форме множественного числа для n
+func trN(ctx context.Context, key string, n int, args ...any) string {
+	return i18n.N(languageFromContext(ctx), key, n, args...)
+}
+
+// savedLanguage возвращает язык, выбранный пользователем командой /language, или пустую строку.
+// Выбор кэшируется, чтобы не обращаться к базе данных на каждое обновление
+func (b *Bot) savedLanguage(ctx context.Context, telegramID int64) string {
+	if lang, ok := b.languages.Load(telegramID); ok {
+		return lang.(string)
+	}
+	lang, err := b.voteChain.GetUserLanguage(ctx, telegramID)
+	if err != nil {
+		log.Errorf("%d Ошибка получения языка пользователя: %v", telegramID, err)
+		return ""
+	}
+	b.languages.Store(telegramID, lang)
+	return lang
+}
+
+// userLanguage определяет язык интерфейса: выбранный пользователем или по language_code Telegram
+func (b *Bot) userLanguage(ctx context.Context, user *tgbotapi.User) string {
+	if user == nil {
+		return i18n.DefaultLanguage
+	}
+	if lang := b.savedLanguage(ctx, user.ID); lang != "" {
+		return lang
+	}
+	return i18n.Match(user.LanguageCode)
+}
+
+// chatLanguage определяет язык получателя рассылки. language_code известен только из обновлений,
+// поэтому пользователям, не выбравшим язык, рассылки приходят на языке по умолчанию
+func (b *Bot) chatLanguage(ctx context.Context, chatID int64) string {
+	if lang := b.savedLanguage(ctx, chatID); lang != "" {
+		return lang
+	}
+	return i18n.DefaultLanguage
+}
+
+// Обработчик команды /language
+func (b *Bot) handleLanguage(ctx context.Context, message *tgbotapi.Message) {
+	var row []tgbotapi.InlineKeyboardButton
+	for _, lang := range i18n.Languages {
+		row = append(row, tgbotapi.NewInlineKeyboardButtonData(i18n.LanguageNames[lang], languageCallbackPrefix+lang))
+	}
+	msg := tgbotapi.NewMessage(message.Chat.ID, tr(ctx, "language.choose"))
+	msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(row)
+	b.botAPI.Send(msg)
+}
+
+// handleLanguageCallback сохраняет язык, выбранный кнопкой
+func (b *Bot) handleLanguageCallback(ctx context.Context, query *tgbotapi.CallbackQuery) {
+	b.botAPI.Send(tgbotapi.NewCallback(query.ID, ""))
+	lang := strings.TrimPrefix(query.Data, languageCallbackPrefix)
+	if !i18n.IsSupported(lang) || query.Message == nil {
+		return
+	}
+	telegramID := query.From.ID
+	if err := b.voteChain.SetUserLanguage(ctx, telegramID, lang); err != nil {
+		log.Errorf("%d Ошибка сохранения языка: %v", telegramID, err)
+		b.SendMessage(query.Message.Chat.ID, tr(ctx, "error.generic"))
+		return
+	}
+	b.languages.Store(telegramID, lang)
+	log.Debugf("%d Выбран язык %s", telegramID, lang)
+
+	ctx = withLanguage(ctx, lang)
+	msg := tgbotapi.NewEditMessageText(query.Message.Chat.ID, query.Message.MessageID, tr(ctx, "language.changed"))
+	b.botAPI.Send(msg)
+}
@@ -0,0 +1,228 @@
+package bot
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/lsdpls/schulze_election_telegram_bot/internal/chain"
+	"github.com/lsdpls/schulze_election_telegram_bot/internal/config"
+	"github.com/lsdpls/schulze_election_telegram_bot/internal/models"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// Обработчик команды /proxy: без аргумента показывает доверенность делегата, с ID — назначает заместителя
+func (b *Bot) handleProxy(ctx context.Context, message *tgbotapi.Message) {
+	telegramID := message.Chat.ID
+	voter, err := b.voteChain.ResolveVoter(ctx, telegramID)
+	if err != nil {
+		log.Errorf("%d Ошибка при проверке делегата: %v", telegramID, err)
+		b.SendMessage(telegramID, tr(ctx, "error.delegate_check"))
+		return
+	}
+	// Заместитель не может передать голос дальше
+	if voter == nil || voter.ProxyID.Valid {
+		b.SendMessage(telegramID, tr(ctx, "proxy.delegates_only"))
+		return
+	}
+
+	args := commandArgsFromContext(ctx)
+	if !args.has("substitute_id") {
+		proxy, err := b.voteChain.GetActiveProxyByDelegateID(ctx, voter.DelegateID)
+		if err != nil {
+			log.Errorf("%d Ошибка при получении доверенности: %v", telegramID, err)
+			b.SendMessage(telegramID, tr(ctx, "error.generic"))
+			return
+		}
+		switch {
+		case proxy == nil:
+			b.SendMessage(telegramID, tr(ctx, "proxy.none"))
+		case proxy.TelegramID.Valid:
+			b.SendMessage(telegramID, tr(ctx, "proxy.status_verified", proxy.SubstituteID))
+		default:
+			b.SendMessage(telegramID, tr(ctx, "proxy.status_pending", proxy.SubstituteID))
+		}
+		return
+	}
+
+	text, err := b.nominateProxy(ctx, telegramID, models.Proxy{
+		DelegateID:   voter.DelegateID,
+		SubstituteID: args.number("substitute_id"),
+		NominatedBy:  sql.NullInt64{Int64: telegramID, Valid: true},
+	})
+	if err != nil {
+		log.Errorf("%d Ошибка назначения заместителя: %v", telegramID, err)
+		b.SendMessage(telegramID, tr(ctx, "error.generic"))
+		return
+	}
+	b.SendMessage(telegramID, text)
+}
+
+// Обработчик команды /revoke_proxy: делегат отзывает свою доверенность
+func (b *Bot) handleRevokeProxy(ctx context.Context, message *tgbotapi.Message) {
+	telegramID := message.Chat.ID
+	voter, err := b.voteChain.ResolveVoter(ctx, telegramID)
+	if err != nil {
+		log.Errorf("%d Ошибка при проверке делегата: %v", telegramID, err)
+		b.SendMessage(telegramID, tr(ctx, "error.delegate_check"))
+		return
+	}
+	if voter == nil || voter.ProxyID.Valid {
+		b.SendMessage(telegramID, tr(ctx, "proxy.delegates_only"))
+		return
+	}
+	text, err := b.revokeProxy(ctx, telegramID, voter.DelegateID, sql.NullInt64{Int64: telegramID, Valid: true})
+	if err != nil {
+		log.Errorf("%d Ошибка отзыва доверенности: %v", telegramID, err)
+		b.SendMessage(telegramID, tr(ctx, "error.generic"))
+		return
+	}
+	b.SendMessage(telegramID, text)
+}
+
+// Обработчик команды /set_proxy: администратор назначает делегату заместителя
+func (b *Bot) handleSetProxy(ctx context.Context, message *tgbotapi.Message) {
+	chatID := message.Chat.ID
+	args := commandArgsFromContext(ctx)
+	proxy := models.Proxy{
+		DelegateID:   args.number("delegate_id"),
+		SubstituteID: args.number("substitute_id"),
+	}
+	if message.From != nil {
+		proxy.NominatedBy = sql.NullInt64{Int64: message.From.ID, Valid: true}
+	}
+	text, err := b.nominateProxy(ctx, chatID, proxy)
+	if err != nil {
+		log.Errorf("%d Ошибка назначения заместителя: %v", chatID, err)
+		b.SendMessage(chatID, tr(ctx, "error.generic"))
+		return
+	}
+	b.SendMessage(chatID, text)
+}
+
+// Обработчик команды /cancel_proxy: администратор отзывает доверенность делегата
+func (b *Bot) handleCancelProxy(ctx context.Context, message *tgbotapi.Message) {
+	chatID := message.Chat.ID
+	delegateID := commandArgsFromContext(ctx).number("delegate_id")
+	var revokedBy sql.NullInt64
+	if message.From != nil {
+		revokedBy = sql.NullInt64{Int64: message.From.ID, Valid: true}
+	}
+	text, err := b.revokeProxy(ctx, chatID, delegateID, revokedBy)
+	if err != nil {
+		log.Errorf("%d Ошибка отзыва доверенности: %v", chatID, err)
+		b.SendMessage(chatID, tr(ctx, "error.generic"))
+		return
+	}
+	b.SendMessage(chatID, text)
+}
+
+// Обработчик команды /proxies: все доверенности, включая отозванные
+func (b *Bot) handleProxies(ctx context.Context, message *tgbotapi.Message) {
+	chatID := message.Chat.ID
+	proxies, err := b.voteChain.GetAllProxies(ctx)
+	if err != nil {
+		log.Errorf("%d Ошибка при получении списка доверенностей: %v", chatID, err)
+		return
+	}
+	if len(proxies) == 0 {
+		b.SendMessage(chatID, tr(ctx, "proxy.list_none"))
+		return
+	}
+	text := tr(ctx, "proxy.header")
+	for _, proxy := range proxies {
+		text += "\n" + proxyLine(ctx, proxy)
+	}
+	b.SendMessage(chatID, text)
+}
+
+// nominateProxy назначает заместителя и уведомляет делегата, если заместителя назначил не он сам.
+// Отказ цепочки описывается текстом для отправителя команды, как и успешное назначение
+func (b *Bot) nominateProxy(ctx context.Context, chatID int64, proxy models.Proxy) (string, error) {
+	if _, err := b.voteChain.NominateProxy(ctx, proxy); err != nil {
+		if text, ok := proxyErrorText(ctx, err, proxy.DelegateID, proxy.SubstituteID); ok {
+			return text, nil
+		}
+		return "", fmt.Errorf("nominateProxy: %w", err)
+	}
+	log.Warnf("%d Делегату st%06d назначен заместитель st%06d", chatID, proxy.DelegateID, proxy.SubstituteID)
+
+	delegate, err := b.voteChain.GetDelegateByDelegateID(ctx, proxy.DelegateID)
+	if err != nil {
+		log.Errorf("%d Ошибка при получении делегата: %v", chatID, err)
+	} else if delegate != nil && delegate.TelegramID.Valid && delegate.TelegramID != proxy.NominatedBy {
+		b.notifyProxyChange(ctx, delegate.TelegramID.Int64, "proxy.notice_nominated", proxy.SubstituteID)
+	}
+	return tr(ctx, "proxy.nominated", proxy.SubstituteID, proxy.DelegateID, proxy.SubstituteID), nil
+}
+
+// revokeProxy отзывает доверенность делегата, убирает незаполненный бюллетень заместителя и уведомляет
+// заместителя и делегата, если доверенность отозвал не он сам. Возвращает текст для отправителя команды
+func (b *Bot) revokeProxy(ctx context.Context, chatID int64, delegateID int, revokedBy sql.NullInt64) (string, error) {
+	proxy, err := b.voteChain.RevokeProxy(ctx, delegateID, revokedBy)
+	if err != nil {
+		if text, ok := proxyErrorText(ctx, err, delegateID, 0); ok {
+			return text, nil
+		}
+		return "", fmt.Errorf("revokeProxy: %w", err)
+	}
+	log.Warnf("%d Доверенность делегата st%06d на st%06d отозвана", chatID, delegateID, proxy.SubstituteID)
+
+	if substitute := proxy.TelegramID; substitute.Valid {
+		if err := b.clearBallot(ctx, substitute.Int64); err != nil {
+			log.Errorf("%d Ошибка удаления незаполненного бюллетеня: %v", substitute.Int64, err)
+		}
+		b.notifyProxyChange(ctx, substitute.Int64, "proxy.substitute_revoked", delegateID)
+	}
+	delegate, err := b.voteChain.GetDelegateByDelegateID(ctx, delegateID)
+	if err != nil {
+		log.Errorf("%d Ошибка при получении делегата: %v", chatID, err)
+	} else if delegate != nil && delegate.TelegramID.Valid && delegate.TelegramID != revokedBy {
+		b.notifyProxyChange(ctx, delegate.TelegramID.Int64, "proxy.notice_revoked")
+	}
+	return tr(ctx, "proxy.revoked", proxy.SubstituteID, delegateID), nil
+}
+
+// proxyErrorText переводит отказ цепочки в назначении или отзыве доверенности в текст для отправителя команды.
+// Для прочих ошибок возвращает false
+func proxyErrorText(ctx context.Context, err error, delegateID, substituteID int) (string, bool) {
+	switch {
+	case errors.Is(err, chain.ErrDelegateNotFound):
+		return tr(ctx, "admin.delegate_not_found", delegateID), true
+	case errors.Is(err, chain.ErrProxySelf):
+		return tr(ctx, "proxy.self"), true
+	case errors.Is(err, chain.ErrSubstituteIsDelegate):
+		return tr(ctx, "proxy.substitute_is_delegate", substituteID), true
+	case errors.Is(err, chain.ErrProxyExists):
+		return tr(ctx, "proxy.already_exists", delegateID), true
+	case errors.Is(err, chain.ErrSubstituteBusy):
+		return tr(ctx, "proxy.substitute_busy", substituteID), true
+	case errors.Is(err, chain.ErrProxyNotFound):
+		return tr(ctx, "proxy.no_active", delegateID), true
+	}
+	return "", false
+}
+
+// notifyProxyChange сообщает делегату или заместителю об изменении доверенности на его языке
+func (b *Bot) notifyProxyChange(ctx context.Context, telegramID int64, key string, args ...any) {
+	userCtx := withLanguage(ctx, b.chatLanguage(ctx, telegramID))
+	if err := b.SendMessage(telegramID, tr(userCtx, key, args...)); err != nil {
+		log.Debugf("%d Не удалось уведомить об изменении доверенности: %v", telegramID, err)
+	}
+}
+
+// proxyLine описывает доверенность одной строкой для /proxies
+func proxyLine(ctx context.Context, proxy models.Proxy) string {
+	line := tr(ctx, "proxy.line", proxy.DelegateID, proxy.SubstituteID, formatScheduleTime(proxy.CreatedAt, config.ElectionTimezone))
+	if proxy.TelegramID.Valid {
+		line += ", " + tr(ctx, "audit.account", proxy.TelegramID.Int64, proxy.TelegramID.Int64)
+	} else {
+		line += ", " + tr(ctx, "proxy.line_pending")
+	}
+	if proxy.RevokedAt.Valid {
+		line += ", " + tr(ctx, "proxy.line_revoked", formatScheduleTime(proxy.RevokedAt.Time, config.ElectionTimezone))
+	}
+	return line
+}
@@ -0,0 +1,69 @@
+package bot
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/lsdpls/schulze_election_telegram_bot/internal/chain"
+	"github.com/lsdpls/schulze_election_telegram_bot/internal/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProxyLine(t *testing.T) {
+	t.Parallel()
+
+	ctx := withLanguage(context.Background(), "ru")
+	proxy := models.Proxy{
+		DelegateID:   123456,
+		SubstituteID: 654321,
+		CreatedAt:    time.Date(2026, 10, 19, 12, 30, 0, 0, time.UTC),
+	}
+	line := proxyLine(ctx, proxy)
+	assert.Contains(t, line, "st123456 → st654321")
+	assert.Contains(t, line, "19.10.2026 12:30")
+	assert.Contains(t, line, "почта не подтверждена")
+	assert.NotContains(t, line, "отозвана")
+
+	proxy.TelegramID = sql.NullInt64{Int64: 42, Valid: true}
+	proxy.RevokedAt = sql.NullTime{Time: time.Date(2026, 10, 20, 9, 0, 0, 0, time.UTC), Valid: true}
+	line = proxyLine(ctx, proxy)
+	assert.Contains(t, line, `<a href="tg://user?id=42">42</a>`)
+	assert.Contains(t, line, "отозвана 20.10.2026 09:00")
+}
+
+// Отказы цепочки переводятся в тексты proxy.*, прочие ошибки остаются ошибками
+func TestProxyErrorText(t *testing.T) {
+	t.Parallel()
+
+	ctx := withLanguage(context.Background(), "ru")
+	text, ok := proxyErrorText(ctx, fmt.Errorf("chain.NominateProxy: %w", chain.ErrSubstituteIsDelegate), 123456, 654321)
+	assert.True(t, ok)
+	assert.Contains(t, text, "st654321 — делегат")
+
+	text, ok = proxyErrorText(ctx, fmt.Errorf("chain.RevokeProxy: %w", chain.ErrProxyNotFound), 123456, 0)
+	assert.True(t, ok)
+	assert.Contains(t, text, "st123456")
+
+	_, ok = proxyErrorText(ctx, errors.New("connection refused"), 123456, 654321)
+	assert.False(t, ok)
+}
+
+func TestAuditLineProxy(t *testing.T) {
+	t.Parallel()
+
+	ctx := withLanguage(context.Background(), "ru")
+	record := models.AuditRecord{
+		Action:      models.AuditProxyNominated,
+		DelegateID:  123456,
+		PerformedBy: sql.NullInt64{Int64: 7, Valid: true},
+		Details:     "654321",
+	}
+	line := auditLine(ctx, record)
+	assert.Contains(t, line, "назначен заместитель")
+	assert.Contains(t, line, "заместитель st654321")
+}
@@ -2,15 +2,22 @@ package bot
 
 import (
 	"context"
+	"crypto/rand"
+	"crypto/subtle"
 	"database/sql"
+	"errors"
 	"fmt"
-	"math/rand"
+	"math/big"
 	"regexp"
 	"strconv"
 	"strings"
 	"time"
 
+	"github.com/lsdpls/schulze_election_telegram_bot/internal/chain"
+	"github.com/lsdpls/schulze_election_telegram_bot/internal/config"
 	emailSender "github.com/lsdpls/schulze_election_telegram_bot/internal/email"
+	"github.com/lsdpls/schulze_election_telegram_bot/internal/models"
+	"github.com/lsdpls/schulze_election_telegram_bot/internal/utils"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 )
@@ -18,28 +25,32 @@ import (
 // Обработчик команды /start
 func (b *Bot) handleStart(ctx context.Context, message *tgbotapi.Message) {
 
-	//Проверка на то, что пользователь уже зарегистрирован
-	ok, err := b.voteChain.CheckExistDelegateByTelegramID(ctx, message.Chat.ID)
+	//Проверка на то, что пользователь уже зарегистрирован как делегат или заместитель
+	voter, err := b.voteChain.ResolveVoter(ctx, message.Chat.ID)
 	if err != nil {
 		log.Errorf("%d Ошибка при проверке делегата: %v", message.Chat.ID, err)
-		b.SendMessage(message.Chat.ID, "Произошла ошибка при проверке делегата. Пожалуйста, попробуйте снова")
+		b.SendMessage(message.Chat.ID, tr(ctx, "error.delegate_check"))
 		return
 	}
-	if ok {
+	if voter != nil {
 		log.Warn(message.Chat.ID, " Попытка повторной регистрации")
-		b.SendMessage(message.Chat.ID, "Вы уже зарегистрированы! Используйте команду /vote для голосования")
+		b.SendMessage(message.Chat.ID, tr(ctx, "start.already_registered"))
+		return
+	}
+	if b.isVerificationLocked(ctx, message.Chat.ID) {
+		return
+	}
+	if !b.checkScheduleWindow(ctx, message.Chat.ID, models.ScheduleRegistration) {
 		return
 	}
 
 	// Отправляем приветственное сообщение
-	b.SendMessage(message.Chat.ID, "Добро пожаловать в бот для голосования на выборах в Студенческий совет ПМ-ПУ!\n\n"+
-		"Чтобы начать голосование, необходимо пройти регистрацию.\n"+
-		"<b>Пожалуйста, введите свою st почту (в формате: stXXXXXX)</b>")
+	b.SendMessage(message.Chat.ID, tr(ctx, "start.welcome"))
 
 	// Устанавливаем состояние ожидания почты
-	b.mu.Lock()
-	defer b.mu.Unlock()
-	b.userStates[message.Chat.ID] = StateWaitingForEmail
+	if err := b.setRegistration(ctx, message.Chat.ID, registrationSession{State: StateWaitingForEmail}); err != nil {
+		log.Errorf("%d Ошибка сохранения состояния регистрации: %v", message.Chat.ID, err)
+	}
 }
 
 // Обработчик ввода почты
@@ -47,111 +58,320 @@ func (b *Bot) handleEmailInput(ctx context.Context, message *tgbotapi.Message) {
 	email := strings.TrimSpace(message.Text)
 	telegramID := message.Chat.ID
 
+	if b.isVerificationLocked(ctx, telegramID) {
+		return
+	}
+	if !b.checkScheduleWindow(ctx, telegramID, models.ScheduleRegistration) {
+		return
+	}
 	// Проверяем формат email
 	if !isValidEmail(email) {
 		log.Debug(telegramID, " Неверный формат почты")
-		b.SendMessage(telegramID, "Неверный формат почты. Пожалуйста, введите почту в формате stXXXXXX.")
+		b.SendMessage(telegramID, tr(ctx, "registration.invalid_email"))
 		return
 	}
 	// Извлекаем ID делегата из почты
 	delegateID, err := strconv.Atoi(email[2:])
 	if err != nil {
 		log.Errorf("%d Ошибка при извлечении ID делегата из почты: %v", telegramID, err)
-		b.SendMessage(telegramID, "Невозможно получить ID делегата из почты. Пожалуйста, попробуйте снова")
+		b.SendMessage(telegramID, tr(ctx, "registration.invalid_delegate_id"))
 		return
 	}
 	// Проверяем, существует ли такой делегат в базе данных
 	ok, err := b.voteChain.CheckExistDelegateByDelegateID(ctx, delegateID)
 	if err != nil {
 		log.Errorf("%d Ошибка проверки существования делегата: %v", telegramID, err)
-		b.SendMessage(telegramID, "Произошла ошибка при проверке делегата. Пожалуйста, попробуйте снова")
+		b.SendMessage(telegramID, tr(ctx, "error.delegate_check"))
 		return
 	}
+	// Почта не делегата может принадлежать заместителю по действующей доверенности
+	var proxy *models.Proxy
 	if !ok {
+		proxy, err = b.voteChain.GetActiveProxyBySubstituteID(ctx, delegateID)
+		if err != nil {
+			log.Errorf("%d Ошибка проверки доверенности: %v", telegramID, err)
+			b.SendMessage(telegramID, tr(ctx, "error.delegate_check"))
+			return
+		}
+	}
+	switch {
+	case !ok && proxy == nil:
 		log.Warn(telegramID, " Попытка регистрации несуществующего делегата")
-		b.SendMessage(telegramID, "Такой делегат не найден. Убедитесь, что вы ввели правильную почту.")
+		b.SendMessage(telegramID, tr(ctx, "registration.delegate_not_found"))
+		return
+	case proxy != nil:
+		ok = proxy.TelegramID.Valid
+	default:
+		// Проверяем, зарегистрирован ли уже делегат с такой почтой
+		ok, err = b.voteChain.CheckFerification(ctx, delegateID)
+		if err != nil {
+			log.Errorf("%d Ошибка проверки уникальности делегата: %v", telegramID, err)
+			b.SendMessage(telegramID, tr(ctx, "error.delegate_check"))
+			return
+		}
+	}
+	if ok {
+		log.Warn(telegramID, " Попытка регистрации уже зарегистрированного делегата")
+		b.SendMessage(telegramID, tr(ctx, "registration.already_verified"))
+		return
+	}
+
+	// Проверяем паузу между отправками кода: для аккаунта и для почты (чтобы нельзя было засыпать письмами чужую почту)
+	verification, err := b.getVerification(ctx, telegramID)
+	if err != nil {
+		log.Errorf("%d Ошибка получения состояния верификации: %v", telegramID, err)
+		b.SendMessage(telegramID, tr(ctx, "registration.send_code_failed"))
+		return
+	}
+	if wait := time.Until(verification.LastSentAt.Add(config.VerificationResendCooldown)); wait > 0 {
+		log.Warnf("%d Повторный запрос кода до истечения паузы (st%06d)", telegramID, delegateID)
+		seconds := int(wait.Seconds()) + 1
+		b.SendMessage(telegramID, trN(ctx, "registration.resend_cooldown", seconds, seconds))
 		return
 	}
-	// Проверяем, зарегистрирован ли уже делегат с такой почтой
-	ok, err = b.voteChain.CheckFerification(ctx, delegateID)
+	var emailCooldown bool
+	ok, err = b.sessions.Get(ctx, emailCooldownKey(delegateID), &emailCooldown)
 	if err != nil {
-		log.Errorf("%d Ошибка проверки уникальности делегата: %v", telegramID, err)
-		b.SendMessage(telegramID, "Произошла ошибка при проверке делегата. Пожалуйста, попробуйте снова")
+		log.Errorf("%d Ошибка проверки паузы отправки кода: %v", telegramID, err)
+		b.SendMessage(telegramID, tr(ctx, "registration.send_code_failed"))
 		return
 	}
 	if ok {
-		log.Warn(telegramID, " Попытка регистрации уже зарегистрированного делегата")
-		b.SendMessage(telegramID, "Делегат с такой почтой уже зарегистрировался")
+		log.Warnf("%d Запрос кода на почту st%06d до истечения паузы", telegramID, delegateID)
+		b.SendMessage(telegramID, tr(ctx, "registration.email_cooldown"))
+		return
+	}
+
+	if notice, limited := b.throttled(ctx, rateEmails, telegramID); limited {
+		if notice != "" {
+			b.SendMessage(telegramID, notice)
+		}
 		return
 	}
 
 	// Генерируем и отправляем код
-	code := generateCode()
+	code, err := generateCode()
+	if err != nil {
+		log.Errorf("%d Ошибка генерации кода подтверждения: %v", telegramID, err)
+		b.SendMessage(telegramID, tr(ctx, "registration.send_code_failed"))
+		return
+	}
 	email = fmt.Sprintf("%s@student.spbu.ru", email)
 	if err := emailSender.SendVerificationCodeToEmail(email, code); err != nil {
 		log.Errorf("%d Ошибка отправки кода на почту: %v", telegramID, err)
-		b.SendMessage(telegramID, "Произошла ошибка при отправке кода на почту. Пожалуйста, попробуйте снова")
+		b.SendMessage(telegramID, tr(ctx, "registration.send_code_failed"))
+		return
+	}
+	log.Infof("%d Код подтверждения отправлен на почту st%06d", telegramID, delegateID)
+
+	// Запоминаем время отправки для пауз между повторными запросами
+	now := time.Now()
+	verification.LastSentAt = now
+	if err := b.setVerification(ctx, telegramID, verification); err != nil {
+		log.Errorf("%d Ошибка сохранения состояния верификации: %v", telegramID, err)
+	}
+	if err := b.sessions.Set(ctx, emailCooldownKey(delegateID), true, config.VerificationResendCooldown); err != nil {
+		log.Errorf("%d Ошибка сохранения паузы отправки кода: %v", telegramID, err)
+	}
+	// Сохраняем сгенерированный код и ID делегата для дальнейшей проверки, устанавливаем состояние ожидания кода
+	if err := b.setRegistration(ctx, telegramID, registrationSession{
+		State:         StateWaitingForCode,
+		Code:          code,
+		DelegateID:    delegateID,
+		CodeExpiresAt: now.Add(config.VerificationCodeTTL),
+		Proxy:         proxy != nil,
+	}); err != nil {
+		log.Errorf("%d Ошибка сохранения кода подтверждения: %v", telegramID, err)
+		b.SendMessage(telegramID, tr(ctx, "registration.send_code_failed"))
 		return
 	}
-	log.Debugf("%d Код подтверждения %d отправлен на почту", telegramID, code)
-	// Сохраняем сгенерированный код в мапу для дальнейшей проверки
-	b.mu.Lock()
-	defer b.mu.Unlock()
-	b.codeStore[telegramID] = code
-	if err := b.SendMessage(telegramID, "Код подтверждения отправлен на ваш email. Пожалуйста, введите код.\nЕсли Вы не видите пиьсмо - проверьте Спам или обратитесь к организаторам"); err != nil {
+	minutes := int(config.VerificationCodeTTL.Minutes())
+	if err := b.SendMessage(telegramID, trN(ctx, "registration.code_sent", minutes, minutes)); err != nil {
 		log.Errorf("%d Ошибка уведомления об отправке кода: %v", telegramID, err)
 	}
-
-	// Устанавливаем email в мапу для дальнейшего использования
-	b.userEmail[telegramID] = delegateID
-	// Устанавливаем состояние ожидания кода
-	b.userStates[telegramID] = StateWaitingForCode
 }
 
 // Обработчик ввода кода
 func (b *Bot) handleCodeInput(ctx context.Context, message *tgbotapi.Message) {
 	telegramID := message.Chat.ID
 
+	if b.isVerificationLocked(ctx, telegramID) {
+		return
+	}
+	// Повторный ввод почты в ожидании кода — запрос нового кода (с учетом паузы)
+	if isValidEmail(strings.TrimSpace(message.Text)) {
+		b.handleEmailInput(ctx, message)
+		return
+	}
+	if notice, limited := b.throttled(ctx, rateCodes, telegramID); limited {
+		if notice != "" {
+			b.SendMessage(telegramID, notice)
+		}
+		return
+	}
 	// Извлекаем введенный код
-	code, err := strconv.Atoi(message.Text)
+	code, err := strconv.Atoi(strings.TrimSpace(message.Text))
 	if err != nil {
 		log.Debug(telegramID, " Неверный формат кода")
-		b.SendMessage(telegramID, "Неверный формат кода. Пожалуйста, введите числовой код.")
+		b.SendMessage(telegramID, tr(ctx, "registration.invalid_code_format"))
 		return
 	}
 	// Проверяем, есть ли сгенерированный код для этого пользователя
-	b.mu.Lock()
-	defer b.mu.Unlock()
-	expectedCode, ok := b.codeStore[telegramID]
-	if !ok {
+	registration, ok, err := b.getRegistration(ctx, telegramID)
+	if err != nil {
+		log.Errorf("%d Ошибка получения кода подтверждения: %v", telegramID, err)
+		b.SendMessage(telegramID, tr(ctx, "registration.code_check_failed"))
+		return
+	}
+	if !ok || registration.Code == 0 {
 		log.Error(telegramID, " Не найден код для подтверждения")
-		b.SendMessage(telegramID, "Не найден код для подтверждения. Попробуйте начать регистрацию заново.")
+		b.SendMessage(telegramID, tr(ctx, "registration.code_not_found"))
 		return
 	}
-	// Сравниваем введенный код с ожидаемым
-	if code != expectedCode {
-		log.Debug(telegramID, " Неверный код")
-		b.SendMessage(telegramID, "Неверный код. Попробуйте еще раз.")
+	// Проверяем срок действия кода
+	if time.Now().After(registration.CodeExpiresAt) {
+		log.Warnf("%d Истек срок действия кода для st%06d", telegramID, registration.DelegateID)
+		b.resetToEmailInput(ctx, telegramID)
+		b.SendMessage(telegramID, tr(ctx, "registration.code_expired"))
 		return
 	}
-
-	// Верифицируем делегата
-	delegateID := b.userEmail[telegramID]
-	if err := b.voteChain.VerificateDelegate(ctx, delegateID, sql.NullInt64{Int64: telegramID, Valid: true}); err != nil {
-		log.Errorf("%d Ошибка верификации делегата: %v", telegramID, err)
-		b.SendMessage(telegramID, "Произошла ошибка при верификации. Пожалуйста, попробуйте снова")
+	// Сравниваем введенный код с ожидаемым за постоянное время
+	if subtle.ConstantTimeCompare([]byte(strconv.Itoa(code)), []byte(strconv.Itoa(registration.Code))) != 1 {
+		b.handleWrongCode(ctx, telegramID, registration)
 		return
 	}
-	// Уведомляем пользователя о завершении регистрации
-	if err := b.SendMessage(telegramID, "Регистрация успешно завершена! Используйте команду /vote для голосования"); err != nil {
-		log.Errorf("%d Ошибка уведомления о завершении регистрации: %v", telegramID, err)
+
+	delegateID := registration.DelegateID
+	if registration.Proxy {
+		if !b.verifyProxy(ctx, telegramID, delegateID) {
+			return
+		}
+	} else {
+		// Верифицируем делегата
+		if err := b.voteChain.VerificateDelegate(ctx, delegateID, sql.NullInt64{Int64: telegramID, Valid: true}); err != nil {
+			log.Errorf("%d Ошибка верификации делегата: %v", telegramID, err)
+			b.SendMessage(telegramID, tr(ctx, "registration.verification_failed"))
+			return
+		}
+		// Уведомляем пользователя о завершении регистрации
+		if err := b.SendMessage(telegramID, tr(ctx, "registration.completed")); err != nil {
+			log.Errorf("%d Ошибка уведомления о завершении регистрации: %v", telegramID, err)
+		}
+		log.Info(telegramID, " Регистрация прошла успешно")
+		// После сброса регистрации голос делегата переходит к новому аккаунту вместе с новым токеном проверки
+		if delegate, err := b.voteChain.GetDelegateByDelegateID(ctx, delegateID); err != nil {
+			log.Errorf("%d Ошибка получения делегата: %v", telegramID, err)
+		} else if delegate != nil && delegate.HasVoted {
+			b.SendMessage(telegramID, tr(ctx, "registration.vote_transferred", utils.GenerateVoteToken(telegramID)))
+		}
 	}
-	log.Info(telegramID, " Регистрация прошла успешно")
 
 	// Сбрасываем состояния пользователя
-	delete(b.userStates, telegramID)
-	delete(b.codeStore, telegramID)
-	delete(b.userEmail, telegramID)
+	if err := b.clearRegistration(ctx, telegramID); err != nil {
+		log.Errorf("%d Ошибка сброса состояния регистрации: %v", telegramID, err)
+	}
+	if err := b.clearVerification(ctx, telegramID); err != nil {
+		log.Errorf("%d Ошибка сброса счетчиков верификации: %v", telegramID, err)
+	}
+}
+
+// verifyProxy привязывает аккаунт заместителя, подтвердившего почту, и уведомляет делегата.
+// Возвращает false, если доверенность отозвана, пока заместитель вводил код
+func (b *Bot) verifyProxy(ctx context.Context, telegramID int64, substituteID int) bool {
+	proxy, err := b.voteChain.VerifyProxy(ctx, substituteID, sql.NullInt64{Int64: telegramID, Valid: true})
+	if errors.Is(err, chain.ErrProxyNotFound) {
+		log.Warnf("%d Доверенность заместителя st%06d отозвана или уже подтверждена", telegramID, substituteID)
+		if err := b.clearRegistration(ctx, telegramID); err != nil {
+			log.Errorf("%d Ошибка сброса состояния регистрации: %v", telegramID, err)
+		}
+		b.SendMessage(telegramID, tr(ctx, "registration.proxy_revoked"))
+		return false
+	}
+	if err != nil {
+		log.Errorf("%d Ошибка верификации заместителя: %v", telegramID, err)
+		b.SendMessage(telegramID, tr(ctx, "registration.verification_failed"))
+		return false
+	}
+	if err := b.SendMessage(telegramID, tr(ctx, "registration.proxy_completed", proxy.DelegateID)); err != nil {
+		log.Errorf("%d Ошибка уведомления о завершении регистрации: %v", telegramID, err)
+	}
+	log.Infof("%d Заместитель st%06d делегата st%06d зарегистрирован", telegramID, substituteID, proxy.DelegateID)
+
+	delegate, err := b.voteChain.GetDelegateByDelegateID(ctx, proxy.DelegateID)
+	if err != nil {
+		log.Errorf("%d Ошибка получения делегата: %v", telegramID, err)
+	} else if delegate != nil && delegate.TelegramID.Valid {
+		b.notifyProxyChange(ctx, delegate.TelegramID.Int64, "proxy.notice_verified", substituteID)
+	}
+	return true
+}
+
+// handleWrongCode учитывает неверную попытку: для текущего кода и для аккаунта в целом
+func (b *Bot) handleWrongCode(ctx context.Context, telegramID int64, registration registrationSession) {
+	verification, err := b.getVerification(ctx, telegramID)
+	if err != nil {
+		log.Errorf("%d Ошибка получения состояния верификации: %v", telegramID, err)
+		b.SendMessage(telegramID, tr(ctx, "registration.code_check_failed"))
+		return
+	}
+	verification.FailedAttempts++
+	registration.CodeAttempts++
+
+	// Превышен лимит попыток аккаунта: блокировка до разблокировки администратором
+	if verification.FailedAttempts >= config.VerificationMaxAccountAttempts {
+		verification.Locked = true
+		verification.LockedAt = time.Now()
+		if err := b.setVerification(ctx, telegramID, verification); err != nil {
+			log.Errorf("%d Ошибка блокировки верификации: %v", telegramID, err)
+		}
+		if err := b.clearRegistration(ctx, telegramID); err != nil {
+			log.Errorf("%d Ошибка сброса состояния регистрации: %v", telegramID, err)
+		}
+		log.Warnf("%d Верификация заблокирована после %d неверных попыток (st%06d). Разблокировка: /unlock %d",
+			telegramID, verification.FailedAttempts, registration.DelegateID, telegramID)
+		b.SendMessage(telegramID, tr(ctx, "registration.account_locked"))
+		return
+	}
+	if err := b.setVerification(ctx, telegramID, verification); err != nil {
+		log.Errorf("%d Ошибка сохранения состояния верификации: %v", telegramID, err)
+	}
+
+	// Превышен лимит попыток для кода: код аннулируется
+	if registration.CodeAttempts >= config.VerificationMaxCodeAttempts {
+		log.Warnf("%d Код для st%06d аннулирован после %d неверных попыток", telegramID, registration.DelegateID, registration.CodeAttempts)
+		b.resetToEmailInput(ctx, telegramID)
+		b.SendMessage(telegramID, tr(ctx, "registration.code_attempts_exceeded"))
+		return
+	}
+	if err := b.setRegistration(ctx, telegramID, registration); err != nil {
+		log.Errorf("%d Ошибка сохранения состояния регистрации: %v", telegramID, err)
+	}
+	log.Warnf("%d Неверный код (попытка %d из %d)", telegramID, registration.CodeAttempts, config.VerificationMaxCodeAttempts)
+	attemptsLeft := config.VerificationMaxCodeAttempts - registration.CodeAttempts
+	b.SendMessage(telegramID, trN(ctx, "registration.wrong_code", attemptsLeft, attemptsLeft))
+}
+
+// resetToEmailInput аннулирует код и возвращает пользователя к вводу почты
+func (b *Bot) resetToEmailInput(ctx context.Context, telegramID int64) {
+	if err := b.setRegistration(ctx, telegramID, registrationSession{State: StateWaitingForEmail}); err != nil {
+		log.Errorf("%d Ошибка сохранения состояния регистрации: %v", telegramID, err)
+	}
+}
+
+// isVerificationLocked проверяет блокировку верификации аккаунта и уведомляет пользователя
+func (b *Bot) isVerificationLocked(ctx context.Context, telegramID int64) bool {
+	verification, err := b.getVerification(ctx, telegramID)
+	if err != nil {
+		log.Errorf("%d Ошибка получения состояния верификации: %v", telegramID, err)
+		b.SendMessage(telegramID, tr(ctx, "error.generic"))
+		return true
+	}
+	if verification.Locked {
+		log.Warn(telegramID, " Попытка регистрации заблокированного аккаунта")
+		b.SendMessage(telegramID, tr(ctx, "registration.locked"))
+		return true
+	}
+	return false
 }
 
 // Проверка формата email
@@ -160,8 +380,11 @@ func isValidEmail(email string) bool {
 	return re.MatchString(email)
 }
 
-// Генерирует шестизначный код подтверждения
-func generateCode() int {
-	rand.Seed(time.Now().UnixNano())
-	return rand.Intn(900000) + 100000
+// Генерирует шестизначный код подтверждения с помощью криптографически стойкого генератора
+func generateCode() (int, error) {
+	n, err := rand.Int(rand.Reader, big.NewInt(900000))
+	if err != nil {
+		return 0, fmt.Errorf("generateCode: %w", err)
+	}
+	return int(n.Int64()) + 100000, nil
 }
@@ -0,0 +1,169 @@
+package bot
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/lsdpls/schulze_election_telegram_bot/internal/config"
+	"github.com/lsdpls/schulze_election_telegram_bot/internal/models"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// delegateChatIDs возвращает Telegram ID зарегистрированных делегатов и подтвердивших почту заместителей;
+// onlyNotVoted — только не проголосовавших. Делегату, передавшему голос, напоминание о голосовании не нужно
+func (b *Bot) delegateChatIDs(ctx context.Context, onlyNotVoted bool) ([]int64, error) {
+	delegates, err := b.voteChain.GetAllDelegates(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("delegateChatIDs: %w", err)
+	}
+	proxies, err := b.voteChain.GetAllProxies(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("delegateChatIDs: %w", err)
+	}
+	substitutes := make(map[int]sql.NullInt64)
+	for _, proxy := range proxies {
+		if !proxy.RevokedAt.Valid {
+			substitutes[proxy.DelegateID] = proxy.TelegramID
+		}
+	}
+	var chatIDs []int64
+	for _, delegate := range delegates {
+		if onlyNotVoted && delegate.HasVoted {
+			continue
+		}
+		substitute, delegated := substitutes[delegate.DelegateID]
+		if delegate.TelegramID.Valid && !(onlyNotVoted && delegated) {
+			chatIDs = append(chatIDs, delegate.TelegramID.Int64)
+		}
+		if substitute.Valid {
+			chatIDs = append(chatIDs, substitute.Int64)
+		}
+	}
+	return chatIDs, nil
+}
+
+// sendReminder рассылает напоминание непроголосовавшим делегатам и отправляет итоги в чат администраторов.
+// Пустой текст — стандартное напоминание на языке каждого делегата
+func (b *Bot) sendReminder(ctx context.Context, text string) (broadcastReport, error) {
+	chatIDs, err := b.delegateChatIDs(ctx, true)
+	if err != nil {
+		return broadcastReport{}, fmt.Errorf("sendReminder: %w", err)
+	}
+	log.Infof("Рассылка напоминания %d непроголосовавшим делегатам", len(chatIDs))
+	report := b.broadcast(ctx, chatIDs, func(ctx context.Context) string {
+		if text == "" {
+			return tr(ctx, "reminder.default")
+		}
+		return text
+	})
+	log.Infof("Напоминание разослано: %s", report)
+	if config.AdminChatID != 0 {
+		b.SendMessage(config.AdminChatID, trN(ctx, "reminder.sent", len(chatIDs),
+			len(chatIDs), report.Delivered, report.Blocked, report.Failed))
+	}
+	return report, nil
+}
+
+// processReminders рассылает наступившие запланированные напоминания
+func (b *Bot) processReminders(ctx context.Context, now time.Time) {
+	reminders, err := b.voteChain.ClaimDueReminders(ctx, now)
+	if err != nil {
+		log.Errorf("Ошибка получения запланированных напоминаний: %v", err)
+		return
+	}
+	for _, reminder := range reminders {
+		report, err := b.sendReminder(ctx, reminder.Text)
+		if err != nil {
+			log.Errorf("Ошибка рассылки напоминания %d: %v", reminder.ID, err)
+			continue
+		}
+		reminder.Delivered, reminder.Blocked, reminder.Failed = report.Delivered, report.Blocked, report.Failed
+		if err := b.voteChain.UpdateReminderReport(ctx, reminder); err != nil {
+			log.Errorf("Ошибка сохранения итогов напоминания %d: %v", reminder.ID, err)
+		}
+	}
+}
+
+// Обработчик команды /remind
+func (b *Bot) handleRemind(ctx context.Context, message *tgbotapi.Message) {
+	text := commandArgsFromContext(ctx).text("text")
+	// Рассылка идет дольше таймаута вебхука, поэтому выполняется в фоне
+	go func() {
+		if _, err := b.sendReminder(context.WithoutCancel(ctx), text); err != nil {
+			log.Errorf("%d Ошибка рассылки напоминания: %v", message.Chat.ID, err)
+		}
+	}()
+}
+
+// Обработчик команды /remind_at
+func (b *Bot) handleRemindAt(ctx context.Context, message *tgbotapi.Message) {
+	chatID := message.Chat.ID
+
+	args := commandArgsFromContext(ctx)
+	location, err := time.LoadLocation(config.ElectionTimezone)
+	if err != nil {
+		log.Errorf("%d Ошибка загрузки часового пояса: %v", chatID, err)
+		b.SendMessage(chatID, tr(ctx, "error.generic"))
+		return
+	}
+	sendAt := args.timeIn("send_at", location)
+	if !sendAt.After(time.Now()) {
+		b.SendMessage(chatID, tr(ctx, "reminder.in_past"))
+		return
+	}
+	text := args.text("text")
+
+	id, err := b.voteChain.AddReminder(ctx, models.Reminder{Text: text, SendAt: sendAt})
+	if err != nil {
+		log.Errorf("%d Ошибка сохранения напоминания: %v", chatID, err)
+		b.SendMessage(chatID, tr(ctx, "error.generic"))
+		return
+	}
+	log.Infof("%d Напоминание %d запланировано на %s", chatID, id, formatScheduleTime(sendAt, config.ElectionTimezone))
+	b.SendMessage(chatID, tr(ctx, "reminder.scheduled", id, formatScheduleTime(sendAt, config.ElectionTimezone)))
+}
+
+// Обработчик команды /reminders
+func (b *Bot) handleReminders(ctx context.Context, message *tgbotapi.Message) {
+	chatID := message.Chat.ID
+	reminders, err := b.voteChain.GetPendingReminders(ctx)
+	if err != nil {
+		log.Errorf("%d Ошибка получения напоминаний: %v", chatID, err)
+		return
+	}
+	if len(reminders) == 0 {
+		b.SendMessage(chatID, tr(ctx, "reminders.none"))
+		return
+	}
+	text := "<b>" + tr(ctx, "reminders.header") + "</b>\n"
+	for _, reminder := range reminders {
+		reminderText := reminder.Text
+		if reminderText == "" {
+			reminderText = tr(ctx, "reminders.default_text")
+		}
+		text += fmt.Sprintf("\n• %d, %s: %s", reminder.ID,
+			formatScheduleTime(reminder.SendAt, config.ElectionTimezone), reminderText)
+	}
+	b.SendMessage(chatID, text)
+}
+
+// Обработчик команды /cancel_reminder
+func (b *Bot) handleCancelReminder(ctx context.Context, message *tgbotapi.Message) {
+	chatID := message.Chat.ID
+	reminderID := commandArgsFromContext(ctx).number("id")
+	deleted, err := b.voteChain.DeleteReminder(ctx, reminderID)
+	if err != nil {
+		log.Errorf("%d Ошибка удаления напоминания: %v", chatID, err)
+		b.SendMessage(chatID, tr(ctx, "error.generic"))
+		return
+	}
+	if !deleted {
+		b.SendMessage(chatID, tr(ctx, "reminder.not_found", reminderID))
+		return
+	}
+	log.Infof("%d Напоминание %d отменено", chatID, reminderID)
+	b.SendMessage(chatID, tr(ctx, "reminder.cancelled", reminderID))
+}
@@ -0,0 +1,154 @@
+package bot
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"html"
+	"strconv"
+
+	"github.com/lsdpls/schulze_election_telegram_bot/internal/config"
+	"github.com/lsdpls/schulze_election_telegram_bot/internal/models"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// Что делать с голосом делегата при сбросе регистрации
+const (
+	resetVoteKeep    = "keep"    // Голос переходит к новому аккаунту (по умолчанию)
+	resetVoteDiscard = "discard" // Голос удаляется, делегат голосует заново
+)
+
+var resetVoteOptions = []string{resetVoteKeep, resetVoteDiscard}
+
+// Обработчик команды /reset_registration: показывает, что будет сброшено, и просит подтверждение
+func (b *Bot) handleResetRegistration(ctx context.Context, message *tgbotapi.Message) {
+	chatID := message.Chat.ID
+	args := commandArgsFromContext(ctx)
+	delegateID := args.number("delegate_id")
+	option := resetVoteKeep
+	if args.has("vote") {
+		option = args.text("vote")
+	}
+
+	delegate, err := b.voteChain.GetDelegateByDelegateID(ctx, delegateID)
+	if err != nil {
+		log.Errorf("%d Ошибка при получении делегата: %v", chatID, err)
+		return
+	}
+	if delegate == nil {
+		b.SendMessage(chatID, tr(ctx, "admin.delegate_not_found", delegateID))
+		return
+	}
+	if !delegate.TelegramID.Valid {
+		b.SendMessage(chatID, tr(ctx, "reset.not_registered", delegateID))
+		return
+	}
+	// После закрытия голосования удаление голоса изменило бы итоги
+	if option == resetVoteDiscard && delegate.HasVoted && !b.activeVoting.Load() {
+		b.SendMessage(chatID, tr(ctx, "reset.discard_closed"))
+		return
+	}
+
+	summary := tr(ctx, "reset.summary", delegateID, html.EscapeString(delegate.Name), html.EscapeString(delegate.Group)) +
+		"\n" + tr(ctx, "confirm.delegate_registered", delegate.TelegramID.Int64, delegate.TelegramID.Int64)
+	switch {
+	case !delegate.HasVoted:
+		summary += "\n" + tr(ctx, "confirm.delegate_no_vote")
+	case option == resetVoteDiscard:
+		summary += "\n" + tr(ctx, "reset.vote_discard")
+	default:
+		summary += "\n" + tr(ctx, "reset.vote_keep")
+	}
+	b.askConfirmation(ctx, message, confirmationSession{Command: "reset_registration", TargetID: delegateID, Option: option},
+		summary+"\n"+tr(ctx, "reset.next_steps"))
+}
+
+// resetRegistration отвязывает аккаунт после подтверждения, очищает состояния старого аккаунта
+// и сообщает ему об отвязке. Возвращает текст для администратора
+func (b *Bot) resetRegistration(ctx context.Context, confirmation confirmationSession, performedBy int64) (string, error) {
+	discard := confirmation.Option == resetVoteDiscard
+	if discard && !b.activeVoting.Load() {
+		// Голосование могло закрыться, пока ждали подтверждения
+		delegate, err := b.voteChain.GetDelegateByDelegateID(ctx, confirmation.TargetID)
+		if err != nil {
+			return "", fmt.Errorf("resetRegistration: %w", err)
+		}
+		if delegate != nil && delegate.HasVoted {
+			return tr(ctx, "reset.discard_closed"), nil
+		}
+	}
+	previous, err := b.voteChain.ResetRegistration(ctx, confirmation.TargetID, discard,
+		sql.NullInt64{Int64: performedBy, Valid: true})
+	if err != nil {
+		return "", fmt.Errorf("resetRegistration: %w", err)
+	}
+	log.Warnf("%d Регистрация делегата st%06d сброшена (аккаунт %d, голос: %s)", confirmation.ChatID,
+		confirmation.TargetID, previous.TelegramID.Int64, confirmation.Option)
+
+	if oldID := previous.TelegramID; oldID.Valid {
+		if err := b.clearRegistration(ctx, oldID.Int64); err != nil {
+			log.Errorf("%d Ошибка сброса состояния регистрации: %v", oldID.Int64, err)
+		}
+		if err := b.clearBallot(ctx, oldID.Int64); err != nil {
+			log.Errorf("%d Ошибка удаления незаполненного бюллетеня: %v", oldID.Int64, err)
+		}
+		userCtx := withLanguage(ctx, b.chatLanguage(ctx, oldID.Int64))
+		if err := b.SendMessage(oldID.Int64, tr(userCtx, "reset.notice")); err != nil {
+			log.Debugf("%d Не удалось уведомить об отвязке аккаунта: %v", oldID.Int64, err)
+		}
+	}
+	if discard && previous.HasVoted {
+		return tr(ctx, "reset.done_vote_discarded", confirmation.TargetID), nil
+	}
+	return tr(ctx, "reset.done", confirmation.TargetID), nil
+}
+
+// Обработчик команды /audit: история привязок Telegram аккаунтов делегата
+func (b *Bot) handleAudit(ctx context.Context, message *tgbotapi.Message) {
+	chatID := message.Chat.ID
+	delegateID := commandArgsFromContext(ctx).number("delegate_id")
+	records, err := b.voteChain.GetAuditLog(ctx, delegateID)
+	if err != nil {
+		log.Errorf("%d Ошибка при получении журнала делегата: %v", chatID, err)
+		return
+	}
+	if len(records) == 0 {
+		b.SendMessage(chatID, tr(ctx, "audit.none", delegateID))
+		return
+	}
+	text := tr(ctx, "audit.header", delegateID)
+	for _, record := range records {
+		text += "\n" + auditLine(ctx, record)
+	}
+	b.SendMessage(chatID, text)
+}
+
+// auditLine описывает запись журнала одной строкой
+func auditLine(ctx context.Context, record models.AuditRecord) string {
+	line := "• " + formatScheduleTime(record.CreatedAt, config.ElectionTimezone) + " — " + tr(ctx, "audit.action."+record.Action)
+	if record.TelegramID.Valid {
+		line += ", " + tr(ctx, "audit.account", record.TelegramID.Int64, record.TelegramID.Int64)
+	}
+	switch {
+	case isProxyAction(record.Action):
+		// В подробностях действий с доверенностью — ID заместителя
+		substituteID, _ := strconv.Atoi(record.Details)
+		line += ", " + tr(ctx, "audit.substitute", substituteID)
+	case record.Details != "":
+		line += ", " + tr(ctx, "audit.details."+record.Details)
+	}
+	if record.PerformedBy.Valid {
+		line += ", " + tr(ctx, "audit.performed_by", record.PerformedBy.Int64, record.PerformedBy.Int64)
+	}
+	return line
+}
+
+// isProxyAction сообщает, что запись журнала касается доверенности
+func isProxyAction(action string) bool {
+	switch action {
+	case models.AuditProxyNominated, models.AuditProxyVerified, models.AuditProxyRevoked, models.AuditProxyVoted:
+		return true
+	}
+	return false
+}
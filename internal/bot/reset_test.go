@@ -0,0 +1,54 @@
+package bot
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/lsdpls/schulze_election_telegram_bot/internal/i18n"
+	"github.com/lsdpls/schulze_election_telegram_bot/internal/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuditLine(t *testing.T) {
+	t.Parallel()
+
+	ctx := withLanguage(context.Background(), "ru")
+	record := models.AuditRecord{
+		Action:      models.AuditResetRegistration,
+		DelegateID:  123456,
+		TelegramID:  sql.NullInt64{Int64: 42, Valid: true},
+		PerformedBy: sql.NullInt64{Int64: 7, Valid: true},
+		Details:     models.AuditVoteKept,
+		CreatedAt:   time.Date(2026, 10, 19, 12, 30, 0, 0, time.UTC),
+	}
+	line := auditLine(ctx, record)
+	assert.Contains(t, line, "19.10.2026 12:30")
+	assert.Contains(t, line, "сброс регистрации")
+	assert.Contains(t, line, `<a href="tg://user?id=42">42</a>`)
+	assert.Contains(t, line, "голос сохранен")
+	assert.Contains(t, line, `<a href="tg://user?id=7">7</a>`)
+
+	record = models.AuditRecord{Action: models.AuditVerification, TelegramID: sql.NullInt64{Int64: 42, Valid: true}}
+	assert.NotContains(t, auditLine(ctx, record), "выполнил")
+}
+
+// Действия и подробности журнала должны быть в каталоге сообщений на всех языках
+func TestAuditTexts(t *testing.T) {
+	t.Parallel()
+
+	keys := []string{
+		"audit.action." + models.AuditResetRegistration, "audit.action." + models.AuditVerification,
+		"audit.details." + models.AuditVoteKept, "audit.details." + models.AuditVoteDiscarded, "audit.details." + models.AuditNoVote,
+		"audit.action." + models.AuditProxyNominated, "audit.action." + models.AuditProxyVerified,
+		"audit.action." + models.AuditProxyRevoked, "audit.action." + models.AuditProxyVoted,
+	}
+	for _, lang := range i18n.Languages {
+		for _, key := range keys {
+			_, ok := i18n.Base(lang, key)
+			assert.True(t, ok, "%s %s", lang, key)
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package bot
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/lsdpls/schulze_election_telegram_bot/internal/config"
+	"github.com/lsdpls/schulze_election_telegram_bot/internal/models"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// roleNone — роль пользователя, не являющегося администратором
+const roleNone = ""
+
+// Наборы ролей для команд. Суперадминистратору доступны все команды
+var (
+	rolesEveryone   = []string{roleNone, models.RoleOperator, models.RoleTallyOfficer, models.RoleObserver}
+	rolesSuperadmin = []string{}
+	rolesOperator   = []string{models.RoleOperator}
+	rolesTally      = []string{models.RoleTallyOfficer}
+	rolesReadOnly   = []string{models.RoleOperator, models.RoleTallyOfficer, models.RoleObserver}
+)
+
+// Роли, которые можно выдать командой /grant
+var adminRoles = []string{models.RoleSuperadmin, models.RoleOperator, models.RoleObserver, models.RoleTallyOfficer}
+
+// roleName возвращает название роли на языке из контекста
+func roleName(ctx context.Context, role string) string {
+	return tr(ctx, "role."+role)
+}
+
+type adminRoleKey struct{}
+
+// withAdminRole сохраняет роль отправителя команды в контексте
+func withAdminRole(ctx context.Context, role string) context.Context {
+	return context.WithValue(ctx, adminRoleKey{}, role)
+}
+
+// adminRoleFromContext возвращает роль отправителя команды
+func adminRoleFromContext(ctx context.Context) string {
+	role, _ := ctx.Value(adminRoleKey{}).(string)
+	return role
+}
+
+// adminRole определяет роль отправителя. Чат ADMIN_CHAT_ID обладает правами суперадминистратора,
+// остальные администраторы работают с ботом в личных сообщениях. Пустая строка — не администратор
+func (b *Bot) adminRole(ctx context.Context, chat *tgbotapi.Chat, from *tgbotapi.User) (string, error) {
+	if chat == nil {
+		return "", nil
+	}
+	if chat.ID == config.AdminChatID {
+		return models.RoleSuperadmin, nil
+	}
+	if !chat.IsPrivate() || from == nil {
+		return "", nil
+	}
+	admin, err := b.voteChain.GetAdmin(ctx, from.ID)
+	if err != nil {
+		return "", fmt.Errorf("adminRole: %w", err)
+	}
+	if admin == nil {
+		return "", nil
+	}
+	return admin.Role, nil
+}
+
+// Обработчик команды /grant
+func (b *Bot) handleGrant(ctx context.Context, message *tgbotapi.Message) {
+	chatID := message.Chat.ID
+	args := commandArgsFromContext(ctx)
+	telegramID, role := args.telegramID("telegram_id"), args.text("role")
+
+	admin := models.Admin{TelegramID: telegramID, Role: role}
+	if message.From != nil {
+		admin.GrantedBy = sql.NullInt64{Int64: message.From.ID, Valid: true}
+	}
+	if err := b.voteChain.SetAdmin(ctx, admin); err != nil {
+		log.Errorf("%d Ошибка при выдаче роли: %v", chatID, err)
+		b.SendMessage(chatID, tr(ctx, "error.generic"))
+		return
+	}
+	log.Warnf("%d Пользователю %d выдана роль %s", chatID, telegramID, role)
+	b.SendMessage(chatID, tr(ctx, "admin.role_granted", telegramID, tr(ctx, "role."+role)))
+	if err := b.setAdminCommands(ctx, telegramID, role); err != nil {
+		log.Warnf("%d Не удалось опубликовать меню команд администратора %d: %v", chatID, telegramID, err)
+	}
+}
+
+// Обработчик команды /revoke
+func (b *Bot) handleRevoke(ctx context.Context, message *tgbotapi.Message) {
+	chatID := message.Chat.ID
+	telegramID := commandArgsFromContext(ctx).telegramID("telegram_id")
+	deleted, err := b.voteChain.DeleteAdmin(ctx, telegramID)
+	if err != nil {
+		log.Errorf("%d Ошибка при отзыве роли: %v", chatID, err)
+		b.SendMessage(chatID, tr(ctx, "error.generic"))
+		return
+	}
+	if !deleted {
+		b.SendMessage(chatID, tr(ctx, "admin.role_missing", telegramID))
+		return
+	}
+	log.Warnf("%d У пользователя %d отозвана роль", chatID, telegramID)
+	b.SendMessage(chatID, tr(ctx, "admin.role_revoked", telegramID))
+	if err := b.setAdminCommands(ctx, telegramID, roleNone); err != nil {
+		log.Warnf("%d Не удалось удалить меню команд администратора %d: %v", chatID, telegramID, err)
+	}
+}
+
+// Обработчик команды /admins
+func (b *Bot) handleAdmins(ctx context.Context, message *tgbotapi.Message) {
+	chatID := message.Chat.ID
+	admins, err := b.voteChain.GetAllAdmins(ctx)
+	if err != nil {
+		log.Errorf("%d Ошибка при получении списка администраторов: %v", chatID, err)
+		return
+	}
+	if len(admins) == 0 {
+		b.SendMessage(chatID, tr(ctx, "admins.none"))
+		return
+	}
+	text := tr(ctx, "admins.header") + "\n"
+	for _, admin := range admins {
+		text += tr(ctx, "admins.line", admin.TelegramID, admin.TelegramID,
+			roleName(ctx, admin.Role), admin.GrantedAt.Format("02.01.2006")) + "\n"
+	}
+	b.SendMessage(chatID, text)
+}
@@ -0,0 +1,63 @@
+package bot
+
+import (
+	"testing"
+
+	"github.com/lsdpls/schulze_election_telegram_bot/internal/i18n"
+	"github.com/lsdpls/schulze_election_telegram_bot/internal/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAdminCommandRoles(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		command string
+		role    string
+		allowed bool
+	}{
+		{"delete_delegate", models.RoleSuperadmin, true},
+		{"delete_delegate", models.RoleOperator, true},
+		{"delete_delegate", models.RoleObserver, false},
+		{"delete_delegate", models.RoleTallyOfficer, false},
+		{"show_votes", models.RoleObserver, true},
+		{"results", models.RoleTallyOfficer, true},
+		{"results", models.RoleObserver, false},
+		{"grant", models.RoleOperator, false},
+		{"grant", models.RoleSuperadmin, true},
+	}
+	for _, tt := range tests {
+		command, ok := findCommand(tt.command)
+		if assert.True(t, ok, tt.command) {
+			assert.Equal(t, tt.allowed, command.allows(tt.role), "%s %s", tt.command, tt.role)
+		}
+	}
+
+	// Наблюдателям доступны только команды просмотра
+	for _, command := range commands {
+		if command.isAdmin() && command.allows(models.RoleObserver) {
+			assert.Contains(t, []string{"show_delegates", "show_candidates", "show_votes", "turnout"}, command.name)
+		}
+	}
+}
+
+// Описания команд для /help и названия ролей должны быть в каталоге сообщений на всех языках
+func TestAdminCommandTexts(t *testing.T) {
+	t.Parallel()
+
+	for _, lang := range i18n.Languages {
+		for _, command := range commands {
+			key := "command." + command.name
+			if command.isAdmin() {
+				key = "admin." + key
+			}
+			_, ok := i18n.Base(lang, key)
+			assert.True(t, ok, "%s %s", lang, command.name)
+		}
+		for _, role := range adminRoles {
+			_, ok := i18n.Base(lang, "role."+role)
+			assert.True(t, ok, "%s %s", lang, role)
+		}
+	}
+}
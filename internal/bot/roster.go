@@ -0,0 +1,314 @@
+package bot
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"html"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/lsdpls/schulze_election_telegram_bot/internal/models"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// Колонки файла импорта (заголовок обязателен, порядок колонок любой)
+const (
+	rosterColumnType        = "type"
+	rosterColumnID          = "id"
+	rosterColumnName        = "name"
+	rosterColumnGroup       = "group"
+	rosterColumnCourse      = "course"
+	rosterColumnDescription = "description"
+)
+
+// Допустимые названия колонок
+var rosterColumnAliases = map[string]string{
+	"type": rosterColumnType, "тип": rosterColumnType,
+	"id":   rosterColumnID,
+	"name": rosterColumnName, "фио": rosterColumnName, "имя": rosterColumnName,
+	"group": rosterColumnGroup, "группа": rosterColumnGroup,
+	"course": rosterColumnCourse, "курс": rosterColumnCourse,
+	"description": rosterColumnDescription, "описание": rosterColumnDescription,
+}
+
+// Допустимые значения колонки type
+var rosterTypeAliases = map[string]string{
+	"delegate": "delegate", "делегат": "delegate",
+	"candidate": "candidate", "кандидат": "candidate",
+}
+
+// rosterFile содержит делегатов и кандидатов из файла импорта
+type rosterFile struct {
+	Delegates     []models.Delegate
+	Candidates    []models.Candidate
+	HasDelegates  bool // В файле есть делегаты: отсутствующие в нем делегаты будут удалены
+	HasCandidates bool // В файле есть кандидаты: отсутствующие в нем кандидаты будут удалены
+}
+
+// readRosterCSV читает строки CSV файла (i-я запись — (i+1)-я строка файла). Разделитель (запятая или точка с запятой) определяется по заголовку
+func readRosterCSV(r io.Reader) ([][]string, error) {
+	reader := bufio.NewReader(r)
+	header, err := reader.Peek(reader.Size())
+	if err != nil && err != io.EOF && err != bufio.ErrBufferFull {
+		return nil, fmt.Errorf("readRosterCSV: %w", err)
+	}
+	firstLine, _, _ := bytes.Cut(header, []byte("\n"))
+
+	csvReader := csv.NewReader(reader)
+	csvReader.FieldsPerRecord = -1
+	if bytes.Count(firstLine, []byte(";")) > bytes.Count(firstLine, []byte(",")) {
+		csvReader.Comma = ';'
+	}
+	// Пустые строки csv пропускает, поэтому выравниваем записи по номерам строк файла для сообщений об ошибках
+	var records [][]string
+	for {
+		record, err := csvReader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("readRosterCSV: %w", err)
+		}
+		line, _ := csvReader.FieldPos(0)
+		for len(records) < line-1 {
+			records = append(records, nil)
+		}
+		records = append(records, record)
+	}
+	// Excel добавляет BOM в начало UTF-8 файла
+	if len(records) > 0 && len(records[0]) > 0 {
+		records[0][0] = strings.TrimPrefix(records[0][0], "\ufeff")
+	}
+	return records, nil
+}
+
+// readRosterXLSX читает строки первого листа XLSX файла
+func readRosterXLSX(r io.Reader) ([][]string, error) {
+	f, err := excelize.OpenReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("readRosterXLSX: %w", err)
+	}
+	defer f.Close()
+	sheets := f.GetSheetList()
+	if len(sheets) == 0 {
+		return nil, fmt.Errorf("readRosterXLSX: no sheets")
+	}
+	rows, err := f.GetRows(sheets[0])
+	if err != nil {
+		return nil, fmt.Errorf("readRosterXLSX: %w", err)
+	}
+	return rows, nil
+}
+
+// parseRoster проверяет строки файла импорта и возвращает делегатов и кандидатов.
+// Возвращает список ошибок с номерами строк; при ошибках импорт применять нельзя
+func parseRoster(ctx context.Context, records [][]string) (rosterFile, []string) {
+	var file rosterFile
+	if len(records) == 0 {
+		return file, []string{tr(ctx, "roster.empty_file")}
+	}
+
+	// Разбираем заголовок
+	columns := make(map[string]int)
+	for i, title := range records[0] {
+		if column, ok := rosterColumnAliases[strings.ToLower(strings.TrimSpace(title))]; ok {
+			columns[column] = i
+		}
+	}
+	for _, column := range []string{rosterColumnType, rosterColumnID, rosterColumnName} {
+		if _, ok := columns[column]; !ok {
+			return file, []string{tr(ctx, "roster.missing_column", column)}
+		}
+	}
+	cell := func(record []string, column string) string {
+		i, ok := columns[column]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+
+	var errs []string
+	delegateIDs := make(map[int]int)
+	candidateIDs := make(map[int]int)
+	groups := make(map[string]int)
+	for i, record := range records[1:] {
+		line := i + 2
+		if strings.TrimSpace(strings.Join(record, "")) == "" {
+			continue
+		}
+		rowErr := func(key string, args ...any) {
+			errs = append(errs, tr(ctx, "roster.row_error", line, tr(ctx, key, args...)))
+		}
+
+		rowType, ok := rosterTypeAliases[strings.ToLower(cell(record, rosterColumnType))]
+		if !ok {
+			rowErr("roster.unknown_type", cell(record, rosterColumnType))
+			continue
+		}
+		idStr := strings.TrimPrefix(strings.ToLower(cell(record, rosterColumnID)), "st")
+		id, err := strconv.Atoi(idStr)
+		if err != nil || !isValidID(idStr) {
+			rowErr("roster.invalid_id", cell(record, rosterColumnID))
+			continue
+		}
+		name := cell(record, rosterColumnName)
+		if name == "" {
+			rowErr("roster.missing_name")
+			continue
+		}
+
+		switch rowType {
+		case "delegate":
+			file.HasDelegates = true
+			group := cell(record, rosterColumnGroup)
+			if !isValidGroup(group) {
+				rowErr("roster.invalid_group", group)
+				continue
+			}
+			if prev, ok := delegateIDs[id]; ok {
+				rowErr("roster.duplicate_delegate", id, prev)
+				continue
+			}
+			if prev, ok := groups[group]; ok {
+				rowErr("roster.duplicate_group", group, prev)
+				continue
+			}
+			delegateIDs[id], groups[group] = line, line
+			file.Delegates = append(file.Delegates, models.Delegate{DelegateID: id, Name: name, Group: group})
+		case "candidate":
+			file.HasCandidates = true
+			course := cell(record, rosterColumnCourse)
+			if !isValidCourse(course) {
+				rowErr("roster.invalid_course", course)
+				continue
+			}
+			if prev, ok := candidateIDs[id]; ok {
+				rowErr("roster.duplicate_candidate", id, prev)
+				continue
+			}
+			candidateIDs[id] = line
+			file.Candidates = append(file.Candidates, models.Candidate{
+				CandidateID: id,
+				Name:        name,
+				Course:      course,
+				Description: cell(record, rosterColumnDescription),
+				IsEligible:  true,
+			})
+		}
+	}
+	if len(errs) == 0 && !file.HasDelegates && !file.HasCandidates {
+		errs = append(errs, tr(ctx, "roster.no_rows"))
+	}
+	return file, errs
+}
+
+// diffRoster сравнивает файл импорта с текущими списками. Возвращает изменения, их описание для предпросмотра
+// и предупреждения. Проголосовавшие делегаты не удаляются, так как вместе с ними удалились бы их голоса
+func diffRoster(ctx context.Context, file rosterFile, delegates []models.Delegate, candidates []models.Candidate) (models.RosterImport, []string, []string) {
+	var rosterImport models.RosterImport
+	var lines, warnings []string
+
+	if file.HasDelegates {
+		current := make(map[int]models.Delegate, len(delegates))
+		for _, delegate := range delegates {
+			current[delegate.DelegateID] = delegate
+		}
+		inFile := make(map[int]bool, len(file.Delegates))
+		for _, delegate := range file.Delegates {
+			inFile[delegate.DelegateID] = true
+			old, ok := current[delegate.DelegateID]
+			switch {
+			case !ok:
+				rosterImport.AddDelegates = append(rosterImport.AddDelegates, delegate)
+				lines = append(lines, tr(ctx, "roster.add_delegate", delegate.DelegateID, delegate.Name, delegate.Group))
+			case old.Name != delegate.Name || old.Group != delegate.Group:
+				rosterImport.UpdateDelegates = append(rosterImport.UpdateDelegates, delegate)
+				lines = append(lines, tr(ctx, "roster.update_delegate",
+					delegate.DelegateID, old.Name, old.Group, delegate.Name, delegate.Group))
+			}
+		}
+		for _, delegate := range sortedDelegates(delegates) {
+			if inFile[delegate.DelegateID] {
+				continue
+			}
+			if delegate.HasVoted {
+				warnings = append(warnings, tr(ctx, "roster.voted_delegate_kept",
+					delegate.DelegateID, delegate.Name))
+				continue
+			}
+			rosterImport.DeleteDelegates = append(rosterImport.DeleteDelegates, delegate.DelegateID)
+			lines = append(lines, tr(ctx, "roster.delete_delegate", delegate.DelegateID, delegate.Name, delegate.Group))
+		}
+	}
+
+	if file.HasCandidates {
+		current := make(map[int]models.Candidate, len(candidates))
+		for _, candidate := range candidates {
+			current[candidate.CandidateID] = candidate
+		}
+		inFile := make(map[int]bool, len(file.Candidates))
+		for _, candidate := range file.Candidates {
+			inFile[candidate.CandidateID] = true
+			old, ok := current[candidate.CandidateID]
+			switch {
+			case !ok:
+				rosterImport.AddCandidates = append(rosterImport.AddCandidates, candidate)
+				lines = append(lines, tr(ctx, "roster.add_candidate", candidate.CandidateID, candidate.Name, candidate.Course))
+			case old.Name != candidate.Name || old.Course != candidate.Course || old.Description != candidate.Description:
+				rosterImport.UpdateCandidates = append(rosterImport.UpdateCandidates, candidate)
+				lines = append(lines, tr(ctx, "roster.update_candidate",
+					candidate.CandidateID, old.Name, old.Course, candidate.Name, candidate.Course))
+			}
+		}
+		sortedIDs := make([]int, 0, len(candidates))
+		for _, candidate := range candidates {
+			sortedIDs = append(sortedIDs, candidate.CandidateID)
+		}
+		sort.Ints(sortedIDs)
+		for _, candidateID := range sortedIDs {
+			if inFile[candidateID] {
+				continue
+			}
+			candidate := current[candidateID]
+			rosterImport.DeleteCandidates = append(rosterImport.DeleteCandidates, candidateID)
+			lines = append(lines, tr(ctx, "roster.delete_candidate", candidateID, candidate.Name, candidate.Course))
+		}
+	}
+	return rosterImport, lines, warnings
+}
+
+// sortedDelegates возвращает делегатов, упорядоченных по ID
+func sortedDelegates(delegates []models.Delegate) []models.Delegate {
+	sorted := append([]models.Delegate(nil), delegates...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].DelegateID < sorted[j].DelegateID })
+	return sorted
+}
+
+// rosterImportSummary формирует текст предпросмотра импорта (HTML)
+func rosterImportSummary(ctx context.Context, rosterImport models.RosterImport, lines, warnings []string) string {
+	const maxLines = 40
+	text := tr(ctx, "roster.preview",
+		len(rosterImport.AddDelegates), len(rosterImport.UpdateDelegates), len(rosterImport.DeleteDelegates),
+		len(rosterImport.AddCandidates), len(rosterImport.UpdateCandidates), len(rosterImport.DeleteCandidates)) + "\n"
+	if len(lines) > 0 {
+		text += "\n"
+	}
+	for i, line := range lines {
+		if i == maxLines {
+			text += tr(ctx, "list.more", len(lines)-maxLines) + "\n"
+			break
+		}
+		text += html.EscapeString(line) + "\n"
+	}
+	for _, warning := range warnings {
+		text += "\n⚠️ " + html.EscapeString(warning)
+	}
+	return text
+}
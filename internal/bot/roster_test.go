@@ -0,0 +1,67 @@
+package bot
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"testing"
+
+	"github.com/lsdpls/schulze_election_telegram_bot/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRosterCSV(t *testing.T) {
+	t.Parallel()
+
+	data := "\ufeffтип;ID;ФИО;Группа;Курс;Описание\n" +
+		"делегат;st100001;Иванов Иван;21.Б01-пу;;\n" +
+		"candidate;100002;Петров Петр;;1 бакалавриат;Староста\n" +
+		"\n" +
+		"delegate;12345;Сидоров;21.Б02-пу;;\n" +
+		"delegate;100003;Смирнов;группа;;\n" +
+		"delegate;100004;Кузнецов;21.Б01-пу;;\n" +
+		"observer;100005;Попов;;;\n"
+	records, err := readRosterCSV(strings.NewReader(data))
+	require.NoError(t, err)
+
+	file, errs := parseRoster(context.Background(), records)
+	assert.Equal(t, []string{
+		`строка 5: неверный ID "12345", ожидается шестизначное число`,
+		`строка 6: неверная группа "группа", ожидается формат XX.БXX-пу или XX.МXX-пу`,
+		"строка 7: группа 21.Б01-пу уже указана в строке 2",
+		`строка 8: неизвестный тип "observer" (delegate или candidate)`,
+	}, errs)
+	assert.Equal(t, []models.Delegate{{DelegateID: 100001, Name: "Иванов Иван", Group: "21.Б01-пу"}}, file.Delegates)
+	assert.Equal(t, []models.Candidate{{CandidateID: 100002, Name: "Петров Петр", Course: "1 бакалавриат", Description: "Староста", IsEligible: true}}, file.Candidates)
+}
+
+func TestDiffRoster(t *testing.T) {
+	t.Parallel()
+
+	file := rosterFile{
+		Delegates: []models.Delegate{
+			{DelegateID: 100001, Name: "Иванов Иван", Group: "21.Б01-пу"}, // без изменений
+			{DelegateID: 100002, Name: "Петров Петр", Group: "21.Б03-пу"}, // сменил группу
+			{DelegateID: 100005, Name: "Новиков", Group: "21.Б05-пу"},     // новый
+		},
+		HasDelegates: true,
+	}
+	delegates := []models.Delegate{
+		{DelegateID: 100001, Name: "Иванов Иван", Group: "21.Б01-пу"},
+		{DelegateID: 100002, Name: "Петров Петр", Group: "21.Б02-пу", TelegramID: sql.NullInt64{Int64: 42, Valid: true}},
+		{DelegateID: 100003, Name: "Смирнов", Group: "21.Б04-пу"},
+		{DelegateID: 100004, Name: "Кузнецов", Group: "21.Б06-пу", HasVoted: true},
+	}
+	candidates := []models.Candidate{{CandidateID: 200001, Name: "Кандидат", Course: "1 бакалавриат"}}
+
+	rosterImport, lines, warnings := diffRoster(context.Background(), file, delegates, candidates)
+	assert.Equal(t, []models.Delegate{{DelegateID: 100005, Name: "Новиков", Group: "21.Б05-пу"}}, rosterImport.AddDelegates)
+	assert.Equal(t, []models.Delegate{{DelegateID: 100002, Name: "Петров Петр", Group: "21.Б03-пу"}}, rosterImport.UpdateDelegates)
+	// Проголосовавший делегат не удаляется, кандидаты без строк в файле не трогаются
+	assert.Equal(t, []int{100003}, rosterImport.DeleteDelegates)
+	assert.Empty(t, rosterImport.DeleteCandidates)
+	assert.Len(t, lines, 3)
+	assert.Len(t, warnings, 1)
+}
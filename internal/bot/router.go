@@ -0,0 +1,565 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lsdpls/schulze_election_telegram_bot/internal/config"
+	"github.com/lsdpls/schulze_election_telegram_bot/internal/i18n"
+	"github.com/lsdpls/schulze_election_telegram_bot/internal/models"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// argKind — тип аргумента команды: определяет проверку значения и подпись в подсказке
+type argKind string
+
+const (
+	argString     argKind = "string"      // Непустая строка без запятых
+	argText       argKind = "text"        // Остаток строки, может содержать запятые; только последний аргумент
+	argID         argKind = "id"          // Шестизначный ID делегата или кандидата
+	argTelegramID argKind = "telegram_id" // Telegram ID пользователя
+	argInt        argKind = "int"         // Целое число
+	argTime       argKind = "time"        // Время ДД.ММ.ГГГГ ЧЧ:ММ, часовой пояс выбирает обработчик
+	argURL        argKind = "url"         // Ссылка http или https
+	argCourse     argKind = "course"      // Курс кандидата: 1-4 бакалавриат или 1-2 магистратура
+	argChoice     argKind = "choice"      // Одно из значений choices
+)
+
+// commandArg описывает аргумент команды. Аргументы разделяются запятыми, необязательные — только в конце
+type commandArg struct {
+	name     string
+	kind     argKind
+	optional bool
+	choices  []string // Допустимые значения для argChoice
+}
+
+func argRequired(name string, kind argKind) commandArg { return commandArg{name: name, kind: kind} }
+
+func argOptional(name string, kind argKind) commandArg {
+	return commandArg{name: name, kind: kind, optional: true}
+}
+
+func argOneOf(name string, choices ...string) commandArg {
+	return commandArg{name: name, kind: argChoice, choices: choices}
+}
+
+// commandHandler обрабатывает команду. Роль отправителя и разобранные аргументы передаются в контексте
+type commandHandler func(b *Bot, ctx context.Context, message *tgbotapi.Message)
+
+// botCommand описывает команду бота. Описание для /help и меню команд хранится в каталоге сообщений
+// под ключом admin.command.<name> для администрирующих команд и command.<name> для остальных
+type botCommand struct {
+	name    string
+	args    []commandArg
+	roles   []string // Роли, которым доступна команда (суперадминистратору доступны все); roleNone — пользователи без роли
+	private bool     // Только в личных сообщениях
+	handler commandHandler
+}
+
+// commands — команды бота в порядке вывода в /help и меню.
+// Заполняется в init, так как /help сам обращается к списку команд
+var commands []botCommand
+
+func init() {
+	commands = []botCommand{
+		// Команды делегатов
+		{name: "start", roles: rolesEveryone, private: true, handler: (*Bot).handleStart},
+		{name: "vote", roles: rolesEveryone, private: true, handler: (*Bot).handleVote},
+		{name: "verify", roles: rolesEveryone, private: true, handler: (*Bot).handleVerify},
+		{name: "candidates", roles: rolesEveryone, private: true, handler: (*Bot).handleCandidates},
+		{name: "language", roles: rolesEveryone, private: true, handler: (*Bot).handleLanguage},
+		// Без ID /proxy показывает доверенность делегата
+		{name: "proxy", roles: rolesEveryone, private: true, handler: (*Bot).handleProxy, args: []commandArg{
+			argOptional("substitute_id", argID)}},
+		{name: "revoke_proxy", roles: rolesEveryone, private: true, handler: (*Bot).handleRevokeProxy},
+		{name: "help", roles: rolesEveryone, handler: (*Bot).handleHelp},
+		// Изменение базы данных
+		{name: "add_delegate", roles: rolesOperator, handler: (*Bot).handleAddDelegate, args: []commandArg{
+			argRequired("delegate_id", argID), argRequired("name", argString), argRequired("group", argString)}},
+		// Без поля /edit_delegate показывает карточку делегата с кнопками полей, без значения — просит прислать значение
+		{name: "edit_delegate", roles: rolesOperator, handler: (*Bot).handleEditDelegate, args: []commandArg{
+			argRequired("delegate_id", argID), {name: "field", kind: argChoice, optional: true, choices: editFields[editDelegate]},
+			argOptional("value", argText)}},
+		{name: "delete_delegate", roles: rolesOperator, handler: (*Bot).handleDeleteDelegate, args: []commandArg{
+			argRequired("delegate_id", argID)}},
+		// Голос по умолчанию переходит к новому аккаунту (keep), discard удаляет его
+		{name: "reset_registration", roles: rolesOperator, handler: (*Bot).handleResetRegistration, args: []commandArg{
+			argRequired("delegate_id", argID), {name: "vote", kind: argChoice, optional: true, choices: resetVoteOptions}}},
+		{name: "audit", roles: rolesOperator, handler: (*Bot).handleAudit, args: []commandArg{
+			argRequired("delegate_id", argID)}},
+		{name: "set_proxy", roles: rolesOperator, handler: (*Bot).handleSetProxy, args: []commandArg{
+			argRequired("delegate_id", argID), argRequired("substitute_id", argID)}},
+		{name: "cancel_proxy", roles: rolesOperator, handler: (*Bot).handleCancelProxy, args: []commandArg{
+			argRequired("delegate_id", argID)}},
+		{name: "proxies", roles: rolesOperator, handler: (*Bot).handleProxies},
+		{name: "add_candidate", roles: rolesOperator, handler: (*Bot).handleAddCandidate, args: []commandArg{
+			argRequired("candidate_id", argID), argRequired("name", argString), argRequired("course", argCourse),
+			argRequired("description", argText)}},
+		{name: "ban_candidate", roles: rolesOperator, handler: (*Bot).handleBanCandidate, args: []commandArg{
+			argRequired("candidate_id", argID)}},
+		{name: "unban_candidate", roles: rolesOperator, handler: (*Bot).handleUnbanCandidate, args: []commandArg{
+			argRequired("candidate_id", argID)}},
+		{name: "edit_candidate", roles: rolesOperator, handler: (*Bot).handleEditCandidate, args: []commandArg{
+			argRequired("candidate_id", argID), {name: "field", kind: argChoice, optional: true, choices: editFields[editCandidate]},
+			argOptional("value", argText)}},
+		{name: "set_photo", roles: rolesOperator, handler: (*Bot).handleSetPhoto, args: []commandArg{
+			argRequired("candidate_id", argID)}},
+		{name: "set_manifesto", roles: rolesOperator, handler: (*Bot).handleSetManifesto, args: []commandArg{
+			argRequired("candidate_id", argID), argOptional("url", argURL)}},
+		{name: "delete_candidate", roles: rolesOperator, handler: (*Bot).handleDeleteCandidate, args: []commandArg{
+			argRequired("candidate_id", argID)}},
+		{name: "unlock", roles: rolesOperator, handler: (*Bot).handleUnlock, args: []commandArg{
+			argRequired("telegram_id", argTelegramID)}},
+		// Показать инфу
+		{name: "show_delegates", roles: rolesReadOnly, handler: (*Bot).handleShowDelegates},
+		{name: "show_candidates", roles: rolesReadOnly, handler: (*Bot).handleShowCandidates},
+		{name: "show_votes", roles: rolesReadOnly, handler: (*Bot).handleShowVotes},
+		{name: "turnout", roles: rolesReadOnly, handler: (*Bot).handleTurnout},
+		// Управление голосованием
+		{name: "start_voting", roles: rolesOperator, handler: (*Bot).handleStartVoting},
+		{name: "stop_voting", roles: rolesOperator, handler: (*Bot).handleStopVoting},
+		// Без аргументов /schedule показывает расписание
+		{name: "schedule", roles: rolesOperator, handler: (*Bot).handleSchedule, args: []commandArg{
+			{name: "kind", kind: argChoice, optional: true, choices: scheduleKinds}, argOptional("starts_at", argTime),
+			argOptional("ends_at", argTime), argOptional("timezone", argString)}},
+		{name: "cancel_schedule", roles: rolesOperator, handler: (*Bot).handleCancelSchedule, args: []commandArg{
+			argOneOf("kind", scheduleKinds...)}},
+		{name: "remind", roles: rolesOperator, handler: (*Bot).handleRemind, args: []commandArg{
+			argOptional("text", argText)}},
+		{name: "remind_at", roles: rolesOperator, handler: (*Bot).handleRemindAt, args: []commandArg{
+			argRequired("send_at", argTime), argOptional("text", argText)}},
+		{name: "reminders", roles: rolesOperator, handler: (*Bot).handleReminders},
+		{name: "cancel_reminder", roles: rolesOperator, handler: (*Bot).handleCancelReminder, args: []commandArg{
+			argRequired("id", argInt)}},
+		// Результаты
+		{name: "results", roles: rolesTally, handler: (*Bot).handleResults},
+		{name: "print", roles: rolesTally, handler: (*Bot).handlePrint},
+		{name: "csv", roles: rolesTally, handler: (*Bot).handleCSV},
+		{name: "xlsx", roles: rolesTally, handler: (*Bot).handleXLSX},
+		{name: "protocol", roles: rolesTally, handler: (*Bot).handleProtocol},
+		// Импорт
+		{name: "import", roles: rolesOperator, handler: (*Bot).handleImportHelp},
+		// Администраторы
+		{name: "grant", roles: rolesSuperadmin, handler: (*Bot).handleGrant, args: []commandArg{
+			argRequired("telegram_id", argTelegramID), argOneOf("role", adminRoles...)}},
+		{name: "revoke", roles: rolesSuperadmin, handler: (*Bot).handleRevoke, args: []commandArg{
+			argRequired("telegram_id", argTelegramID)}},
+		{name: "admins", roles: rolesSuperadmin, handler: (*Bot).handleAdmins},
+		// Тексты сообщений
+		{name: "texts", roles: rolesOperator, handler: (*Bot).handleTexts},
+		{name: "text", roles: rolesOperator, handler: (*Bot).handleShowText, args: []commandArg{
+			argRequired("key", argString)}},
+		{name: "set_text", roles: rolesOperator, handler: (*Bot).handleSetText, args: []commandArg{
+			argOneOf("lang", i18n.Languages...), argRequired("key", argString), argRequired("text", argText)}},
+		{name: "reset_text", roles: rolesOperator, handler: (*Bot).handleResetText, args: []commandArg{
+			argOneOf("lang", i18n.Languages...), argRequired("key", argString)}},
+		// Уровень логирования
+		{name: "log", roles: rolesSuperadmin, handler: (*Bot).handleLog, args: []commandArg{
+			argOneOf("level", "Debug", "Info", "Warn", "Error")}},
+		{name: "send_logs", roles: rolesSuperadmin, handler: (*Bot).handleSendLogs},
+	}
+}
+
+// findCommand ищет команду по имени
+func findCommand(name string) (botCommand, bool) {
+	for _, command := range commands {
+		if command.name == name {
+			return command, true
+		}
+	}
+	return botCommand{}, false
+}
+
+// allows проверяет, доступна ли команда роли
+func (c botCommand) allows(role string) bool {
+	return role == models.RoleSuperadmin || slices.Contains(c.roles, role)
+}
+
+// isAdmin сообщает, что команда доступна только администраторам
+func (c botCommand) isAdmin() bool {
+	return !slices.Contains(c.roles, roleNone)
+}
+
+// availableIn проверяет, доступна ли команда роли в личном чате (private) или в чате администраторов
+func (c botCommand) availableIn(role string, private bool) bool {
+	return c.allows(role) && (private || !c.private)
+}
+
+// description возвращает описание команды на языке из контекста
+func (c botCommand) description(ctx context.Context) string {
+	if c.isAdmin() {
+		return tr(ctx, "admin.command."+c.name)
+	}
+	return tr(ctx, "command."+c.name)
+}
+
+// usage возвращает подсказку по вызову: /grant <telegram_id>, <superadmin|operator|observer|tally_officer>
+func (c botCommand) usage(ctx context.Context) string {
+	parts := []string{"/" + c.name}
+	for i, arg := range c.args {
+		label := "<" + arg.label(ctx) + ">"
+		if arg.optional {
+			label = "[" + label + "]"
+		}
+		if i > 0 {
+			parts[len(parts)-1] += ","
+		}
+		parts = append(parts, label)
+	}
+	return strings.Join(parts, " ")
+}
+
+// label возвращает подпись аргумента в подсказке
+func (a commandArg) label(ctx context.Context) string {
+	switch a.kind {
+	case argChoice:
+		return strings.Join(a.choices, "|")
+	case argTime:
+		return tr(ctx, "command.time_format")
+	}
+	return a.name
+}
+
+// commandArgs — проверенные значения аргументов команды по именам
+type commandArgs map[string]string
+
+type commandArgsKey struct{}
+
+// withCommandArgs сохраняет разобранные аргументы команды в контексте
+func withCommandArgs(ctx context.Context, args commandArgs) context.Context {
+	return context.WithValue(ctx, commandArgsKey{}, args)
+}
+
+// commandArgsFromContext возвращает разобранные аргументы команды
+func commandArgsFromContext(ctx context.Context) commandArgs {
+	args, _ := ctx.Value(commandArgsKey{}).(commandArgs)
+	return args
+}
+
+// has сообщает, указан ли необязательный аргумент
+func (a commandArgs) has(name string) bool {
+	_, ok := a[name]
+	return ok
+}
+
+// text возвращает значение аргумента как строку
+func (a commandArgs) text(name string) string {
+	return a[name]
+}
+
+// number возвращает значение аргумента argID или argInt
+func (a commandArgs) number(name string) int {
+	n, _ := strconv.Atoi(a[name])
+	return n
+}
+
+// telegramID возвращает значение аргумента argTelegramID
+func (a commandArgs) telegramID(name string) int64 {
+	id, _ := strconv.ParseInt(a[name], 10, 64)
+	return id
+}
+
+// timeIn возвращает значение аргумента argTime в часовом поясе location
+func (a commandArgs) timeIn(name string, location *time.Location) time.Time {
+	t, _ := time.ParseInLocation(scheduleTimeLayout, a[name], location)
+	return t
+}
+
+// argError — ошибка в аргументах команды. Пустой arg — лишние аргументы
+type argError struct {
+	arg     commandArg
+	missing bool
+}
+
+func (e *argError) Error() string {
+	if e.arg.name == "" {
+		return "too many arguments"
+	}
+	if e.missing {
+		return "missing argument " + e.arg.name
+	}
+	return "invalid argument " + e.arg.name
+}
+
+// message возвращает описание ошибки с подсказкой по вызову команды на языке из контекста
+func (e *argError) message(ctx context.Context, command botCommand) string {
+	usage := command.usage(ctx)
+	switch {
+	case e.arg.name == "":
+		return tr(ctx, "command.extra_args", usage)
+	case e.missing:
+		return tr(ctx, "command.missing_arg", e.arg.label(ctx), usage)
+	case e.arg.kind == argChoice:
+		return tr(ctx, "command.invalid_arg", e.arg.name, tr(ctx, "command.arg.choice", strings.Join(e.arg.choices, ", ")), usage)
+	}
+	return tr(ctx, "command.invalid_arg", e.arg.name, tr(ctx, "command.arg."+string(e.arg.kind)), usage)
+}
+
+// parseArgs разбирает аргументы команды. Команды без аргументов игнорируют текст после команды:
+// например, параметр deep link в /start
+func (c botCommand) parseArgs(raw string) (commandArgs, error) {
+	args := commandArgs{}
+	if len(c.args) == 0 {
+		return args, nil
+	}
+	raw = strings.TrimSpace(raw)
+
+	var parts []string
+	if raw != "" {
+		if c.args[len(c.args)-1].kind == argText {
+			parts = strings.SplitN(raw, ",", len(c.args))
+		} else {
+			parts = strings.Split(raw, ",")
+		}
+	}
+	if len(parts) > len(c.args) {
+		return nil, &argError{}
+	}
+	for i, arg := range c.args {
+		var value string
+		if i < len(parts) {
+			value = strings.TrimSpace(parts[i])
+		}
+		if value == "" {
+			if !arg.optional {
+				return nil, &argError{arg: arg, missing: true}
+			}
+			continue
+		}
+		if !arg.valid(value) {
+			return nil, &argError{arg: arg}
+		}
+		args[arg.name] = value
+	}
+	return args, nil
+}
+
+// valid проверяет значение аргумента по его типу
+func (a commandArg) valid(value string) bool {
+	switch a.kind {
+	case argID:
+		return isValidID(value)
+	case argTelegramID:
+		_, err := strconv.ParseInt(value, 10, 64)
+		return err == nil
+	case argInt:
+		_, err := strconv.Atoi(value)
+		return err == nil
+	case argTime:
+		_, err := time.Parse(scheduleTimeLayout, value)
+		return err == nil
+	case argURL:
+		return isValidURL(value)
+	case argCourse:
+		return isValidCourse(value)
+	case argChoice:
+		return slices.Contains(a.choices, value)
+	}
+	return true
+}
+
+// commandMiddleware оборачивает обработчик команды
+type commandMiddleware func(command botCommand, next commandHandler) commandHandler
+
+// commandMiddlewares применяются по порядку: первая выполняется раньше остальных
+var commandMiddlewares = []commandMiddleware{recoverCommand, logCommand, limitCommand, authorizeCommand, parseCommandArgs}
+
+// handleCommand находит команду и выполняет ее через цепочку middleware
+func (b *Bot) handleCommand(ctx context.Context, message *tgbotapi.Message) {
+	command, ok := findCommand(message.Command())
+	if !ok {
+		command = botCommand{name: message.Command(), roles: rolesEveryone, handler: (*Bot).handleUnknownCommand}
+	}
+	handler := command.handler
+	for i := len(commandMiddlewares) - 1; i >= 0; i-- {
+		handler = commandMiddlewares[i](command, handler)
+	}
+	handler(b, ctx, message)
+}
+
+// recoverCommand перехватывает панику в обработчике, чтобы одна команда не останавливала бота
+func recoverCommand(command botCommand, next commandHandler) commandHandler {
+	return func(b *Bot, ctx context.Context, message *tgbotapi.Message) {
+		defer func() {
+			if r := recover(); r != nil {
+				log.Errorf("%d Паника в команде /%s: %v\n%s", message.Chat.ID, command.name, r, debug.Stack())
+				b.SendMessage(message.Chat.ID, tr(ctx, "error.generic"))
+			}
+		}()
+		next(b, ctx, message)
+	}
+}
+
+// logCommand записывает команду и время ее выполнения в журнал отладки
+func logCommand(command botCommand, next commandHandler) commandHandler {
+	return func(b *Bot, ctx context.Context, message *tgbotapi.Message) {
+		start := time.Now()
+		next(b, ctx, message)
+		log.Debugf("%d /%s выполнена за %s", message.Chat.ID, command.name, time.Since(start).Round(time.Millisecond))
+	}
+}
+
+// limitCommand отбрасывает команды пользователя, превысившего частоту команд
+func limitCommand(command botCommand, next commandHandler) commandHandler {
+	return func(b *Bot, ctx context.Context, message *tgbotapi.Message) {
+		if message.From != nil {
+			if notice, limited := b.throttled(ctx, rateCommands, message.From.ID); limited {
+				if notice != "" {
+					b.SendMessage(message.Chat.ID, notice)
+				}
+				return
+			}
+		}
+		next(b, ctx, message)
+	}
+}
+
+// authorizeCommand определяет роль отправителя и проверяет доступ к команде. Команды принимаются только
+// в личных сообщениях и в чате администраторов
+func authorizeCommand(command botCommand, next commandHandler) commandHandler {
+	return func(b *Bot, ctx context.Context, message *tgbotapi.Message) {
+		if !message.Chat.IsPrivate() && message.Chat.ID != config.AdminChatID {
+			return
+		}
+		role, err := b.adminRole(ctx, message.Chat, message.From)
+		if err != nil {
+			log.Errorf("%d Ошибка проверки роли администратора: %v", message.Chat.ID, err)
+			return
+		}
+		switch {
+		// Администрирующие команды скрыты от пользователей без роли, команды делегатов — в чате администраторов
+		case !command.availableIn(role, message.Chat.IsPrivate()) && (role == roleNone || command.private):
+			b.handleUnknownCommand(ctx, message)
+			return
+		case !command.allows(role):
+			log.Warnf("%d Недостаточно прав для команды /%s (роль %s)", message.From.ID, command.name, role)
+			b.SendMessage(message.Chat.ID, tr(ctx, "admin.forbidden"))
+			return
+		}
+		next(b, withAdminRole(ctx, role), message)
+	}
+}
+
+// parseCommandArgs проверяет аргументы команды и отвечает подсказкой по вызову, если они неверны
+func parseCommandArgs(command botCommand, next commandHandler) commandHandler {
+	return func(b *Bot, ctx context.Context, message *tgbotapi.Message) {
+		args, err := command.parseArgs(message.CommandArguments())
+		var argErr *argError
+		if errors.As(err, &argErr) {
+			log.Debugf("%d /%s: %v", message.Chat.ID, command.name, err)
+			// Без HTML разметки: в подсказках есть угловые скобки
+			b.botAPI.Send(tgbotapi.NewMessage(message.Chat.ID, argErr.message(ctx, command)))
+			return
+		}
+		next(b, withCommandArgs(ctx, args), message)
+	}
+}
+
+// Ответ на неизвестную команду
+func (b *Bot) handleUnknownCommand(ctx context.Context, message *tgbotapi.Message) {
+	key := "command.unknown"
+	if message.Chat.ID == config.AdminChatID {
+		key = "admin.unknown_command"
+	}
+	b.botAPI.Send(tgbotapi.NewMessage(message.Chat.ID, tr(ctx, key)))
+}
+
+// Обработчик команды /help: список команд, доступных отправителю в этом чате
+func (b *Bot) handleHelp(ctx context.Context, message *tgbotapi.Message) {
+	role := adminRoleFromContext(ctx)
+	text := tr(ctx, "help.header")
+	if role != roleNone {
+		text = tr(ctx, "admin.help_header", roleName(ctx, role))
+	}
+	text += "\n"
+	for _, command := range commands {
+		if command.availableIn(role, message.Chat.IsPrivate()) {
+			text += command.usage(ctx) + " - " + command.description(ctx) + "\n"
+		}
+	}
+	if role != roleNone {
+		text += "\n" + tr(ctx, "admin.help_footer")
+	}
+	// Без HTML разметки: в подсказках есть угловые скобки
+	b.botAPI.Send(tgbotapi.NewMessage(message.Chat.ID, text))
+}
+
+// menuCommands возвращает меню команд роли в личном чате (private) или в чате администраторов
+func menuCommands(ctx context.Context, role string, private bool) []tgbotapi.BotCommand {
+	var menu []tgbotapi.BotCommand
+	for _, command := range commands {
+		if command.availableIn(role, private) {
+			menu = append(menu, tgbotapi.BotCommand{Command: command.name, Description: command.description(ctx)})
+		}
+	}
+	return menu
+}
+
+// SyncCommands публикует меню команд: пользователям — в личных сообщениях, суперадминистраторам —
+// в чате администраторов, администраторам — в их личных сообщениях по ролям
+func (b *Bot) SyncCommands(ctx context.Context) error {
+	if err := b.setCommands(ctx, tgbotapi.NewBotCommandScopeAllPrivateChats(), roleNone, true); err != nil {
+		return fmt.Errorf("SyncCommands: %w", err)
+	}
+	if config.AdminChatID != 0 {
+		if err := b.setCommands(ctx, tgbotapi.NewBotCommandScopeChat(config.AdminChatID), models.RoleSuperadmin, false); err != nil {
+			return fmt.Errorf("SyncCommands: %w", err)
+		}
+	}
+	admins, err := b.voteChain.GetAllAdmins(ctx)
+	if err != nil {
+		return fmt.Errorf("SyncCommands: %w", err)
+	}
+	for _, admin := range admins {
+		// Меню нельзя задать пользователю, который не начинал диалог с ботом: остальные меню все равно публикуются
+		if err := b.setAdminCommands(ctx, admin.TelegramID, admin.Role); err != nil {
+			log.Warnf("%d Не удалось опубликовать меню команд администратора: %v", admin.TelegramID, err)
+		}
+	}
+	return nil
+}
+
+// setAdminCommands публикует меню команд администратора в его личных сообщениях.
+// Пустая роль удаляет меню: остается меню для всех личных сообщений
+func (b *Bot) setAdminCommands(ctx context.Context, telegramID int64, role string) error {
+	scope := tgbotapi.NewBotCommandScopeChat(telegramID)
+	if role == roleNone {
+		for _, lang := range i18n.Languages {
+			if _, err := b.botAPI.Request(tgbotapi.NewDeleteMyCommandsWithScopeAndLanguage(scope, commandsLanguageCode(lang))); err != nil {
+				return fmt.Errorf("setAdminCommands: %w", err)
+			}
+		}
+		return nil
+	}
+	if err := b.setCommands(ctx, scope, role, true); err != nil {
+		return fmt.Errorf("setAdminCommands: %w", err)
+	}
+	return nil
+}
+
+// setCommands публикует меню команд роли для области видимости на всех языках
+func (b *Bot) setCommands(ctx context.Context, scope tgbotapi.BotCommandScope, role string, private bool) error {
+	for _, lang := range i18n.Languages {
+		menu := menuCommands(withLanguage(ctx, lang), role, private)
+		if _, err := b.botAPI.Request(tgbotapi.NewSetMyCommandsWithScopeAndLanguage(scope, commandsLanguageCode(lang), menu...)); err != nil {
+			return fmt.Errorf("setCommands: %w", err)
+		}
+	}
+	return nil
+}
+
+// commandsLanguageCode возвращает language_code меню: меню на языке по умолчанию видят пользователи
+// с любым языком, для которого нет отдельного меню
+func commandsLanguageCode(lang string) string {
+	if lang == i18n.DefaultLanguage {
+		return ""
+	}
+	return lang
+}
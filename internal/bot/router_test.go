@@ -0,0 +1,144 @@
+package bot
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/lsdpls/schulze_election_telegram_bot/internal/i18n"
+	"github.com/lsdpls/schulze_election_telegram_bot/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseArgs(t *testing.T) {
+	t.Parallel()
+
+	command := botCommand{name: "test", args: []commandArg{
+		argRequired("id", argID), argOneOf("lang", i18n.Languages...), argOptional("text", argText),
+	}}
+	tests := []struct {
+		raw     string
+		want    commandArgs
+		missing string
+		invalid string
+	}{
+		{raw: "123456, en", want: commandArgs{"id": "123456", "lang": "en"}},
+		{raw: " 123456 ,ru, текст, с запятыми ", want: commandArgs{"id": "123456", "lang": "ru", "text": "текст, с запятыми"}},
+		{raw: "", missing: "id"},
+		{raw: "123456", missing: "lang"},
+		{raw: "123456, , text", missing: "lang"},
+		{raw: "12345, ru", invalid: "id"},
+		{raw: "123456, de", invalid: "lang"},
+	}
+	for _, tt := range tests {
+		args, err := command.parseArgs(tt.raw)
+		if tt.missing == "" && tt.invalid == "" {
+			require.NoError(t, err, tt.raw)
+			assert.Equal(t, tt.want, args, tt.raw)
+			continue
+		}
+		var argErr *argError
+		if assert.ErrorAs(t, err, &argErr, tt.raw) {
+			assert.Equal(t, tt.missing+tt.invalid, argErr.arg.name, tt.raw)
+			assert.Equal(t, tt.missing != "", argErr.missing, tt.raw)
+		}
+	}
+
+	// Лишние аргументы — ошибка, если команда принимает аргументы
+	withoutText := botCommand{name: "test", args: []commandArg{argRequired("id", argID)}}
+	_, err := withoutText.parseArgs("123456, 654321")
+	var argErr *argError
+	if assert.ErrorAs(t, err, &argErr) {
+		assert.Empty(t, argErr.arg.name)
+	}
+	// Команда без аргументов игнорирует текст после команды
+	args, err := botCommand{name: "start"}.parseArgs("deep-link-payload")
+	require.NoError(t, err)
+	assert.Empty(t, args)
+}
+
+func TestCommandArgsValues(t *testing.T) {
+	t.Parallel()
+
+	args, err := botCommand{name: "test", args: []commandArg{
+		argRequired("id", argID), argRequired("telegram_id", argTelegramID), argRequired("at", argTime), argOptional("url", argURL),
+	}}.parseArgs("012345, -1001234567890, 19.10.2026 12:30")
+	require.NoError(t, err)
+	assert.Equal(t, 12345, args.number("id"))
+	assert.Equal(t, int64(-1001234567890), args.telegramID("telegram_id"))
+	assert.Equal(t, "2026-10-19 12:30", args.timeIn("at", time.UTC).Format("2006-01-02 15:04"))
+	assert.False(t, args.has("url"))
+}
+
+func TestCommandUsage(t *testing.T) {
+	t.Parallel()
+
+	ctx := withLanguage(context.Background(), i18n.Russian)
+	command, ok := findCommand("remind_at")
+	require.True(t, ok)
+	assert.Equal(t, "/remind_at <ДД.ММ.ГГГГ ЧЧ:ММ>, [<text>]", command.usage(ctx))
+	command, ok = findCommand("grant")
+	require.True(t, ok)
+	assert.Equal(t, "/grant <telegram_id>, <superadmin|operator|observer|tally_officer>", command.usage(ctx))
+}
+
+// Аргументы каждой команды можно разобрать, а меню принимается Telegram
+func TestCommandRegistry(t *testing.T) {
+	t.Parallel()
+
+	nameRe := regexp.MustCompile(`^[a-z0-9_]{1,32}$`)
+	names := make(map[string]bool)
+	for _, command := range commands {
+		assert.Regexp(t, nameRe, command.name)
+		assert.False(t, names[command.name], "duplicate command %s", command.name)
+		names[command.name] = true
+		for i, arg := range command.args {
+			if arg.kind == argText {
+				assert.Equal(t, len(command.args)-1, i, "%s: text argument must be the last one", command.name)
+			}
+			if i > 0 && command.args[i-1].optional {
+				assert.True(t, arg.optional, "%s: required argument after an optional one", command.name)
+			}
+			if arg.kind == argChoice {
+				assert.NotEmpty(t, arg.choices, command.name)
+			}
+		}
+	}
+
+	for _, lang := range i18n.Languages {
+		ctx := withLanguage(context.Background(), lang)
+		for _, command := range commands {
+			description := command.description(ctx)
+			assert.GreaterOrEqual(t, len([]rune(description)), 3, "%s %s", lang, command.name)
+			assert.LessOrEqual(t, len([]rune(description)), 256, "%s %s", lang, command.name)
+		}
+	}
+}
+
+func TestMenuCommands(t *testing.T) {
+	t.Parallel()
+
+	ctx := withLanguage(context.Background(), i18n.English)
+	menuNames := func(role string, private bool) []string {
+		var names []string
+		for _, command := range menuCommands(ctx, role, private) {
+			names = append(names, command.Command)
+		}
+		return names
+	}
+
+	assert.Equal(t, []string{"start", "vote", "verify", "candidates", "language", "proxy", "revoke_proxy", "help"}, menuNames(roleNone, true))
+	// В чате администраторов нет команд делегатов
+	adminChat := menuNames(models.RoleSuperadmin, false)
+	assert.NotContains(t, adminChat, "vote")
+	assert.Contains(t, adminChat, "help")
+	assert.Contains(t, adminChat, "grant")
+	// Наблюдателю в личных сообщениях доступны команды делегата и просмотра
+	observer := menuNames(models.RoleObserver, true)
+	assert.Contains(t, observer, "vote")
+	assert.Contains(t, observer, "show_votes")
+	assert.NotContains(t, observer, "delete_delegate")
+}
@@ -0,0 +1,309 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/lsdpls/schulze_election_telegram_bot/internal/config"
+	"github.com/lsdpls/schulze_election_telegram_bot/internal/models"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// Формат времени в командах расписания и объявлениях
+const scheduleTimeLayout = "02.01.2006 15:04"
+
+// Объявления обратного отсчета до начала и окончания окна (от самого раннего к самому позднему)
+var scheduleCountdowns = []struct {
+	label  string
+	offset time.Duration
+}{
+	{"24h", 24 * time.Hour},
+	{"1h", time.Hour},
+	{"10m", 10 * time.Minute},
+}
+
+// Названия окон в журнале
+// Виды окон расписания в порядке вывода в подсказке
+var scheduleKinds = []string{models.ScheduleRegistration, models.ScheduleVoting}
+
+var scheduleKindNames = map[string]string{
+	models.ScheduleRegistration: "регистрации",
+	models.ScheduleVoting:       "голосования",
+}
+
+// RunScheduler открывает и закрывает окна расписания, рассылает объявления и напоминания до отмены контекста.
+// Первая проверка выполняется сразу, чтобы наверстать пропущенное за время перезапуска
+func (b *Bot) RunScheduler(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		b.processSchedule(ctx, time.Now())
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// processSchedule обрабатывает все окна расписания на момент now
+func (b *Bot) processSchedule(ctx context.Context, now time.Time) {
+	windows, err := b.voteChain.GetAllScheduleWindows(ctx)
+	if err != nil {
+		log.Errorf("Ошибка получения расписания: %v", err)
+		return
+	}
+	for _, window := range windows {
+		if err := b.processScheduleWindow(ctx, window, now); err != nil {
+			log.Errorf("Ошибка обработки окна %s: %v", window.Kind, err)
+		}
+	}
+	b.processReminders(ctx, now)
+}
+
+func (b *Bot) processScheduleWindow(ctx context.Context, window models.ScheduleWindow, now time.Time) error {
+	kindName := scheduleKindNames[window.Kind]
+
+	// Обратный отсчет до начала
+	if !window.OpenedAt.Valid && !window.ClosedAt.Valid {
+		if due, mark := dueCountdown("start", window.StartsAt, now, window.Announcements); due != "" {
+			window.Announcements = append(window.Announcements, mark...)
+			if err := b.voteChain.UpdateScheduleWindowProgress(ctx, window); err != nil {
+				return fmt.Errorf("processScheduleWindow: %w", err)
+			}
+			b.announceSchedule(ctx, window, false, func(ctx context.Context) string {
+				return tr(ctx, "schedule.countdown_start."+window.Kind,
+					countdownText(ctx, due), formatScheduleTime(window.StartsAt, window.Timezone))
+			})
+		}
+	}
+
+	// Открытие окна
+	if !window.OpenedAt.Valid && !window.ClosedAt.Valid && !now.Before(window.StartsAt) && now.Before(window.EndsAt) {
+		if window.Kind == models.ScheduleVoting {
+			if err := b.SetCandidates(); err != nil {
+				return fmt.Errorf("processScheduleWindow: %w", err)
+			}
+			if err := b.setActiveVoting(ctx, true); err != nil {
+				return fmt.Errorf("processScheduleWindow: %w", err)
+			}
+		}
+		window.OpenedAt.Time, window.OpenedAt.Valid = now, true
+		if err := b.voteChain.UpdateScheduleWindowProgress(ctx, window); err != nil {
+			return fmt.Errorf("processScheduleWindow: %w", err)
+		}
+		log.Warnf("Окно %s открыто по расписанию", kindName)
+		b.announceSchedule(ctx, window, false, func(ctx context.Context) string {
+			return tr(ctx, "schedule.opened."+window.Kind, formatScheduleTime(window.EndsAt, window.Timezone))
+		})
+	}
+
+	// Обратный отсчет до окончания
+	if window.OpenedAt.Valid && !window.ClosedAt.Valid {
+		if due, mark := dueCountdown("end", window.EndsAt, now, window.Announcements); due != "" {
+			window.Announcements = append(window.Announcements, mark...)
+			if err := b.voteChain.UpdateScheduleWindowProgress(ctx, window); err != nil {
+				return fmt.Errorf("processScheduleWindow: %w", err)
+			}
+			b.announceSchedule(ctx, window, true, func(ctx context.Context) string {
+				return tr(ctx, "schedule.countdown_end."+window.Kind,
+					countdownText(ctx, due), formatScheduleTime(window.EndsAt, window.Timezone))
+			})
+		}
+	}
+
+	// Закрытие окна (в том числе если бот был выключен все время окна)
+	if !window.ClosedAt.Valid && !now.Before(window.EndsAt) {
+		if window.Kind == models.ScheduleVoting {
+			if err := b.setActiveVoting(ctx, false); err != nil {
+				return fmt.Errorf("processScheduleWindow: %w", err)
+			}
+		}
+		window.ClosedAt.Time, window.ClosedAt.Valid = now, true
+		if err := b.voteChain.UpdateScheduleWindowProgress(ctx, window); err != nil {
+			return fmt.Errorf("processScheduleWindow: %w", err)
+		}
+		log.Warnf("Окно %s закрыто по расписанию", kindName)
+		b.announceSchedule(ctx, window, false, func(ctx context.Context) string {
+			return tr(ctx, "schedule.closed."+window.Kind)
+		})
+	}
+	return nil
+}
+
+// dueCountdown возвращает объявление обратного отсчета, которое пора отправить, и все метки, которые нужно
+// отметить отправленными. После простоя бота отправляется только самое позднее из пропущенных объявлений
+func dueCountdown(prefix string, target, now time.Time, sent []string) (string, []string) {
+	if !now.Before(target) {
+		return "", nil
+	}
+	var due string
+	var mark []string
+	for _, countdown := range scheduleCountdowns {
+		label := prefix + "-" + countdown.label
+		if now.Before(target.Add(-countdown.offset)) {
+			continue
+		}
+		due = label
+		if !slices.Contains(sent, label) {
+			mark = append(mark, label)
+		}
+	}
+	if due == "" || slices.Contains(sent, due) {
+		return "", nil
+	}
+	return due, mark
+}
+
+// countdownText возвращает текст обратного отсчета по метке объявления
+func countdownText(ctx context.Context, label string) string {
+	for _, countdown := range scheduleCountdowns {
+		if strings.HasSuffix(label, "-"+countdown.label) {
+			return tr(ctx, "schedule.in_"+countdown.label)
+		}
+	}
+	return label
+}
+
+// announceSchedule отправляет объявление в чат администраторов, а объявления о голосовании — и делегатам.
+// onlyNotVoted ограничивает рассылку делегатами, которые еще не проголосовали
+func (b *Bot) announceSchedule(ctx context.Context, window models.ScheduleWindow, onlyNotVoted bool, text localizedText) {
+	if config.AdminChatID != 0 {
+		if err := b.SendMessage(config.AdminChatID, text(ctx)); err != nil {
+			log.Errorf("Ошибка отправки объявления в чат администраторов: %v", err)
+		}
+	}
+	// Незарегистрированным делегатам писать некуда, поэтому объявления о регистрации только для администраторов
+	if window.Kind != models.ScheduleVoting {
+		return
+	}
+	chatIDs, err := b.delegateChatIDs(ctx, onlyNotVoted)
+	if err != nil {
+		log.Errorf("Ошибка получения списка делегатов для рассылки: %v", err)
+		return
+	}
+	report := b.broadcast(ctx, chatIDs, text)
+	log.Infof("Объявление разослано делегатам: %s", report)
+}
+
+// checkScheduleWindow проверяет, что сейчас идет окно kind (если оно задано), и уведомляет пользователя об обратном
+func (b *Bot) checkScheduleWindow(ctx context.Context, chatID int64, kind string) bool {
+	window, err := b.voteChain.GetScheduleWindow(ctx, kind)
+	if err != nil {
+		log.Errorf("%d Ошибка получения расписания: %v", chatID, err)
+		b.SendMessage(chatID, tr(ctx, "error.generic"))
+		return false
+	}
+	if window == nil {
+		return true
+	}
+	now := time.Now()
+	if now.Before(window.StartsAt) {
+		b.SendMessage(chatID, tr(ctx, "schedule.not_started."+kind, formatScheduleTime(window.StartsAt, window.Timezone)))
+		return false
+	}
+	if !now.Before(window.EndsAt) {
+		b.SendMessage(chatID, tr(ctx, "schedule.ended."+kind))
+		return false
+	}
+	return true
+}
+
+// formatScheduleTime форматирует время в часовом поясе окна
+func formatScheduleTime(t time.Time, timezone string) string {
+	location, err := time.LoadLocation(timezone)
+	if err != nil {
+		location = time.UTC
+	}
+	return fmt.Sprintf("%s (%s)", t.In(location).Format(scheduleTimeLayout), location)
+}
+
+// Обработчик команды /schedule
+func (b *Bot) handleSchedule(ctx context.Context, message *tgbotapi.Message) {
+	chatID := message.Chat.ID
+	args := commandArgsFromContext(ctx)
+	if !args.has("kind") {
+		b.sendSchedule(ctx, chatID)
+		return
+	}
+	if !args.has("starts_at") || !args.has("ends_at") {
+		command, _ := findCommand("schedule")
+		b.botAPI.Send(tgbotapi.NewMessage(chatID, tr(ctx, "command.missing_arg", tr(ctx, "command.time_format"), command.usage(ctx))))
+		return
+	}
+	kind := args.text("kind")
+	timezone := config.ElectionTimezone
+	if args.has("timezone") {
+		timezone = args.text("timezone")
+	}
+	location, err := time.LoadLocation(timezone)
+	if err != nil {
+		log.Warnf("%d Неизвестный часовой пояс %s: %v", chatID, timezone, err)
+		b.SendMessage(chatID, tr(ctx, "schedule.unknown_timezone", html.EscapeString(timezone)))
+		return
+	}
+	startsAt, endsAt := args.timeIn("starts_at", location), args.timeIn("ends_at", location)
+	if !endsAt.After(startsAt) {
+		b.SendMessage(chatID, tr(ctx, "schedule.ends_before_start"))
+		return
+	}
+	if !endsAt.After(time.Now()) {
+		b.SendMessage(chatID, tr(ctx, "schedule.ends_in_past"))
+		return
+	}
+
+	window := models.ScheduleWindow{Kind: kind, StartsAt: startsAt, EndsAt: endsAt, Timezone: location.String()}
+	if err := b.voteChain.SetScheduleWindow(ctx, window); err != nil {
+		log.Errorf("%d Ошибка сохранения расписания: %v", chatID, err)
+		b.SendMessage(chatID, tr(ctx, "error.generic"))
+		return
+	}
+	log.Infof("%d Период %s запланирован: %s — %s", chatID, scheduleKindNames[kind],
+		formatScheduleTime(startsAt, window.Timezone), formatScheduleTime(endsAt, window.Timezone))
+	b.SendMessage(chatID, tr(ctx, "schedule.saved."+kind,
+		formatScheduleTime(startsAt, window.Timezone), formatScheduleTime(endsAt, window.Timezone)))
+}
+
+// Обработчик команды /cancel_schedule
+func (b *Bot) handleCancelSchedule(ctx context.Context, message *tgbotapi.Message) {
+	chatID := message.Chat.ID
+	kind := commandArgsFromContext(ctx).text("kind")
+	if err := b.voteChain.DeleteScheduleWindow(ctx, kind); err != nil {
+		log.Errorf("%d Ошибка удаления расписания: %v", chatID, err)
+		b.SendMessage(chatID, tr(ctx, "error.generic"))
+		return
+	}
+	log.Infof("%d Расписание %s отменено", chatID, scheduleKindNames[kind])
+	b.SendMessage(chatID, tr(ctx, "schedule.cancelled."+kind))
+}
+
+// sendSchedule отправляет текущее расписание в чат
+func (b *Bot) sendSchedule(ctx context.Context, chatID int64) {
+	windows, err := b.voteChain.GetAllScheduleWindows(ctx)
+	if err != nil {
+		log.Errorf("%d Ошибка получения расписания: %v", chatID, err)
+		return
+	}
+	if len(windows) == 0 {
+		b.SendMessage(chatID, tr(ctx, "schedule.none"))
+		return
+	}
+	text := "<b>" + tr(ctx, "schedule.header") + "</b>\n"
+	for _, window := range windows {
+		status := tr(ctx, "schedule.status.pending")
+		switch {
+		case window.ClosedAt.Valid:
+			status = tr(ctx, "schedule.status.closed")
+		case window.OpenedAt.Valid:
+			status = tr(ctx, "schedule.status.open")
+		}
+		text += "\n• " + tr(ctx, "schedule.line."+window.Kind,
+			formatScheduleTime(window.StartsAt, window.Timezone), formatScheduleTime(window.EndsAt, window.Timezone), status)
+	}
+	b.SendMessage(chatID, text)
+}
@@ -0,0 +1,35 @@
+package bot
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDueCountdown(t *testing.T) {
+	t.Parallel()
+
+	target := time.Date(2024, 10, 14, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name     string
+		now      time.Time
+		sent     []string
+		wantDue  string
+		wantMark []string
+	}{
+		{"too early", target.Add(-48 * time.Hour), nil, "", nil},
+		{"first countdown", target.Add(-23 * time.Hour), nil, "start-24h", []string{"start-24h"}},
+		{"already sent", target.Add(-23 * time.Hour), []string{"start-24h"}, "", nil},
+		{"next countdown", target.Add(-30 * time.Minute), []string{"start-24h"}, "start-1h", []string{"start-1h"}},
+		{"after downtime only latest is sent", target.Add(-5 * time.Minute), nil, "start-10m", []string{"start-24h", "start-1h", "start-10m"}},
+		{"target passed", target, nil, "", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			due, mark := dueCountdown("start", target, tt.now, tt.sent)
+			assert.Equal(t, tt.wantDue, due)
+			assert.Equal(t, tt.wantMark, mark)
+		})
+	}
+}
@@ -0,0 +1,206 @@
+package bot
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Сроки хранения состояний пользователей
+const (
+	registrationSessionTTL = 24 * time.Hour   // Незавершенная регистрация
+	ballotSessionTTL       = 24 * time.Hour   // Незаполненный бюллетень
+	rosterImportSessionTTL = 30 * time.Minute // Импорт, ожидающий подтверждения
+	confirmationSessionTTL = 5 * time.Minute  // Необратимое действие, ожидающее подтверждения
+	editSessionTTL         = 10 * time.Minute // Мастер изменения, ожидающий новое значение поля
+)
+
+// sessionStore хранит состояния пользователей и бота между перезапусками
+type sessionStore interface {
+	Get(ctx context.Context, key string, dest any) (bool, error)
+	Set(ctx context.Context, key string, value any, ttl time.Duration) error
+	Take(ctx context.Context, key string, dest any) (bool, error)
+	Delete(ctx context.Context, key string) error
+}
+
+// registrationSession хранит состояние регистрации делегата
+type registrationSession struct {
+	State         string    `json:"state"`                     // Текущее состояние (StateWaitingForEmail, StateWaitingForCode)
+	Code          int       `json:"code,omitempty"`            // Отправленный код подтверждения
+	DelegateID    int       `json:"delegate_id,omitempty"`     // ID делегата из введенной почты
+	CodeExpiresAt time.Time `json:"code_expires_at,omitempty"` // Время истечения кода
+	CodeAttempts  int       `json:"code_attempts,omitempty"`   // Количество неверных попыток ввода текущего кода
+	Proxy         bool      `json:"proxy,omitempty"`           // Почта принадлежит заместителю, DelegateID — его ID
+}
+
+// verificationSession хранит счетчики верификации Telegram аккаунта. Хранится бессрочно,
+// чтобы блокировку нельзя было снять повторной регистрацией; сбрасывается после успешной верификации или /unlock
+type verificationSession struct {
+	FailedAttempts int       `json:"failed_attempts"`        // Неверные попытки ввода кода по всем кодам
+	LastSentAt     time.Time `json:"last_sent_at,omitempty"` // Время последней отправки кода
+	Locked         bool      `json:"locked"`                 // Аккаунт заблокирован до разблокировки администратором
+	LockedAt       time.Time `json:"locked_at,omitempty"`    // Время блокировки
+}
+
+// ballotSession хранит незаполненный бюллетень. ID и номер версии передаются в данных кнопок,
+// чтобы нажатия в старых сообщениях не меняли текущий бюллетень
+type ballotSession struct {
+	ID         string `json:"id"`          // Случайный идентификатор, новый при каждом /vote
+	Seq        int    `json:"seq"`         // Версия клавиатуры, увеличивается при каждом обновлении сообщения
+	RankedList []int  `json:"ranked_list"` // Ранжированные кандидаты
+}
+
+// newBallotSession создает пустой бюллетень со случайным идентификатором
+func newBallotSession() (ballotSession, error) {
+	id := make([]byte, 4)
+	if _, err := rand.Read(id); err != nil {
+		return ballotSession{}, fmt.Errorf("newBallotSession: %w", err)
+	}
+	return ballotSession{ID: hex.EncodeToString(id), RankedList: []int{}}, nil
+}
+
+func registrationKey(telegramID int64) string {
+	return fmt.Sprintf("registration:%d", telegramID)
+}
+
+func verificationKey(telegramID int64) string {
+	return fmt.Sprintf("verification:%d", telegramID)
+}
+
+// emailCooldownKey — ключ паузы между отправками кода на одну почту (независимо от Telegram аккаунта)
+func emailCooldownKey(delegateID int) string {
+	return fmt.Sprintf("email_cooldown:%d", delegateID)
+}
+
+func rosterImportKey(chatID int64) string {
+	return fmt.Sprintf("roster_import:%d", chatID)
+}
+
+func confirmationKey(confirmationID string) string {
+	return "confirmation:" + confirmationID
+}
+
+// editKey — ключ мастера изменения: у каждого администратора в каждом чате свой
+func editKey(chatID, telegramID int64) string {
+	return fmt.Sprintf("edit:%d:%d", chatID, telegramID)
+}
+
+func ballotKey(telegramID int64) string {
+	return fmt.Sprintf("ballot:%d", telegramID)
+}
+
+func (b *Bot) getRegistration(ctx context.Context, telegramID int64) (registrationSession, bool, error) {
+	var registration registrationSession
+	ok, err := b.sessions.Get(ctx, registrationKey(telegramID), &registration)
+	if err != nil {
+		return registration, false, fmt.Errorf("getRegistration: %w", err)
+	}
+	return registration, ok, nil
+}
+
+func (b *Bot) setRegistration(ctx context.Context, telegramID int64, registration registrationSession) error {
+	if err := b.sessions.Set(ctx, registrationKey(telegramID), registration, registrationSessionTTL); err != nil {
+		return fmt.Errorf("setRegistration: %w", err)
+	}
+	return nil
+}
+
+func (b *Bot) clearRegistration(ctx context.Context, telegramID int64) error {
+	if err := b.sessions.Delete(ctx, registrationKey(telegramID)); err != nil {
+		return fmt.Errorf("clearRegistration: %w", err)
+	}
+	return nil
+}
+
+func (b *Bot) getVerification(ctx context.Context, telegramID int64) (verificationSession, error) {
+	var verification verificationSession
+	if _, err := b.sessions.Get(ctx, verificationKey(telegramID), &verification); err != nil {
+		return verification, fmt.Errorf("getVerification: %w", err)
+	}
+	return verification, nil
+}
+
+func (b *Bot) setVerification(ctx context.Context, telegramID int64, verification verificationSession) error {
+	if err := b.sessions.Set(ctx, verificationKey(telegramID), verification, 0); err != nil {
+		return fmt.Errorf("setVerification: %w", err)
+	}
+	return nil
+}
+
+func (b *Bot) clearVerification(ctx context.Context, telegramID int64) error {
+	if err := b.sessions.Delete(ctx, verificationKey(telegramID)); err != nil {
+		return fmt.Errorf("clearVerification: %w", err)
+	}
+	return nil
+}
+
+// getBallot возвращает незаполненный бюллетень делегата. false — бюллетень не создавался или уже отправлен
+func (b *Bot) getBallot(ctx context.Context, telegramID int64) (ballotSession, bool, error) {
+	var raw json.RawMessage
+	ok, err := b.sessions.Get(ctx, ballotKey(telegramID), &raw)
+	if err != nil {
+		return ballotSession{}, false, fmt.Errorf("getBallot: %w", err)
+	}
+	if !ok {
+		return ballotSession{}, false, nil
+	}
+	// Бюллетени, созданные до появления сессий, хранились списком кандидатов и считаются устаревшими
+	if bytes.HasPrefix(bytes.TrimSpace(raw), []byte("[")) {
+		return ballotSession{}, false, nil
+	}
+	var ballot ballotSession
+	if err := json.Unmarshal(raw, &ballot); err != nil {
+		return ballotSession{}, false, fmt.Errorf("getBallot: %w", err)
+	}
+	if ballot.RankedList == nil {
+		ballot.RankedList = []int{}
+	}
+	return ballot, true, nil
+}
+
+func (b *Bot) setBallot(ctx context.Context, telegramID int64, ballot ballotSession) error {
+	if err := b.sessions.Set(ctx, ballotKey(telegramID), ballot, ballotSessionTTL); err != nil {
+		return fmt.Errorf("setBallot: %w", err)
+	}
+	return nil
+}
+
+func (b *Bot) clearBallot(ctx context.Context, telegramID int64) error {
+	if err := b.sessions.Delete(ctx, ballotKey(telegramID)); err != nil {
+		return fmt.Errorf("clearBallot: %w", err)
+	}
+	return nil
+}
+
+// setActiveVoting открывает или закрывает голосование и сохраняет флаг в базе данных
+func (b *Bot) setActiveVoting(ctx context.Context, active bool) error {
+	if err := b.voteChain.SetVotingActive(ctx, active); err != nil {
+		return fmt.Errorf("setActiveVoting: %w", err)
+	}
+	b.activeVoting.Store(active)
+	return nil
+}
+
+// RestoreState загружает переопределенные тексты и восстанавливает состояние голосования после перезапуска бота
+func (b *Bot) RestoreState(ctx context.Context) error {
+	if err := b.LoadTextOverrides(ctx); err != nil {
+		return fmt.Errorf("RestoreState: %w", err)
+	}
+	active, err := b.voteChain.VotingActive(ctx)
+	if err != nil {
+		return fmt.Errorf("RestoreState: %w", err)
+	}
+	if !active {
+		return nil
+	}
+	if err := b.SetCandidates(); err != nil {
+		return fmt.Errorf("RestoreState: %w", err)
+	}
+	b.activeVoting.Store(true)
+	log.Info("Голосование восстановлено после перезапуска")
+	return nil
+}
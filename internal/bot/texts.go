@@ -0,0 +1,142 @@
+package bot
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"html"
+
+	"github.com/lsdpls/schulze_election_telegram_bot/internal/i18n"
+	"github.com/lsdpls/schulze_election_telegram_bot/internal/models"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// LoadTextOverrides загружает тексты сообщений, переопределенные администраторами
+func (b *Bot) LoadTextOverrides(ctx context.Context) error {
+	overrides, err := b.voteChain.GetAllTextOverrides(ctx)
+	if err != nil {
+		return fmt.Errorf("LoadTextOverrides: %w", err)
+	}
+	catalog := make(map[string]map[string]string)
+	for _, override := range overrides {
+		// Тексты, ставшие несовместимыми с каталогом после обновления бота, пропускаются
+		if err := i18n.Validate(override.Language, override.Key, override.Text); err != nil {
+			log.Warnf("Переопределение текста %s (%s) пропущено: %v", override.Key, override.Language, err)
+			continue
+		}
+		if catalog[override.Language] == nil {
+			catalog[override.Language] = make(map[string]string)
+		}
+		catalog[override.Language][override.Key] = override.Text
+	}
+	i18n.SetOverrides(catalog)
+	return nil
+}
+
+// Обработчик команды /text
+func (b *Bot) handleShowText(ctx context.Context, message *tgbotapi.Message) {
+	chatID := message.Chat.ID
+	key := commandArgsFromContext(ctx).text("key")
+	overrides, err := b.voteChain.GetAllTextOverrides(ctx)
+	if err != nil {
+		log.Errorf("%d Ошибка получения текстов: %v", chatID, err)
+		b.SendMessage(chatID, tr(ctx, "error.generic"))
+		return
+	}
+
+	var text string
+	for _, lang := range i18n.Languages {
+		base, ok := i18n.Base(lang, key)
+		if !ok {
+			continue
+		}
+		text += fmt.Sprintf("<b>%s</b>\n<code>%s</code>\n", lang, html.EscapeString(base))
+		for _, override := range overrides {
+			if override.Language == lang && override.Key == key {
+				text += tr(ctx, "texts.override", html.EscapeString(override.Text)) + "\n"
+			}
+		}
+		text += "\n"
+	}
+	if text == "" {
+		b.SendMessage(chatID, tr(ctx, "texts.unknown_key", html.EscapeString(key)))
+		return
+	}
+	b.SendMessage(chatID, text)
+}
+
+// Обработчик команды /texts
+func (b *Bot) handleTexts(ctx context.Context, message *tgbotapi.Message) {
+	chatID := message.Chat.ID
+	overrides, err := b.voteChain.GetAllTextOverrides(ctx)
+	if err != nil {
+		log.Errorf("%d Ошибка получения текстов: %v", chatID, err)
+		b.SendMessage(chatID, tr(ctx, "error.generic"))
+		return
+	}
+	if len(overrides) == 0 {
+		b.SendMessage(chatID, tr(ctx, "texts.none"))
+		return
+	}
+	text := tr(ctx, "texts.header")
+	for _, override := range overrides {
+		text += fmt.Sprintf("\n• %s (%s): %s", override.Key, override.Language, html.EscapeString(override.Text))
+	}
+	b.SendMessage(chatID, text)
+}
+
+// Обработчик команды /set_text
+func (b *Bot) handleSetText(ctx context.Context, message *tgbotapi.Message) {
+	chatID := message.Chat.ID
+	args := commandArgsFromContext(ctx)
+	override := models.TextOverride{
+		Language: args.text("lang"),
+		Key:      args.text("key"),
+		Text:     args.text("text"),
+	}
+	if err := i18n.Validate(override.Language, override.Key, override.Text); err != nil {
+		log.Warnf("%d Текст не сохранен: %v", chatID, err)
+		b.SendMessage(chatID, tr(ctx, "texts.invalid", html.EscapeString(err.Error())))
+		return
+	}
+	if message.From != nil {
+		override.UpdatedBy = sql.NullInt64{Int64: message.From.ID, Valid: true}
+	}
+	if err := b.voteChain.SetTextOverride(ctx, override); err != nil {
+		log.Errorf("%d Ошибка сохранения текста: %v", chatID, err)
+		b.SendMessage(chatID, tr(ctx, "error.generic"))
+		return
+	}
+	if err := b.LoadTextOverrides(ctx); err != nil {
+		log.Errorf("%d Ошибка загрузки текстов: %v", chatID, err)
+		b.SendMessage(chatID, tr(ctx, "error.generic"))
+		return
+	}
+	log.Warnf("%d Текст %s (%s) изменен", chatID, override.Key, override.Language)
+	b.SendMessage(chatID, tr(ctx, "texts.saved", override.Key, override.Language))
+}
+
+// Обработчик команды /reset_text
+func (b *Bot) handleResetText(ctx context.Context, message *tgbotapi.Message) {
+	chatID := message.Chat.ID
+	args := commandArgsFromContext(ctx)
+	lang, key := args.text("lang"), args.text("key")
+	deleted, err := b.voteChain.DeleteTextOverride(ctx, lang, key)
+	if err != nil {
+		log.Errorf("%d Ошибка удаления текста: %v", chatID, err)
+		b.SendMessage(chatID, tr(ctx, "error.generic"))
+		return
+	}
+	if !deleted {
+		b.SendMessage(chatID, tr(ctx, "texts.not_overridden", html.EscapeString(key), html.EscapeString(lang)))
+		return
+	}
+	if err := b.LoadTextOverrides(ctx); err != nil {
+		log.Errorf("%d Ошибка загрузки текстов: %v", chatID, err)
+		b.SendMessage(chatID, tr(ctx, "error.generic"))
+		return
+	}
+	log.Warnf("%d Текст %s (%s) возвращен к исходному", chatID, key, lang)
+	b.SendMessage(chatID, tr(ctx, "texts.reset", html.EscapeString(key), html.EscapeString(lang)))
+}
@@ -0,0 +1,121 @@
+package bot
+
+import (
+	"context"
+	"time"
+
+	"github.com/lsdpls/schulze_election_telegram_bot/internal/config"
+	"github.com/lsdpls/schulze_election_telegram_bot/internal/ratelimit"
+)
+
+// rateAction — вид действия пользователя с собственным ограничением частоты
+type rateAction string
+
+const (
+	rateCommands  rateAction = "commands"  // Команды
+	rateCallbacks rateAction = "callbacks" // Нажатия inline-кнопок
+	rateEmails    rateAction = "emails"    // Запросы кода на почту
+	rateCodes     rateAction = "codes"     // Попытки ввода кода
+)
+
+const (
+	// Предупреждение о превышении частоты отправляется не чаще одного раза в throttleNoticeInterval,
+	// чтобы не отвечать на каждое сообщение флуда
+	throttleNoticeInterval = time.Minute
+	// Пользователь считается флудящим, если сверх лимитов отклонено больше floodBurst запросов
+	// (счетчик восстанавливается на один каждые floodInterval)
+	floodBurst    = 30
+	floodInterval = 10 * time.Second
+	// Сообщение о флуде одного пользователя в чат администраторов — не чаще раза в floodReportInterval
+	floodReportInterval = time.Hour
+)
+
+// throttle ограничивает частоту действий пользователей по Telegram ID отдельно для каждого вида действия
+type throttle struct {
+	limits     map[rateAction]*ratelimit.Limiter
+	notices    *ratelimit.Limiter // Предупреждения пользователю об ограничении
+	violations *ratelimit.Limiter // Отклоненные запросы: исчерпание означает флуд
+	reports    *ratelimit.Limiter // Сообщения о флуде в чат администраторов
+}
+
+// throttleResult — решение по одному действию
+type throttleResult struct {
+	allowed bool
+	wait    time.Duration // Через сколько действие станет доступно
+	notify  bool          // Нужно предупредить пользователя
+	report  bool          // Нужно сообщить администраторам о флуде
+}
+
+// newThrottle создает ограничения по значениям из конфигурации
+func newThrottle() *throttle {
+	limits := map[rateAction]config.RateLimit{
+		rateCommands:  config.RateLimitCommands,
+		rateCallbacks: config.RateLimitCallbacks,
+		rateEmails:    config.RateLimitEmails,
+		rateCodes:     config.RateLimitCodes,
+	}
+	t := &throttle{
+		limits:     make(map[rateAction]*ratelimit.Limiter, len(limits)),
+		notices:    ratelimit.New(throttleNoticeInterval, 1),
+		violations: ratelimit.New(floodInterval, floodBurst),
+		reports:    ratelimit.New(floodReportInterval, 1),
+	}
+	for action, limit := range limits {
+		t.limits[action] = ratelimit.New(limit.Interval, limit.Burst)
+	}
+	return t
+}
+
+// check расходует токен действия пользователя и решает, что делать с отклоненным запросом
+func (t *throttle) check(action rateAction, telegramID int64) throttleResult {
+	limiter, ok := t.limits[action]
+	if !ok || limiter.Allow(telegramID) {
+		return throttleResult{allowed: true}
+	}
+	result := throttleResult{wait: limiter.RetryAfter(telegramID)}
+	result.notify = t.notices.Allow(telegramID)
+	if !t.violations.Allow(telegramID) {
+		result.report = t.reports.Allow(telegramID)
+	}
+	return result
+}
+
+// throttled проверяет частоту действия пользователя. Для отклоненного действия возвращает true и текст
+// предупреждения (пустой, если пользователя уже недавно предупреждали)
+func (b *Bot) throttled(ctx context.Context, action rateAction, telegramID int64) (string, bool) {
+	result := b.throttle.check(action, telegramID)
+	if result.allowed {
+		return "", false
+	}
+	log.Debugf("%d Превышена частота действий (%s), повтор через %s", telegramID, action, result.wait.Round(time.Second))
+	if result.report {
+		b.reportFlood(ctx, action, telegramID)
+	}
+	if !result.notify {
+		return "", true
+	}
+	return tr(ctx, "ratelimit."+string(action), waitText(ctx, result.wait)), true
+}
+
+// reportFlood сообщает в чат администраторов о пользователе, который продолжает превышать ограничения
+func (b *Bot) reportFlood(ctx context.Context, action rateAction, telegramID int64) {
+	log.Warnf("%d Пользователь продолжает превышать ограничения частоты (%s)", telegramID, action)
+	if config.AdminChatID == 0 {
+		return
+	}
+	ctx = withLanguage(ctx, b.chatLanguage(ctx, config.AdminChatID))
+	text := tr(ctx, "ratelimit.abuse", telegramID, telegramID, tr(ctx, "ratelimit.action."+string(action)))
+	if err := b.SendMessage(config.AdminChatID, text); err != nil {
+		log.Errorf("%d Ошибка отправки сообщения о флуде в чат администраторов: %v", telegramID, err)
+	}
+}
+
+// waitText возвращает время ожидания словами: секунды до минуты, далее минуты с округлением вверх
+func waitText(ctx context.Context, wait time.Duration) string {
+	seconds := max(int((wait+time.Second-1)/time.Second), 1)
+	if seconds < 60 {
+		return trN(ctx, "ratelimit.seconds", seconds, seconds)
+	}
+	minutes := (seconds + 59) / 60
+	return trN(ctx, "ratelimit.minutes", minutes, minutes)
+}
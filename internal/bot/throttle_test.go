@@ -0,0 +1,58 @@
+package bot
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/lsdpls/schulze_election_telegram_bot/internal/ratelimit"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestThrottleCheck(t *testing.T) {
+	t.Parallel()
+
+	th := &throttle{
+		limits:     map[rateAction]*ratelimit.Limiter{rateCodes: ratelimit.New(time.Hour, 2)},
+		notices:    ratelimit.New(time.Hour, 1),
+		violations: ratelimit.New(time.Hour, 2),
+		reports:    ratelimit.New(time.Hour, 1),
+	}
+
+	assert.True(t, th.check(rateCodes, 1).allowed)
+	assert.True(t, th.check(rateCodes, 1).allowed)
+
+	// Первый отказ — с предупреждением, следующие — молча
+	result := th.check(rateCodes, 1)
+	assert.False(t, result.allowed)
+	assert.True(t, result.notify)
+	assert.False(t, result.report)
+	assert.Greater(t, result.wait, 59*time.Minute)
+
+	result = th.check(rateCodes, 1)
+	assert.False(t, result.notify)
+	assert.False(t, result.report)
+
+	// Исчерпан счетчик отказов — о флуде сообщается один раз
+	assert.True(t, th.check(rateCodes, 1).report)
+	assert.False(t, th.check(rateCodes, 1).report)
+
+	// Ограничения других пользователей и действий без лимита не затронуты
+	assert.True(t, th.check(rateCodes, 2).allowed)
+	assert.True(t, th.check(rateCommands, 1).allowed)
+}
+
+func TestWaitText(t *testing.T) {
+	t.Parallel()
+
+	ctx := withLanguage(context.Background(), "ru")
+	assert.Equal(t, "1 секунду", waitText(ctx, 0))
+	assert.Equal(t, "3 секунды", waitText(ctx, 2100*time.Millisecond))
+	assert.Equal(t, "59 секунд", waitText(ctx, 59*time.Second))
+	assert.Equal(t, "1 минуту", waitText(ctx, time.Minute))
+	assert.Equal(t, "12 минут", waitText(ctx, 11*time.Minute+time.Second))
+
+	ctx = withLanguage(context.Background(), "en")
+	assert.Equal(t, "5 seconds", waitText(ctx, 5*time.Second))
+}
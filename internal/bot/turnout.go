@@ -0,0 +1,222 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/lsdpls/schulze_election_telegram_bot/internal/config"
+	"github.com/lsdpls/schulze_election_telegram_bot/internal/models"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// Ключ закрепленного сообщения с явкой в чате администраторов (хранится бессрочно)
+const turnoutSessionKey = "turnout:message"
+
+// turnoutMessage — закрепленное сообщение с явкой, которое обновляет бот
+type turnoutMessage struct {
+	ChatID    int64 `json:"chat_id"`
+	MessageID int   `json:"message_id"`
+}
+
+// turnoutCount — число делегатов, зарегистрированных и проголосовавших
+type turnoutCount struct {
+	Total      int
+	Registered int
+	Voted      int
+}
+
+// turnoutYear — явка делегатов одного года поступления
+type turnoutYear struct {
+	Year string // Год поступления; пустая строка — группа не соответствует формату
+	turnoutCount
+}
+
+// turnoutStats — явка по всем делегатам и по годам поступления
+type turnoutStats struct {
+	turnoutCount
+	Years []turnoutYear // По возрастанию года
+}
+
+// calculateTurnout считает явку. Учитываются только отметки о регистрации и голосовании, без содержимого бюллетеней
+func calculateTurnout(delegates []models.Delegate) turnoutStats {
+	var stats turnoutStats
+	years := make(map[string]*turnoutCount)
+	for _, delegate := range delegates {
+		year := groupYear(delegate.Group)
+		if years[year] == nil {
+			years[year] = &turnoutCount{}
+		}
+		for _, count := range []*turnoutCount{&stats.turnoutCount, years[year]} {
+			count.Total++
+			if delegate.TelegramID.Valid {
+				count.Registered++
+			}
+			if delegate.HasVoted {
+				count.Voted++
+			}
+		}
+	}
+	for year, count := range years {
+		stats.Years = append(stats.Years, turnoutYear{Year: year, turnoutCount: *count})
+	}
+	// Группы без года — в конце списка
+	sort.Slice(stats.Years, func(i, j int) bool {
+		if (stats.Years[i].Year == "") != (stats.Years[j].Year == "") {
+			return stats.Years[j].Year == ""
+		}
+		return stats.Years[i].Year < stats.Years[j].Year
+	})
+	return stats
+}
+
+// groupYear возвращает год поступления по префиксу группы: 21.Б01-пу — 2021
+func groupYear(group string) string {
+	prefix, _, ok := strings.Cut(group, ".")
+	if !ok || len(prefix) != 2 || prefix[0] < '0' || prefix[0] > '9' || prefix[1] < '0' || prefix[1] > '9' {
+		return ""
+	}
+	return "20" + prefix
+}
+
+// percent возвращает долю part от total в процентах
+func percent(part, total int) int {
+	if total == 0 {
+		return 0
+	}
+	return part * 100 / total
+}
+
+// turnoutText формирует сообщение с явкой (HTML)
+func turnoutText(ctx context.Context, stats turnoutStats) string {
+	text := "<b>" + tr(ctx, "turnout.header") + "</b>\n" +
+		tr(ctx, "turnout.registered", stats.Registered, stats.Total, percent(stats.Registered, stats.Total)) + "\n" +
+		tr(ctx, "turnout.voted", stats.Voted, stats.Registered, percent(stats.Voted, stats.Registered)) + "\n"
+	if len(stats.Years) > 0 {
+		text += "\n" + tr(ctx, "turnout.by_year") + "\n"
+	}
+	for _, year := range stats.Years {
+		label := year.Year
+		if label == "" {
+			label = tr(ctx, "turnout.unknown_year")
+		}
+		text += tr(ctx, "turnout.year", label, year.Registered, year.Total, year.Voted,
+			percent(year.Voted, year.Registered)) + "\n"
+	}
+	return text
+}
+
+// Обработчик команды /turnout
+func (b *Bot) handleTurnout(ctx context.Context, message *tgbotapi.Message) {
+	chatID := message.Chat.ID
+	delegates, err := b.voteChain.GetAllDelegates(ctx)
+	if err != nil {
+		log.Errorf("%d Ошибка при получении списка делегатов: %v", chatID, err)
+		return
+	}
+	b.SendMessage(chatID, turnoutText(ctx, calculateTurnout(delegates)))
+}
+
+// RunTurnoutDashboard обновляет закрепленное сообщение с явкой в чате администраторов до отмены контекста
+func (b *Bot) RunTurnoutDashboard(ctx context.Context, interval time.Duration) {
+	if config.AdminChatID == 0 {
+		return
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if err := b.refreshTurnoutMessage(ctx); err != nil {
+			log.Errorf("Ошибка обновления явки: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// refreshTurnoutMessage публикует и закрепляет сообщение с явкой при открытом голосовании и обновляет его.
+// После закрытия голосования сообщение обновляется в последний раз и остается закрепленным
+func (b *Bot) refreshTurnoutMessage(ctx context.Context) error {
+	isActive := b.activeVoting.Load()
+
+	var pinned turnoutMessage
+	ok, err := b.sessions.Get(ctx, turnoutSessionKey, &pinned)
+	if err != nil {
+		return fmt.Errorf("refreshTurnoutMessage: %w", err)
+	}
+	if !ok && !isActive {
+		return nil
+	}
+
+	delegates, err := b.voteChain.GetAllDelegates(ctx)
+	if err != nil {
+		return fmt.Errorf("refreshTurnoutMessage: %w", err)
+	}
+	ctx = withLanguage(ctx, b.chatLanguage(ctx, config.AdminChatID))
+	text := turnoutText(ctx, calculateTurnout(delegates))
+	if !isActive {
+		text += "\n" + tr(ctx, "turnout.finished")
+	}
+
+	// Сообщение не меняется, пока не изменится явка
+	b.turnoutMu.Lock()
+	defer b.turnoutMu.Unlock()
+	if ok && text == b.turnoutText {
+		return nil
+	}
+	body := text
+	text += "\n" + tr(ctx, "turnout.updated", formatScheduleTime(time.Now(), config.ElectionTimezone))
+
+	if !ok {
+		msg := tgbotapi.NewMessage(config.AdminChatID, text)
+		msg.ParseMode = "HTML"
+		sent, err := b.botAPI.Send(msg)
+		if err != nil {
+			return fmt.Errorf("refreshTurnoutMessage: %w", err)
+		}
+		pinned = turnoutMessage{ChatID: sent.Chat.ID, MessageID: sent.MessageID}
+		if err := b.sessions.Set(ctx, turnoutSessionKey, pinned, 0); err != nil {
+			return fmt.Errorf("refreshTurnoutMessage: %w", err)
+		}
+		pin := tgbotapi.PinChatMessageConfig{ChatID: pinned.ChatID, MessageID: pinned.MessageID, DisableNotification: true}
+		if _, err := b.botAPI.Request(pin); err != nil {
+			log.Warnf("Не удалось закрепить сообщение с явкой: %v", err)
+		}
+		log.Info("Сообщение с явкой опубликовано в чате администраторов")
+	} else {
+		edit := tgbotapi.NewEditMessageText(pinned.ChatID, pinned.MessageID, text)
+		edit.ParseMode = "HTML"
+		if _, err := b.botAPI.Send(edit); err != nil && !strings.Contains(err.Error(), "message is not modified") {
+			// Сообщение удалено из чата: при открытом голосовании будет опубликовано новое.
+			// При других ошибках сообщение остается прежним и обновится при следующем изменении явки
+			if isMessageNotFound(err) {
+				if err := b.sessions.Delete(ctx, turnoutSessionKey); err != nil {
+					log.Errorf("Ошибка удаления сообщения с явкой: %v", err)
+				}
+			}
+			return fmt.Errorf("refreshTurnoutMessage: %w", err)
+		}
+	}
+	b.turnoutText = body
+
+	if !isActive {
+		if err := b.sessions.Delete(ctx, turnoutSessionKey); err != nil {
+			return fmt.Errorf("refreshTurnoutMessage: %w", err)
+		}
+		b.turnoutText = ""
+		log.Info("Итоговая явка опубликована в чате администраторов")
+	}
+	return nil
+}
+
+// isMessageNotFound сообщает, что редактируемого сообщения больше нет в чате
+func isMessageNotFound(err error) bool {
+	var tgErr *tgbotapi.Error
+	return errors.As(err, &tgErr) && strings.Contains(tgErr.Message, "message to edit not found")
+}
@@ -0,0 +1,47 @@
+package bot
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/lsdpls/schulze_election_telegram_bot/internal/models"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCalculateTurnout(t *testing.T) {
+	t.Parallel()
+
+	registered := sql.NullInt64{Int64: 1, Valid: true}
+	delegates := []models.Delegate{
+		{DelegateID: 100001, Group: "22.Б01-пу", TelegramID: registered, HasVoted: true},
+		{DelegateID: 100002, Group: "21.Б01-пу", TelegramID: registered, HasVoted: true},
+		{DelegateID: 100003, Group: "21.М02-пу", TelegramID: registered},
+		{DelegateID: 100004, Group: "21.Б03-пу"},
+		{DelegateID: 100005, Group: "группа"},
+	}
+
+	stats := calculateTurnout(delegates)
+	assert.Equal(t, turnoutCount{Total: 5, Registered: 3, Voted: 2}, stats.turnoutCount)
+	assert.Equal(t, []turnoutYear{
+		{Year: "2021", turnoutCount: turnoutCount{Total: 3, Registered: 2, Voted: 1}},
+		{Year: "2022", turnoutCount: turnoutCount{Total: 1, Registered: 1, Voted: 1}},
+		{Year: "", turnoutCount: turnoutCount{Total: 1}},
+	}, stats.Years)
+
+	assert.Equal(t, 0, percent(1, 0))
+	assert.Equal(t, 66, percent(2, 3))
+}
+
+func TestIsMessageNotFound(t *testing.T) {
+	t.Parallel()
+
+	notFound := &tgbotapi.Error{Code: 400, Message: "Bad Request: message to edit not found"}
+	assert.True(t, isMessageNotFound(notFound))
+	assert.True(t, isMessageNotFound(fmt.Errorf("refreshTurnoutMessage: %w", notFound)))
+	assert.False(t, isMessageNotFound(&tgbotapi.Error{Code: 429, Message: "Too Many Requests: retry after 5"}))
+	assert.False(t, isMessageNotFound(errors.New("connection reset by peer")))
+}
@@ -0,0 +1,41 @@
+package bot
+
+import "sync"
+
+// userLocks — блокировки по пользователям: действия одного пользователя выполняются по очереди,
+// разных пользователей — не мешают друг другу. Нулевое значение готово к использованию
+type userLocks struct {
+	mu    sync.Mutex
+	locks map[int64]*userLock
+}
+
+type userLock struct {
+	sync.Mutex
+	refs int // Сколько вызовов держат или ждут блокировку; при нуле блокировка удаляется
+}
+
+// lock захватывает блокировку пользователя и возвращает функцию ее освобождения
+func (l *userLocks) lock(telegramID int64) (unlock func()) {
+	l.mu.Lock()
+	if l.locks == nil {
+		l.locks = make(map[int64]*userLock)
+	}
+	lock, ok := l.locks[telegramID]
+	if !ok {
+		lock = &userLock{}
+		l.locks[telegramID] = lock
+	}
+	lock.refs++
+	l.mu.Unlock()
+
+	lock.Lock()
+	return func() {
+		lock.Unlock()
+		l.mu.Lock()
+		lock.refs--
+		if lock.refs == 0 {
+			delete(l.locks, telegramID)
+		}
+		l.mu.Unlock()
+	}
+}
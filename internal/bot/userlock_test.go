@@ -0,0 +1,37 @@
+package bot
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUserLocks(t *testing.T) {
+	t.Parallel()
+
+	var locks userLocks
+	counters := make(map[int64]int)
+	var countersMu sync.Mutex
+	var wg sync.WaitGroup
+	for i := 0; i < 100; i++ {
+		for _, telegramID := range []int64{1, 2} {
+			wg.Add(1)
+			go func(telegramID int64) {
+				defer wg.Done()
+				unlock := locks.lock(telegramID)
+				defer unlock()
+				// Чтение и запись под блокировкой пользователя не теряют обновлений
+				countersMu.Lock()
+				value := counters[telegramID]
+				countersMu.Unlock()
+				countersMu.Lock()
+				counters[telegramID] = value + 1
+				countersMu.Unlock()
+			}(telegramID)
+		}
+	}
+	wg.Wait()
+	assert.Equal(t, map[int64]int{1: 100, 2: 100}, counters)
+	assert.Empty(t, locks.locks, "released locks are removed")
+}
@@ -0,0 +1,104 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"html"
+
+	"github.com/lsdpls/schulze_election_telegram_bot/internal/config"
+	"github.com/lsdpls/schulze_election_telegram_bot/internal/models"
+	"github.com/lsdpls/schulze_election_telegram_bot/internal/utils"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// Обработчик команды /verify: показывает записанный бюллетень и токен голоса и сверяет бюллетень
+// с последним, который подтвердил бот
+func (b *Bot) handleVerify(ctx context.Context, message *tgbotapi.Message) {
+	telegramID := message.Chat.ID
+	voter, err := b.voteChain.ResolveVoter(ctx, telegramID)
+	if err != nil {
+		log.Errorf("%d Ошибка при проверке регистрации: %v", telegramID, err)
+		b.SendMessage(telegramID, tr(ctx, "vote.registration_check_failed"))
+		return
+	}
+	if voter == nil {
+		b.SendMessage(telegramID, tr(ctx, "vote.not_registered"))
+		return
+	}
+	vote, receipt, err := b.voteChain.GetVoteWithReceipt(ctx, voter.DelegateID)
+	if err != nil {
+		log.Errorf("%d Ошибка при получении бюллетеня: %v", telegramID, err)
+		b.SendMessage(telegramID, tr(ctx, "error.generic"))
+		return
+	}
+	if receipt.Check(vote) == models.ReceiptMismatch {
+		log.Warnf("%d Записанный голос st%06d отличается от подтвержденного бюллетеня", telegramID, voter.DelegateID)
+	}
+
+	// Бюллетень показывается с именами всех кандидатов, включая снятых после голосования
+	candidates, err := b.voteChain.GetAllCandidates(ctx)
+	if err != nil {
+		log.Errorf("%d Ошибка при получении списка кандидатов: %v", telegramID, err)
+		b.SendMessage(telegramID, tr(ctx, "error.generic"))
+		return
+	}
+	names := make(map[int]string, len(candidates))
+	for _, candidate := range candidates {
+		names[candidate.CandidateID] = candidate.Name
+	}
+	b.SendMessage(telegramID, ballotCheckText(ctx, *voter, vote, receipt, utils.GenerateVoteToken(telegramID), names))
+}
+
+// ballotCheckText описывает записанный бюллетень голосующего и результат сверки с подтвержденным.
+// Бюллетень, поданный с другого аккаунта (делегатом или заместителем), не показывается; если подтверждение
+// получил другой аккаунт, показывается его токен
+func ballotCheckText(ctx context.Context, voter models.Voter, vote *models.Vote, receipt *models.VoteReceipt,
+	token string, names map[int]string) string {
+	status := receipt.Check(vote)
+	if vote == nil {
+		if status == models.ReceiptMismatch {
+			return tr(ctx, "verify.missing", formatScheduleTime(receipt.ConfirmedAt, config.ElectionTimezone))
+		}
+		if voter.Delegated() {
+			return tr(ctx, "verify.no_vote_delegated", voter.SubstituteID)
+		}
+		return tr(ctx, "verify.no_vote")
+	}
+	if vote.ProxyID != voter.ProxyID {
+		if vote.ProxyID.Valid {
+			return tr(ctx, "verify.cast_by_substitute", vote.DelegateID)
+		}
+		return tr(ctx, "verify.cast_by_delegate", vote.DelegateID)
+	}
+
+	text := tr(ctx, "verify.ballot", formatScheduleTime(vote.CreatedAt, config.ElectionTimezone)) + "\n\n" +
+		rankingText(vote.CandidateRankings, names) + "\n" + tr(ctx, "verify.token", token) + "\n\n"
+	switch status {
+	case models.ReceiptMatches:
+		text += tr(ctx, "verify.matches", formatScheduleTime(receipt.ConfirmedAt, config.ElectionTimezone))
+	case models.ReceiptMismatch:
+		text += tr(ctx, "verify.mismatch", formatScheduleTime(receipt.ConfirmedAt, config.ElectionTimezone)) +
+			"\n\n" + rankingText(receipt.CandidateRankings, names)
+	default:
+		return text + tr(ctx, "verify.unconfirmed")
+	}
+	// Подтверждение получил другой аккаунт голосующего, например до сброса регистрации
+	if confirmedBy := utils.GenerateVoteToken(receipt.TelegramID); receipt.TelegramID != 0 && confirmedBy != token {
+		text += "\n\n" + tr(ctx, "verify.confirmed_by_other", confirmedBy)
+	}
+	return text
+}
+
+// rankingText перечисляет кандидатов бюллетеня по местам; неизвестные кандидаты показываются по ID
+func rankingText(ranking []int, names map[int]string) string {
+	var text string
+	for i, candidateID := range ranking {
+		name, ok := names[candidateID]
+		if !ok {
+			name = fmt.Sprintf("%06d", candidateID)
+		}
+		text += fmt.Sprintf("%d. %s\n", i+1, html.EscapeString(name))
+	}
+	return text
+}
@@ -0,0 +1,51 @@
+package bot
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/lsdpls/schulze_election_telegram_bot/internal/models"
+	"github.com/lsdpls/schulze_election_telegram_bot/internal/utils"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBallotCheckText(t *testing.T) {
+	t.Parallel()
+
+	ctx := withLanguage(context.Background(), "ru")
+	names := map[int]string{111111: "Иванов", 222222: "Петров"}
+	at := time.Date(2026, 10, 19, 12, 30, 0, 0, time.UTC)
+	voter := models.Voter{DelegateID: 123456}
+	vote := &models.Vote{DelegateID: 123456, CandidateRankings: []int{222222, 111111, 333333}, CreatedAt: at}
+	receipt := &models.VoteReceipt{DelegateID: 123456, CandidateRankings: []int{222222, 111111, 333333}, ConfirmedAt: at}
+
+	text := ballotCheckText(ctx, voter, vote, receipt, "AAAA-BBBB-CCCC-DDDD", names)
+	assert.Contains(t, text, "1. Петров\n2. Иванов\n3. 333333\n")
+	assert.Contains(t, text, "<code>AAAA-BBBB-CCCC-DDDD</code>")
+	assert.Contains(t, text, "✅")
+	assert.NotContains(t, text, "другому аккаунту")
+
+	// Подтверждение получил прежний аккаунт делегата
+	receipt.TelegramID = 42
+	text = ballotCheckText(ctx, voter, vote, receipt, "AAAA-BBBB-CCCC-DDDD", names)
+	assert.Contains(t, text, "другому аккаунту")
+	assert.Contains(t, text, utils.GenerateVoteToken(42))
+	receipt.TelegramID = 0
+
+	receipt.CandidateRankings = []int{111111, 222222, 333333}
+	text = ballotCheckText(ctx, voter, vote, receipt, "AAAA-BBBB-CCCC-DDDD", names)
+	assert.Contains(t, text, "⚠️")
+	assert.Contains(t, text, "1. Иванов\n2. Петров\n")
+
+	assert.Contains(t, ballotCheckText(ctx, voter, nil, receipt, "", names), "голос не записан")
+	assert.Contains(t, ballotCheckText(ctx, voter, vote, nil, "", names), "ℹ️")
+
+	// Голос, поданный заместителем, делегату не показывается
+	vote.ProxyID = sql.NullInt64{Int64: 1, Valid: true}
+	text = ballotCheckText(ctx, voter, vote, receipt, "", names)
+	assert.Contains(t, text, "заместителем")
+	assert.NotContains(t, text, "Петров")
+}
@@ -0,0 +1,211 @@
+package bot
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/lsdpls/schulze_election_telegram_bot/internal/config"
+	"github.com/lsdpls/schulze_election_telegram_bot/internal/i18n"
+	"github.com/lsdpls/schulze_election_telegram_bot/internal/utils"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// webAppInitDataTTL — срок действия initData для отправки бюллетеня, после которого Mini App нужно открыть заново.
+// Короткий срок ограничивает повторное использование перехваченной подписи
+const webAppInitDataTTL = time.Hour
+
+//go:embed webapp/index.html
+var webAppPage []byte
+
+type webAppCandidate struct {
+	CandidateID int    `json:"candidate_id"`
+	Name        string `json:"name"`
+	Course      string `json:"course"`
+	Description string `json:"description"`
+}
+
+type webAppCandidatesResponse struct {
+	Active     bool              `json:"active"`
+	Candidates []webAppCandidate `json:"candidates"`
+}
+
+type webAppVoteRequest struct {
+	InitData string `json:"init_data"` // Telegram.WebApp.initData
+	Ranking  []int  `json:"ranking"`   // ID кандидатов от наиболее к наименее предпочтительному
+}
+
+type webAppVoteResponse struct {
+	VoteToken string `json:"vote_token,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+// HandleWebAppPage отдает страницу Mini App с бюллетенем
+func (b *Bot) HandleWebAppPage(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Write(webAppPage)
+}
+
+// HandleWebAppCandidates отдает список кандидатов текущего голосования с описаниями
+func (b *Bot) HandleWebAppCandidates(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	candidates := b.currentCandidates()
+	response := webAppCandidatesResponse{
+		Active:     b.activeVoting.Load(),
+		Candidates: make([]webAppCandidate, 0, len(candidates.sortedIDs)),
+	}
+	for _, candidateID := range candidates.sortedIDs {
+		candidate := candidates.byID[candidateID]
+		response.Candidates = append(response.Candidates, webAppCandidate{
+			CandidateID: candidate.CandidateID,
+			Name:        candidate.Name,
+			Course:      candidate.Course,
+			Description: candidate.Description,
+		})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		log.Errorf("Ошибка при отправке списка кандидатов Mini App: %v", err)
+	}
+}
+
+// HandleWebAppVote принимает бюллетень из Mini App. Пользователь определяется по подписанным initData
+func (b *Bot) HandleWebAppVote(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	// До проверки initData язык пользователя неизвестен, поэтому используется язык браузера
+	ctx := withLanguage(r.Context(), i18n.Match(r.Header.Get("Accept-Language")))
+
+	var request webAppVoteRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1<<16)).Decode(&request); err != nil {
+		writeWebAppResponse(w, http.StatusBadRequest, webAppVoteResponse{Error: tr(ctx, "webapp.bad_request")})
+		return
+	}
+
+	telegramID, err := utils.ValidateWebAppInitData(request.InitData, config.TelegramAPIToken, webAppInitDataTTL)
+	if err != nil {
+		log.Warnf("Отклонен бюллетень Mini App: %v", err)
+		message := tr(ctx, "webapp.unauthorized")
+		if errors.Is(err, utils.ErrInitDataExpired) {
+			message = tr(ctx, "webapp.expired")
+		}
+		writeWebAppResponse(w, http.StatusUnauthorized, webAppVoteResponse{Error: message})
+		return
+	}
+	if lang := b.savedLanguage(ctx, telegramID); lang != "" {
+		ctx = withLanguage(ctx, lang)
+	}
+	// Отправка из Mini App ограничивается тем же лимитом пользователя, что и нажатия кнопок бюллетеня
+	if notice, limited := b.throttled(ctx, rateCallbacks, telegramID); limited {
+		if notice == "" {
+			notice = tr(ctx, "webapp.rate_limited")
+		}
+		writeWebAppResponse(w, http.StatusTooManyRequests, webAppVoteResponse{Error: notice})
+		return
+	}
+
+	isActive := b.activeVoting.Load()
+	candidates := b.currentCandidates()
+	valid := len(request.Ranking) == len(candidates.byID) && isUniqueCandidates(request.Ranking)
+	for _, candidateID := range request.Ranking {
+		if _, ok := candidates.byID[candidateID]; !ok {
+			valid = false
+		}
+	}
+
+	if !isActive {
+		log.Warn(telegramID, " Попытка голосования через Mini App при закрытом голосовании")
+		writeWebAppResponse(w, http.StatusForbidden, webAppVoteResponse{Error: tr(ctx, "vote.closed")})
+		return
+	}
+	voter, err := b.voteChain.ResolveVoter(ctx, telegramID)
+	if err != nil {
+		log.Errorf("%d Ошибка при проверке регистрации делегата: %v", telegramID, err)
+		writeWebAppResponse(w, http.StatusInternalServerError, webAppVoteResponse{Error: tr(ctx, "vote.registration_check_failed")})
+		return
+	}
+	if voter == nil {
+		log.Warn(telegramID, " Незарегистрированный пользователь пытается проголосовать через Mini App")
+		writeWebAppResponse(w, http.StatusForbidden, webAppVoteResponse{Error: tr(ctx, "vote.not_registered")})
+		return
+	}
+	if voter.Delegated() {
+		log.Warn(telegramID, " Делегат с действующей доверенностью пытается проголосовать через Mini App")
+		writeWebAppResponse(w, http.StatusForbidden, webAppVoteResponse{Error: tr(ctx, "vote.proxy_active", voter.SubstituteID)})
+		return
+	}
+	if !valid {
+		log.Warn(telegramID, " Испорченный бюллетень из Mini App")
+		writeWebAppResponse(w, http.StatusBadRequest, webAppVoteResponse{Error: tr(ctx, "webapp.invalid_ranking")})
+		return
+	}
+
+	log.Debugf("%d rankedList (Mini App): %v", telegramID, request.Ranking)
+	// Запись голоса и удаление бюллетеня в чате выполняются под блокировкой пользователя, как отправка
+	// бюллетеня из чата: одновременные отправки не перемешиваются
+	unlock := b.ballotLocks.lock(telegramID)
+	if err := b.voteChain.AddVote(ctx, telegramID, request.Ranking); err != nil {
+		unlock()
+		log.Errorf("%d ошибка регистрации голоса: %v", telegramID, err)
+		writeWebAppResponse(w, http.StatusInternalServerError, webAppVoteResponse{Error: tr(ctx, "vote.save_failed")})
+		return
+	}
+	// Бюллетени в чате становятся устаревшими
+	if err := b.clearBallot(ctx, telegramID); err != nil {
+		log.Errorf("%d Ошибка удаления незаполненного бюллетеня: %v", telegramID, err)
+	}
+	unlock()
+
+	voteToken := b.sendVoteAccepted(ctx, telegramID, request.Ranking)
+	log.Info(telegramID, " Голос учтен (Mini App)")
+	writeWebAppResponse(w, http.StatusOK, webAppVoteResponse{VoteToken: voteToken})
+}
+
+func writeWebAppResponse(w http.ResponseWriter, status int, response webAppVoteResponse) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		log.Errorf("Ошибка при отправке ответа Mini App: %v", err)
+	}
+}
+
+// sendWebAppButton отправляет кнопку открытия бюллетеня в Mini App.
+// tgbotapi не поддерживает web_app кнопки, поэтому клавиатура собирается вручную
+func (b *Bot) sendWebAppButton(ctx context.Context, telegramID int64) error {
+	type webAppInfo struct {
+		URL string `json:"url"`
+	}
+	type webAppButton struct {
+		Text   string     `json:"text"`
+		WebApp webAppInfo `json:"web_app"`
+	}
+	markup := struct {
+		InlineKeyboard [][]webAppButton `json:"inline_keyboard"`
+	}{
+		InlineKeyboard: [][]webAppButton{{{Text: tr(ctx, "webapp.open"), WebApp: webAppInfo{URL: config.WebAppURL}}}},
+	}
+
+	params := tgbotapi.Params{}
+	params.AddFirstValid("chat_id", telegramID)
+	params["text"] = tr(ctx, "webapp.button_text")
+	if err := params.AddInterface("reply_markup", markup); err != nil {
+		return err
+	}
+	_, err := b.botAPI.MakeRequest("sendMessage", params)
+	return err
+}
@@ -0,0 +1,102 @@
+package bot
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/lsdpls/schulze_election_telegram_bot/internal/config"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// Заголовок, в котором Telegram передает secret_token, указанный при установке вебхука
+const webhookSecretHeader = "X-Telegram-Bot-Api-Secret-Token"
+
+// Сколько запрос вебхука ждет места в очереди обработчиков
+const webhookQueueTimeout = 10 * time.Second
+
+// Предупреждение об отклоненном запросе к вебхуку пишется не чаще раза в webhookWarningInterval
+const webhookWarningInterval = time.Minute
+
+// WebhookPath возвращает путь вебхука. Путь выводится из секрета, чтобы его нельзя было подобрать,
+// но сам секрет по пути (например, из журналов прокси) восстановить нельзя
+func WebhookPath(secret string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte("webhook path"))
+	return "/telegram/" + hex.EncodeToString(mac.Sum(nil))[:32]
+}
+
+// SetWebhook устанавливает вебхук на адрес baseURL + WebhookPath с секретом для заголовка запросов
+func (b *Bot) SetWebhook(baseURL, secret string) error {
+	params := tgbotapi.Params{}
+	params.AddNonEmpty("url", strings.TrimSuffix(baseURL, "/")+WebhookPath(secret))
+	params.AddNonEmpty("secret_token", secret)
+	if err := params.AddInterface("allowed_updates", []string{"message", "callback_query"}); err != nil {
+		return fmt.Errorf("SetWebhook: %w", err)
+	}
+	if _, err := b.botAPI.MakeRequest("setWebhook", params); err != nil {
+		return fmt.Errorf("SetWebhook: %w", err)
+	}
+	return nil
+}
+
+// validWebhookSecret сравнивает секрет из заголовка запроса с ожидаемым за постоянное время
+func validWebhookSecret(header, secret string) bool {
+	return secret != "" && subtle.ConstantTimeCompare([]byte(header), []byte(secret)) == 1
+}
+
+// HandleWebhook обрабатывает вебхуки от Telegram. Запросы без верного секрета отклоняются
+func (b *Bot) HandleWebhook(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		b.rejectWebhook(w, r, "метод "+r.Method, http.StatusMethodNotAllowed)
+		return
+	}
+	if !validWebhookSecret(r.Header.Get(webhookSecretHeader), config.WebhookSecret) {
+		b.rejectWebhook(w, r, "неверный секрет", http.StatusUnauthorized)
+		return
+	}
+
+	var update tgbotapi.Update
+	if err := json.NewDecoder(r.Body).Decode(&update); err != nil {
+		log.Errorf("Error decoding update: %v", err)
+		http.Error(w, "Error decoding update", http.StatusBadRequest)
+		return
+	}
+
+	// Telegram получает ответ сразу после постановки в очередь. Если очередь не освободилась за время запроса,
+	// ответ с ошибкой заставит Telegram повторить обновление позже
+	if update.Message != nil || update.CallbackQuery != nil {
+		ctx, cancel := context.WithTimeout(r.Context(), webhookQueueTimeout)
+		defer cancel()
+		if err := b.Dispatch(ctx, update); err != nil {
+			log.Errorf("Ошибка постановки обновления %d в очередь: %v", update.UpdateID, err)
+			http.Error(w, http.StatusText(http.StatusServiceUnavailable), http.StatusServiceUnavailable)
+			return
+		}
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// rejectWebhook отклоняет запрос к вебхуку. Каждый запрос пишется в журнал отладки, а предупреждение —
+// не чаще раза в webhookWarningInterval, чтобы подбор не засыпал чат логов
+func (b *Bot) rejectWebhook(w http.ResponseWriter, r *http.Request, reason string, status int) {
+	remoteAddr := r.RemoteAddr
+	if realIP := r.Header.Get("X-Real-IP"); realIP != "" {
+		remoteAddr = realIP
+	}
+	if b.webhookWarnings.Allow(0) {
+		log.Warnf("Отклонен запрос к вебхуку от %s: %s", remoteAddr, reason)
+	} else {
+		log.Debugf("Отклонен запрос к вебхуку от %s: %s", remoteAddr, reason)
+	}
+	http.Error(w, http.StatusText(status), status)
+}
@@ -0,0 +1,31 @@
+package bot
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWebhookPath(t *testing.T) {
+	t.Parallel()
+
+	secret := strings.Repeat("a1", 16)
+	path := WebhookPath(secret)
+	assert.Equal(t, path, WebhookPath(secret), "path is deterministic")
+	assert.NotEqual(t, path, WebhookPath(strings.Repeat("b2", 16)))
+	assert.True(t, strings.HasPrefix(path, "/telegram/"))
+	assert.Len(t, strings.TrimPrefix(path, "/telegram/"), 32)
+	assert.NotContains(t, path, secret)
+}
+
+func TestValidWebhookSecret(t *testing.T) {
+	t.Parallel()
+
+	secret := strings.Repeat("a1", 16)
+	assert.True(t, validWebhookSecret(secret, secret))
+	assert.False(t, validWebhookSecret("", secret), "missing header")
+	assert.False(t, validWebhookSecret(secret[:31], secret), "prefix")
+	assert.False(t, validWebhookSecret(secret+"x", secret), "longer")
+	assert.False(t, validWebhookSecret("", ""), "secret not configured")
+}
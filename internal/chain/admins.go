@@ -0,0 +1,73 @@
+package chain
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/lsdpls/schulze_election_telegram_bot/internal/models"
+
+	"github.com/jackc/pgx/v5"
+)
+
+func (vc *VoteChain) SetAdmin(ctx context.Context, admin models.Admin) error {
+	tx, err := vc.storage.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.Serializable})
+	if err != nil {
+		return fmt.Errorf("chain.SetAdmin: can't start transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	admin.GrantedAt = time.Now()
+	if err := vc.storage.SetAdmin(ctx, tx, admin); err != nil {
+		return fmt.Errorf("chain.SetAdmin: %w", err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("chain.SetAdmin: can't commit transaction: %w", err)
+	}
+	return nil
+}
+
+func (vc *VoteChain) GetAdmin(ctx context.Context, telegramID int64) (*models.Admin, error) {
+	tx, err := vc.storage.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.Serializable})
+	if err != nil {
+		return nil, fmt.Errorf("chain.GetAdmin: can't start transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	admin, err := vc.storage.GetAdmin(ctx, tx, telegramID)
+	if err != nil {
+		return nil, fmt.Errorf("chain.GetAdmin: %w", err)
+	}
+	return admin, nil
+}
+
+func (vc *VoteChain) GetAllAdmins(ctx context.Context) ([]models.Admin, error) {
+	tx, err := vc.storage.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.Serializable})
+	if err != nil {
+		return nil, fmt.Errorf("chain.GetAllAdmins: can't start transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	admins, err := vc.storage.GetAllAdmins(ctx, tx)
+	if err != nil {
+		return nil, fmt.Errorf("chain.GetAllAdmins: %w", err)
+	}
+	return admins, nil
+}
+
+func (vc *VoteChain) DeleteAdmin(ctx context.Context, telegramID int64) (bool, error) {
+	tx, err := vc.storage.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.Serializable})
+	if err != nil {
+		return false, fmt.Errorf("chain.DeleteAdmin: can't start transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	deleted, err := vc.storage.DeleteAdmin(ctx, tx, telegramID)
+	if err != nil {
+		return false, fmt.Errorf("chain.DeleteAdmin: %w", err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return false, fmt.Errorf("chain.DeleteAdmin: can't commit transaction: %w", err)
+	}
+	return deleted, nil
+}
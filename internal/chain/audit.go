@@ -0,0 +1,25 @@
+package chain
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/lsdpls/schulze_election_telegram_bot/internal/models"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// GetAuditLog возвращает журнал действий с регистрацией делегата
+func (vc *VoteChain) GetAuditLog(ctx context.Context, delegateID int) ([]models.AuditRecord, error) {
+	tx, err := vc.storage.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.Serializable})
+	if err != nil {
+		return nil, fmt.Errorf("chain.GetAuditLog: can't start transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	records, err := vc.storage.GetAuditLog(ctx, tx, delegateID)
+	if err != nil {
+		return nil, fmt.Errorf("chain.GetAuditLog: %w", err)
+	}
+	return records, nil
+}
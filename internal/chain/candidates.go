@@ -33,7 +33,9 @@ func (vc *VoteChain) AddCandidate(ctx context.Context, candidate models.Candidat
 	return nil
 }
 
-func (vc *VoteChain) DeleteCandidate(ctx context.Context, candidateID int) error {
+// DeleteCandidate удаляет кандидата. ballots — число бюллетеней с кандидатом, которое видел администратор
+// при подтверждении: если оно изменилось, возвращается ErrStateChanged
+func (vc *VoteChain) DeleteCandidate(ctx context.Context, candidateID, ballots int) error {
 	tx, err := vc.storage.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.Serializable})
 	if err != nil {
 		return fmt.Errorf("chain.DeleteCandidate: can't start transaction: %w", err)
@@ -47,6 +49,13 @@ func (vc *VoteChain) DeleteCandidate(ctx context.Context, candidateID int) error
 	if candidate == nil {
 		return fmt.Errorf("chain.DeleteCandidate: candidate not found")
 	}
+	votes, err := vc.storage.GetAllVotes(ctx, tx)
+	if err != nil {
+		return fmt.Errorf("chain.DeleteCandidate: %w", err)
+	}
+	if models.CountBallots(votes, candidateID) != ballots {
+		return fmt.Errorf("chain.DeleteCandidate: %w", ErrStateChanged)
+	}
 
 	if err := vc.storage.DeleteCandidate(ctx, tx, candidateID); err != nil {
 		return fmt.Errorf("chain.DeleteCandidate: %w", err)
@@ -126,3 +135,117 @@ func (vc *VoteChain) BanCandidate(ctx context.Context, candidateID int) error {
 	}
 	return nil
 }
+
+// UnbanCandidate снова допускает кандидата до выборов
+func (vc *VoteChain) UnbanCandidate(ctx context.Context, candidateID int) error {
+	tx, err := vc.storage.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.Serializable})
+	if err != nil {
+		return fmt.Errorf("chain.UnbanCandidate: can't start transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	candidate, err := vc.storage.GetCandidateByCandidateID(ctx, tx, candidateID)
+	if err != nil {
+		return fmt.Errorf("chain.UnbanCandidate: %w", err)
+	}
+	if candidate == nil {
+		return fmt.Errorf("chain.UnbanCandidate: candidate not found")
+	}
+
+	candidate.IsEligible = true
+	if err := vc.storage.UpdateCandidate(ctx, tx, *candidate); err != nil {
+		return fmt.Errorf("chain.UnbanCandidate: %w", err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("chain.UnbanCandidate: can't commit transaction: %w", err)
+	}
+	return nil
+}
+
+// UpdateCandidate изменяет ФИО, курс и описание кандидата. Допуск, фото и программа не меняются.
+// Курс нельзя менять при открытом голосовании: возвращается ErrLockedDuringVoting
+func (vc *VoteChain) UpdateCandidate(ctx context.Context, update models.Candidate) error {
+	tx, err := vc.storage.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.Serializable})
+	if err != nil {
+		return fmt.Errorf("chain.UpdateCandidate: can't start transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	candidate, err := vc.storage.GetCandidateByCandidateID(ctx, tx, update.CandidateID)
+	if err != nil {
+		return fmt.Errorf("chain.UpdateCandidate: %w", err)
+	}
+	if candidate == nil {
+		return fmt.Errorf("chain.UpdateCandidate: candidate not found")
+	}
+	if candidate.Course != update.Course {
+		active, err := vc.votingActive(ctx, tx)
+		if err != nil {
+			return fmt.Errorf("chain.UpdateCandidate: %w", err)
+		}
+		if active {
+			return fmt.Errorf("chain.UpdateCandidate: %w", ErrLockedDuringVoting)
+		}
+	}
+
+	candidate.Name, candidate.Course, candidate.Description = update.Name, update.Course, update.Description
+	if err := vc.storage.UpdateCandidate(ctx, tx, *candidate); err != nil {
+		return fmt.Errorf("chain.UpdateCandidate: %w", err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("chain.UpdateCandidate: can't commit transaction: %w", err)
+	}
+	return nil
+}
+
+// SetCandidatePhoto сохраняет file_id фото кандидата. Пустая строка удаляет фото
+func (vc *VoteChain) SetCandidatePhoto(ctx context.Context, candidateID int, photoFileID string) error {
+	tx, err := vc.storage.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.Serializable})
+	if err != nil {
+		return fmt.Errorf("chain.SetCandidatePhoto: can't start transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	candidate, err := vc.storage.GetCandidateByCandidateID(ctx, tx, candidateID)
+	if err != nil {
+		return fmt.Errorf("chain.SetCandidatePhoto: %w", err)
+	}
+	if candidate == nil {
+		return fmt.Errorf("chain.SetCandidatePhoto: candidate not found")
+	}
+
+	candidate.PhotoFileID = photoFileID
+	if err := vc.storage.UpdateCandidate(ctx, tx, *candidate); err != nil {
+		return fmt.Errorf("chain.SetCandidatePhoto: %w", err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("chain.SetCandidatePhoto: can't commit transaction: %w", err)
+	}
+	return nil
+}
+
+// SetCandidateManifesto сохраняет ссылку на программу кандидата. Пустая строка удаляет ссылку
+func (vc *VoteChain) SetCandidateManifesto(ctx context.Context, candidateID int, manifestoURL string) error {
+	tx, err := vc.storage.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.Serializable})
+	if err != nil {
+		return fmt.Errorf("chain.SetCandidateManifesto: can't start transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	candidate, err := vc.storage.GetCandidateByCandidateID(ctx, tx, candidateID)
+	if err != nil {
+		return fmt.Errorf("chain.SetCandidateManifesto: %w", err)
+	}
+	if candidate == nil {
+		return fmt.Errorf("chain.SetCandidateManifesto: candidate not found")
+	}
+
+	candidate.ManifestoURL = manifestoURL
+	if err := vc.storage.UpdateCandidate(ctx, tx, *candidate); err != nil {
+		return fmt.Errorf("chain.SetCandidateManifesto: %w", err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("chain.SetCandidateManifesto: can't commit transaction: %w", err)
+	}
+	return nil
+}
@@ -84,6 +84,14 @@ func (vc *VoteChain) VerificateDelegate(ctx context.Context, delegateID int, tel
 	if err != nil {
 		return fmt.Errorf("chain.UpdateDelegate: %w", err)
 	}
+	err = vc.storage.AddAuditRecord(ctx, tx, models.AuditRecord{
+		Action:     models.AuditVerification,
+		DelegateID: delegateID,
+		TelegramID: telegramId,
+	})
+	if err != nil {
+		return fmt.Errorf("chain.UpdateDelegate: %w", err)
+	}
 
 	if err = tx.Commit(ctx); err != nil {
 		return fmt.Errorf("chain.UpdateDelegate: can't commit transaction: %w", err)
@@ -91,7 +99,105 @@ func (vc *VoteChain) VerificateDelegate(ctx context.Context, delegateID int, tel
 	return nil
 }
 
-func (vc *VoteChain) DeleteDelegate(ctx context.Context, delegateID int) error {
+// UpdateDelegate изменяет ФИО и группу делегата. Привязка Telegram и отметка о голосовании не меняются.
+// Группу нельзя менять при открытом голосовании: возвращается ErrLockedDuringVoting
+func (vc *VoteChain) UpdateDelegate(ctx context.Context, update models.Delegate) error {
+	tx, err := vc.storage.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.Serializable})
+	if err != nil {
+		return fmt.Errorf("chain.UpdateDelegate: can't start transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	delegate, err := vc.storage.GetDelegateByDelegateID(ctx, tx, update.DelegateID)
+	if err != nil {
+		return fmt.Errorf("chain.UpdateDelegate: %w", err)
+	}
+	if delegate == nil {
+		return fmt.Errorf("chain.UpdateDelegate: delegate not found")
+	}
+	if delegate.Group != update.Group {
+		active, err := vc.votingActive(ctx, tx)
+		if err != nil {
+			return fmt.Errorf("chain.UpdateDelegate: %w", err)
+		}
+		if active {
+			return fmt.Errorf("chain.UpdateDelegate: %w", ErrLockedDuringVoting)
+		}
+	}
+
+	delegate.Name, delegate.Group = update.Name, update.Group
+	if err := vc.storage.UpdateDelegate(ctx, tx, *delegate); err != nil {
+		return fmt.Errorf("chain.UpdateDelegate: %w", err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("chain.UpdateDelegate: can't commit transaction: %w", err)
+	}
+	return nil
+}
+
+// ResetRegistration отвязывает Telegram аккаунт делегата, чтобы он мог пройти верификацию с другого аккаунта,
+// и записывает действие в журнал. Голос остается за делегатом и перейдет к новому аккаунту; discardVote удаляет
+// голос, чтобы делегат проголосовал заново. Возвращает делегата до сброса
+func (vc *VoteChain) ResetRegistration(ctx context.Context, delegateID int, discardVote bool, performedBy sql.NullInt64) (*models.Delegate, error) {
+	tx, err := vc.storage.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.Serializable})
+	if err != nil {
+		return nil, fmt.Errorf("chain.ResetRegistration: can't start transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	delegate, err := vc.storage.GetDelegateByDelegateID(ctx, tx, delegateID)
+	if err != nil {
+		return nil, fmt.Errorf("chain.ResetRegistration: %w", err)
+	}
+	if delegate == nil {
+		return nil, fmt.Errorf("chain.ResetRegistration: delegate not found")
+	}
+	previous := *delegate
+
+	details := models.AuditVoteKept
+	if discardVote && delegate.HasVoted {
+		vote, err := vc.storage.GetVoteByDelegateID(ctx, tx, delegateID)
+		if err != nil {
+			return nil, fmt.Errorf("chain.ResetRegistration: %w", err)
+		}
+		if vote != nil {
+			if err := vc.storage.DeleteVote(ctx, tx, vote.ID); err != nil {
+				return nil, fmt.Errorf("chain.ResetRegistration: %w", err)
+			}
+			if err := vc.storage.DeleteVoteReceipt(ctx, tx, delegateID); err != nil {
+				return nil, fmt.Errorf("chain.ResetRegistration: %w", err)
+			}
+		}
+		delegate.HasVoted = false
+		details = models.AuditVoteDiscarded
+	} else if !delegate.HasVoted {
+		details = models.AuditNoVote
+	}
+
+	delegate.TelegramID = sql.NullInt64{}
+	if err := vc.storage.UpdateDelegate(ctx, tx, *delegate); err != nil {
+		return nil, fmt.Errorf("chain.ResetRegistration: %w", err)
+	}
+	err = vc.storage.AddAuditRecord(ctx, tx, models.AuditRecord{
+		Action:      models.AuditResetRegistration,
+		DelegateID:  delegateID,
+		TelegramID:  previous.TelegramID,
+		PerformedBy: performedBy,
+		Details:     details,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("chain.ResetRegistration: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("chain.ResetRegistration: can't commit transaction: %w", err)
+	}
+	return &previous, nil
+}
+
+// DeleteDelegate удаляет делегата вместе с его голосом. hasVoted — наличие голоса, которое видел администратор
+// при подтверждении: если делегат успел проголосовать или голос удален, возвращается ErrStateChanged
+func (vc *VoteChain) DeleteDelegate(ctx context.Context, delegateID int, hasVoted bool) error {
 	tx, err := vc.storage.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.Serializable})
 	if err != nil {
 		return fmt.Errorf("chain.DeleteDelegate: can't start transaction: %w", err)
@@ -105,6 +211,9 @@ func (vc *VoteChain) DeleteDelegate(ctx context.Context, delegateID int) error {
 	if delegate == nil {
 		return fmt.Errorf("chain.DeleteDelegate: delegate not found")
 	}
+	if delegate.HasVoted != hasVoted {
+		return fmt.Errorf("chain.DeleteDelegate: %w", ErrStateChanged)
+	}
 
 	if err := vc.storage.DeleteDelegate(ctx, tx, delegateID); err != nil {
 		return fmt.Errorf("chain.DeleteDelegate: %w", err)
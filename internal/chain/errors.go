@@ -0,0 +1,20 @@
+package chain
+
+import "errors"
+
+// ErrStateChanged возвращается, если данные изменились после того, как администратор подтвердил действие
+var ErrStateChanged = errors.New("state changed since confirmation")
+
+// Отказы в назначении, подтверждении и отзыве доверенности
+var (
+	ErrDelegateNotFound     = errors.New("delegate not found")
+	ErrProxySelf            = errors.New("delegate can't be own substitute")
+	ErrSubstituteIsDelegate = errors.New("substitute is a delegate")
+	ErrProxyExists          = errors.New("delegate already has a substitute")
+	ErrSubstituteBusy       = errors.New("substitute already represents another delegate")
+	ErrProxyNotFound        = errors.New("active proxy not found")
+)
+
+// ErrLockedDuringVoting возвращается при попытке изменить курс кандидата или группу делегата при открытом голосовании:
+// курс определяет место, за которое голосуют за кандидата, а группа — кого представляет делегат
+var ErrLockedDuringVoting = errors.New("field is locked while voting is open")
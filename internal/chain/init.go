@@ -2,6 +2,7 @@ package chain
 
 import (
 	"context"
+	"time"
 
 	"github.com/lsdpls/schulze_election_telegram_bot/internal/models"
 
@@ -40,6 +41,9 @@ type storage interface {
 	GetAllVotes(ctx context.Context, tx pgx.Tx) ([]models.Vote, error)
 	UpdateVote(ctx context.Context, tx pgx.Tx, vote models.Vote) error
 	DeleteVote(ctx context.Context, tx pgx.Tx, voteID int) error
+	SetVoteReceipt(ctx context.Context, tx pgx.Tx, receipt models.VoteReceipt) error
+	GetVoteReceipt(ctx context.Context, tx pgx.Tx, delegateID int) (*models.VoteReceipt, error)
+	DeleteVoteReceipt(ctx context.Context, tx pgx.Tx, delegateID int) error
 
 	AddResult(ctx context.Context, tx pgx.Tx, result models.Result) error
 	GetResultByCourse(ctx context.Context, tx pgx.Tx, course string) (*models.Result, error)
@@ -47,5 +51,44 @@ type storage interface {
 	UpdateResult(ctx context.Context, tx pgx.Tx, result models.Result) error
 	DeleteResult(ctx context.Context, tx pgx.Tx, resultID int) error
 
+	SetSession(ctx context.Context, tx pgx.Tx, session models.Session) error
+	GetSession(ctx context.Context, tx pgx.Tx, key string) (*models.Session, error)
+	TakeSession(ctx context.Context, tx pgx.Tx, key string) (*models.Session, error)
+	DeleteSession(ctx context.Context, tx pgx.Tx, key string) error
+	DeleteExpiredSessions(ctx context.Context, tx pgx.Tx) (int64, error)
+
+	SetScheduleWindow(ctx context.Context, tx pgx.Tx, window models.ScheduleWindow) error
+	GetScheduleWindow(ctx context.Context, tx pgx.Tx, kind string) (*models.ScheduleWindow, error)
+	GetAllScheduleWindows(ctx context.Context, tx pgx.Tx) ([]models.ScheduleWindow, error)
+	UpdateScheduleWindowProgress(ctx context.Context, tx pgx.Tx, window models.ScheduleWindow) error
+	DeleteScheduleWindow(ctx context.Context, tx pgx.Tx, kind string) error
+
+	AddReminder(ctx context.Context, tx pgx.Tx, reminder models.Reminder) (int, error)
+	GetPendingReminders(ctx context.Context, tx pgx.Tx) ([]models.Reminder, error)
+	ClaimDueReminders(ctx context.Context, tx pgx.Tx, now time.Time) ([]models.Reminder, error)
+	UpdateReminderReport(ctx context.Context, tx pgx.Tx, reminder models.Reminder) error
+	DeleteReminder(ctx context.Context, tx pgx.Tx, reminderID int) (bool, error)
+
+	SetAdmin(ctx context.Context, tx pgx.Tx, admin models.Admin) error
+	GetAdmin(ctx context.Context, tx pgx.Tx, telegramID int64) (*models.Admin, error)
+	GetAllAdmins(ctx context.Context, tx pgx.Tx) ([]models.Admin, error)
+	DeleteAdmin(ctx context.Context, tx pgx.Tx, telegramID int64) (bool, error)
+
+	SetUserLanguage(ctx context.Context, tx pgx.Tx, telegramID int64, language string) error
+	GetUserLanguage(ctx context.Context, tx pgx.Tx, telegramID int64) (string, error)
+	SetTextOverride(ctx context.Context, tx pgx.Tx, override models.TextOverride) error
+	GetAllTextOverrides(ctx context.Context, tx pgx.Tx) ([]models.TextOverride, error)
+	DeleteTextOverride(ctx context.Context, tx pgx.Tx, language, key string) (bool, error)
+
+	AddAuditRecord(ctx context.Context, tx pgx.Tx, record models.AuditRecord) error
+	GetAuditLog(ctx context.Context, tx pgx.Tx, delegateID int) ([]models.AuditRecord, error)
+
+	AddProxy(ctx context.Context, tx pgx.Tx, proxy models.Proxy) (int, error)
+	GetActiveProxyByDelegateID(ctx context.Context, tx pgx.Tx, delegateID int) (*models.Proxy, error)
+	GetActiveProxyBySubstituteID(ctx context.Context, tx pgx.Tx, substituteID int) (*models.Proxy, error)
+	GetActiveProxyByTelegramID(ctx context.Context, tx pgx.Tx, telegramID int64) (*models.Proxy, error)
+	GetAllProxies(ctx context.Context, tx pgx.Tx) ([]models.Proxy, error)
+	UpdateProxy(ctx context.Context, tx pgx.Tx, proxy models.Proxy) error
+
 	BeginTx(ctx context.Context, opts pgx.TxOptions) (pgx.Tx, error)
 }
@@ -0,0 +1,89 @@
+package chain
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/lsdpls/schulze_election_telegram_bot/internal/models"
+
+	"github.com/jackc/pgx/v5"
+)
+
+func (vc *VoteChain) SetUserLanguage(ctx context.Context, telegramID int64, language string) error {
+	tx, err := vc.storage.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.Serializable})
+	if err != nil {
+		return fmt.Errorf("chain.SetUserLanguage: can't start transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if err := vc.storage.SetUserLanguage(ctx, tx, telegramID, language); err != nil {
+		return fmt.Errorf("chain.SetUserLanguage: %w", err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("chain.SetUserLanguage: can't commit transaction: %w", err)
+	}
+	return nil
+}
+
+func (vc *VoteChain) GetUserLanguage(ctx context.Context, telegramID int64) (string, error) {
+	tx, err := vc.storage.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.Serializable})
+	if err != nil {
+		return "", fmt.Errorf("chain.GetUserLanguage: can't start transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	language, err := vc.storage.GetUserLanguage(ctx, tx, telegramID)
+	if err != nil {
+		return "", fmt.Errorf("chain.GetUserLanguage: %w", err)
+	}
+	return language, nil
+}
+
+func (vc *VoteChain) SetTextOverride(ctx context.Context, override models.TextOverride) error {
+	tx, err := vc.storage.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.Serializable})
+	if err != nil {
+		return fmt.Errorf("chain.SetTextOverride: can't start transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	override.UpdatedAt = time.Now()
+	if err := vc.storage.SetTextOverride(ctx, tx, override); err != nil {
+		return fmt.Errorf("chain.SetTextOverride: %w", err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("chain.SetTextOverride: can't commit transaction: %w", err)
+	}
+	return nil
+}
+
+func (vc *VoteChain) GetAllTextOverrides(ctx context.Context) ([]models.TextOverride, error) {
+	tx, err := vc.storage.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.Serializable})
+	if err != nil {
+		return nil, fmt.Errorf("chain.GetAllTextOverrides: can't start transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	overrides, err := vc.storage.GetAllTextOverrides(ctx, tx)
+	if err != nil {
+		return nil, fmt.Errorf("chain.GetAllTextOverrides: %w", err)
+	}
+	return overrides, nil
+}
+
+func (vc *VoteChain) DeleteTextOverride(ctx context.Context, language, key string) (bool, error) {
+	tx, err := vc.storage.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.Serializable})
+	if err != nil {
+		return false, fmt.Errorf("chain.DeleteTextOverride: can't start transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	deleted, err := vc.storage.DeleteTextOverride(ctx, tx, language, key)
+	if err != nil {
+		return false, fmt.Errorf("chain.DeleteTextOverride: %w", err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return false, fmt.Errorf("chain.DeleteTextOverride: can't commit transaction: %w", err)
+	}
+	return deleted, nil
+}
@@ -0,0 +1,248 @@
+package chain
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/lsdpls/schulze_election_telegram_bot/internal/models"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// NominateProxy назначает делегату заместителя. Заместитель не может быть делегатом; у делегата и у заместителя
+// не может быть другой действующей доверенности. Группу заместителя проверить не по чему: студентов, кроме делегатов,
+// в базе нет. Отказ возвращается одной из ошибок ErrDelegateNotFound, ErrProxySelf, ErrSubstituteIsDelegate,
+// ErrProxyExists, ErrSubstituteBusy.
+// Возвращает ID доверенности
+func (vc *VoteChain) NominateProxy(ctx context.Context, proxy models.Proxy) (int, error) {
+	tx, err := vc.storage.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.Serializable})
+	if err != nil {
+		return 0, fmt.Errorf("chain.NominateProxy: can't start transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if proxy.DelegateID == proxy.SubstituteID {
+		return 0, fmt.Errorf("chain.NominateProxy: %w", ErrProxySelf)
+	}
+	delegate, err := vc.storage.GetDelegateByDelegateID(ctx, tx, proxy.DelegateID)
+	if err != nil {
+		return 0, fmt.Errorf("chain.NominateProxy: %w", err)
+	}
+	if delegate == nil {
+		return 0, fmt.Errorf("chain.NominateProxy: %w", ErrDelegateNotFound)
+	}
+	substitute, err := vc.storage.GetDelegateByDelegateID(ctx, tx, proxy.SubstituteID)
+	if err != nil {
+		return 0, fmt.Errorf("chain.NominateProxy: %w", err)
+	}
+	if substitute != nil {
+		return 0, fmt.Errorf("chain.NominateProxy: %w", ErrSubstituteIsDelegate)
+	}
+	active, err := vc.storage.GetActiveProxyByDelegateID(ctx, tx, proxy.DelegateID)
+	if err != nil {
+		return 0, fmt.Errorf("chain.NominateProxy: %w", err)
+	}
+	if active != nil {
+		return 0, fmt.Errorf("chain.NominateProxy: %w", ErrProxyExists)
+	}
+	active, err = vc.storage.GetActiveProxyBySubstituteID(ctx, tx, proxy.SubstituteID)
+	if err != nil {
+		return 0, fmt.Errorf("chain.NominateProxy: %w", err)
+	}
+	if active != nil {
+		return 0, fmt.Errorf("chain.NominateProxy: %w", ErrSubstituteBusy)
+	}
+
+	proxyID, err := vc.storage.AddProxy(ctx, tx, proxy)
+	if err != nil {
+		return 0, fmt.Errorf("chain.NominateProxy: %w", err)
+	}
+	err = vc.storage.AddAuditRecord(ctx, tx, models.AuditRecord{
+		Action:      models.AuditProxyNominated,
+		DelegateID:  proxy.DelegateID,
+		PerformedBy: proxy.NominatedBy,
+		Details:     strconv.Itoa(proxy.SubstituteID),
+	})
+	if err != nil {
+		return 0, fmt.Errorf("chain.NominateProxy: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("chain.NominateProxy: can't commit transaction: %w", err)
+	}
+	return proxyID, nil
+}
+
+// RevokeProxy отзывает действующую доверенность делегата. Голос, уже отданный заместителем, остается за делегатом,
+// пока делегат не переголосует. Возвращает отозванную доверенность или ErrProxyNotFound
+func (vc *VoteChain) RevokeProxy(ctx context.Context, delegateID int, revokedBy sql.NullInt64) (*models.Proxy, error) {
+	tx, err := vc.storage.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.Serializable})
+	if err != nil {
+		return nil, fmt.Errorf("chain.RevokeProxy: can't start transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	proxy, err := vc.storage.GetActiveProxyByDelegateID(ctx, tx, delegateID)
+	if err != nil {
+		return nil, fmt.Errorf("chain.RevokeProxy: %w", err)
+	}
+	if proxy == nil {
+		return nil, fmt.Errorf("chain.RevokeProxy: %w", ErrProxyNotFound)
+	}
+	proxy.RevokedAt = sql.NullTime{Time: time.Now(), Valid: true}
+	proxy.RevokedBy = revokedBy
+	if err := vc.storage.UpdateProxy(ctx, tx, *proxy); err != nil {
+		return nil, fmt.Errorf("chain.RevokeProxy: %w", err)
+	}
+	err = vc.storage.AddAuditRecord(ctx, tx, models.AuditRecord{
+		Action:      models.AuditProxyRevoked,
+		DelegateID:  delegateID,
+		TelegramID:  proxy.TelegramID,
+		PerformedBy: revokedBy,
+		Details:     strconv.Itoa(proxy.SubstituteID),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("chain.RevokeProxy: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("chain.RevokeProxy: can't commit transaction: %w", err)
+	}
+	return proxy, nil
+}
+
+// VerifyProxy привязывает Telegram аккаунт заместителя, подтвердившего почту. Если доверенность отозвали
+// или уже подтвердили, пока заместитель вводил код, возвращается ErrProxyNotFound. Возвращает доверенность
+func (vc *VoteChain) VerifyProxy(ctx context.Context, substituteID int, telegramID sql.NullInt64) (*models.Proxy, error) {
+	tx, err := vc.storage.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.Serializable})
+	if err != nil {
+		return nil, fmt.Errorf("chain.VerifyProxy: can't start transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	proxy, err := vc.storage.GetActiveProxyBySubstituteID(ctx, tx, substituteID)
+	if err != nil {
+		return nil, fmt.Errorf("chain.VerifyProxy: %w", err)
+	}
+	if proxy == nil || proxy.TelegramID.Valid {
+		return nil, fmt.Errorf("chain.VerifyProxy: %w", ErrProxyNotFound)
+	}
+	delegate, err := vc.storage.GetDelegateByTelegramID(ctx, tx, telegramID.Int64)
+	if err != nil {
+		return nil, fmt.Errorf("chain.VerifyProxy: %w", err)
+	}
+	if delegate != nil {
+		return nil, fmt.Errorf("chain.VerifyProxy: account belongs to a delegate")
+	}
+
+	proxy.TelegramID = telegramID
+	if err := vc.storage.UpdateProxy(ctx, tx, *proxy); err != nil {
+		return nil, fmt.Errorf("chain.VerifyProxy: %w", err)
+	}
+	err = vc.storage.AddAuditRecord(ctx, tx, models.AuditRecord{
+		Action:     models.AuditProxyVerified,
+		DelegateID: proxy.DelegateID,
+		TelegramID: telegramID,
+		Details:    strconv.Itoa(proxy.SubstituteID),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("chain.VerifyProxy: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("chain.VerifyProxy: can't commit transaction: %w", err)
+	}
+	return proxy, nil
+}
+
+func (vc *VoteChain) GetActiveProxyByDelegateID(ctx context.Context, delegateID int) (*models.Proxy, error) {
+	tx, err := vc.storage.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.Serializable})
+	if err != nil {
+		return nil, fmt.Errorf("chain.GetActiveProxyByDelegateID: can't start transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	proxy, err := vc.storage.GetActiveProxyByDelegateID(ctx, tx, delegateID)
+	if err != nil {
+		return nil, fmt.Errorf("chain.GetActiveProxyByDelegateID: %w", err)
+	}
+	return proxy, nil
+}
+
+func (vc *VoteChain) GetActiveProxyBySubstituteID(ctx context.Context, substituteID int) (*models.Proxy, error) {
+	tx, err := vc.storage.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.Serializable})
+	if err != nil {
+		return nil, fmt.Errorf("chain.GetActiveProxyBySubstituteID: can't start transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	proxy, err := vc.storage.GetActiveProxyBySubstituteID(ctx, tx, substituteID)
+	if err != nil {
+		return nil, fmt.Errorf("chain.GetActiveProxyBySubstituteID: %w", err)
+	}
+	return proxy, nil
+}
+
+func (vc *VoteChain) GetAllProxies(ctx context.Context) ([]models.Proxy, error) {
+	tx, err := vc.storage.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.Serializable})
+	if err != nil {
+		return nil, fmt.Errorf("chain.GetAllProxies: can't start transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	proxies, err := vc.storage.GetAllProxies(ctx, tx)
+	if err != nil {
+		return nil, fmt.Errorf("chain.GetAllProxies: %w", err)
+	}
+	return proxies, nil
+}
+
+// ResolveVoter определяет, за кого голосует Telegram аккаунт: за себя как делегат или за делегата
+// по доверенности. nil — аккаунт не зарегистрирован
+func (vc *VoteChain) ResolveVoter(ctx context.Context, telegramID int64) (*models.Voter, error) {
+	tx, err := vc.storage.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.Serializable})
+	if err != nil {
+		return nil, fmt.Errorf("chain.ResolveVoter: can't start transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	voter, err := vc.resolveVoter(ctx, tx, telegramID)
+	if err != nil {
+		return nil, fmt.Errorf("chain.ResolveVoter: %w", err)
+	}
+	return voter, nil
+}
+
+func (vc *VoteChain) resolveVoter(ctx context.Context, tx pgx.Tx, telegramID int64) (*models.Voter, error) {
+	delegate, err := vc.storage.GetDelegateByTelegramID(ctx, tx, telegramID)
+	if err != nil {
+		return nil, err
+	}
+	if delegate != nil {
+		voter := &models.Voter{DelegateID: delegate.DelegateID}
+		proxy, err := vc.storage.GetActiveProxyByDelegateID(ctx, tx, delegate.DelegateID)
+		if err != nil {
+			return nil, err
+		}
+		if proxy != nil {
+			voter.SubstituteID = proxy.SubstituteID
+		}
+		return voter, nil
+	}
+
+	proxy, err := vc.storage.GetActiveProxyByTelegramID(ctx, tx, telegramID)
+	if err != nil {
+		return nil, err
+	}
+	if proxy == nil {
+		return nil, nil
+	}
+	return &models.Voter{
+		DelegateID:   proxy.DelegateID,
+		ProxyID:      sql.NullInt64{Int64: int64(proxy.ID), Valid: true},
+		SubstituteID: proxy.SubstituteID,
+	}, nil
+}
@@ -0,0 +1,93 @@
+package chain
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/lsdpls/schulze_election_telegram_bot/internal/models"
+
+	"github.com/jackc/pgx/v5"
+)
+
+func (vc *VoteChain) AddReminder(ctx context.Context, reminder models.Reminder) (int, error) {
+	tx, err := vc.storage.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.Serializable})
+	if err != nil {
+		return 0, fmt.Errorf("chain.AddReminder: can't start transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	reminder.CreatedAt = time.Now()
+	id, err := vc.storage.AddReminder(ctx, tx, reminder)
+	if err != nil {
+		return 0, fmt.Errorf("chain.AddReminder: %w", err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("chain.AddReminder: can't commit transaction: %w", err)
+	}
+	return id, nil
+}
+
+func (vc *VoteChain) GetPendingReminders(ctx context.Context) ([]models.Reminder, error) {
+	tx, err := vc.storage.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.Serializable})
+	if err != nil {
+		return nil, fmt.Errorf("chain.GetPendingReminders: can't start transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	reminders, err := vc.storage.GetPendingReminders(ctx, tx)
+	if err != nil {
+		return nil, fmt.Errorf("chain.GetPendingReminders: %w", err)
+	}
+	return reminders, nil
+}
+
+func (vc *VoteChain) ClaimDueReminders(ctx context.Context, now time.Time) ([]models.Reminder, error) {
+	tx, err := vc.storage.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.Serializable})
+	if err != nil {
+		return nil, fmt.Errorf("chain.ClaimDueReminders: can't start transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	reminders, err := vc.storage.ClaimDueReminders(ctx, tx, now)
+	if err != nil {
+		return nil, fmt.Errorf("chain.ClaimDueReminders: %w", err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("chain.ClaimDueReminders: can't commit transaction: %w", err)
+	}
+	return reminders, nil
+}
+
+func (vc *VoteChain) UpdateReminderReport(ctx context.Context, reminder models.Reminder) error {
+	tx, err := vc.storage.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.Serializable})
+	if err != nil {
+		return fmt.Errorf("chain.UpdateReminderReport: can't start transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if err := vc.storage.UpdateReminderReport(ctx, tx, reminder); err != nil {
+		return fmt.Errorf("chain.UpdateReminderReport: %w", err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("chain.UpdateReminderReport: can't commit transaction: %w", err)
+	}
+	return nil
+}
+
+func (vc *VoteChain) DeleteReminder(ctx context.Context, reminderID int) (bool, error) {
+	tx, err := vc.storage.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.Serializable})
+	if err != nil {
+		return false, fmt.Errorf("chain.DeleteReminder: can't start transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	deleted, err := vc.storage.DeleteReminder(ctx, tx, reminderID)
+	if err != nil {
+		return false, fmt.Errorf("chain.DeleteReminder: %w", err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return false, fmt.Errorf("chain.DeleteReminder: can't commit transaction: %w", err)
+	}
+	return deleted, nil
+}
@@ -3,6 +3,7 @@ package chain
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/lsdpls/schulze_election_telegram_bot/internal/models"
 
@@ -16,6 +17,8 @@ func (vc *VoteChain) AddResult(ctx context.Context, result models.Result) error
 	}
 	defer tx.Rollback(ctx)
 
+	result.ComputedAt = time.Now()
+
 	// Проверяем, существует ли уже результат для данного курса
 	resultDB, err := vc.storage.GetResultByCourse(ctx, tx, result.Course)
 	if err != nil {
@@ -0,0 +1,86 @@
+package chain
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/lsdpls/schulze_election_telegram_bot/internal/models"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// ApplyRosterImport применяет импорт списков в одной транзакции: при любой ошибке не меняется ничего.
+// Обновляются только данные из файла, регистрация, голоса и допуск кандидатов сохраняются
+func (vc *VoteChain) ApplyRosterImport(ctx context.Context, rosterImport models.RosterImport) error {
+	tx, err := vc.storage.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.Serializable})
+	if err != nil {
+		return fmt.Errorf("chain.ApplyRosterImport: can't start transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	// Сначала удаления, чтобы освободить группы для новых и измененных делегатов
+	for _, delegateID := range rosterImport.DeleteDelegates {
+		delegate, err := vc.storage.GetDelegateByDelegateID(ctx, tx, delegateID)
+		if err != nil {
+			return fmt.Errorf("chain.ApplyRosterImport: %w", err)
+		}
+		if delegate == nil {
+			continue
+		}
+		// Удаление делегата удалило бы и его голос
+		if delegate.HasVoted {
+			return fmt.Errorf("chain.ApplyRosterImport: delegate %d has already voted", delegateID)
+		}
+		if err := vc.storage.DeleteDelegate(ctx, tx, delegateID); err != nil {
+			return fmt.Errorf("chain.ApplyRosterImport: %w", err)
+		}
+	}
+	for _, candidateID := range rosterImport.DeleteCandidates {
+		if err := vc.storage.DeleteCandidate(ctx, tx, candidateID); err != nil {
+			return fmt.Errorf("chain.ApplyRosterImport: %w", err)
+		}
+	}
+
+	for _, update := range rosterImport.UpdateDelegates {
+		delegate, err := vc.storage.GetDelegateByDelegateID(ctx, tx, update.DelegateID)
+		if err != nil {
+			return fmt.Errorf("chain.ApplyRosterImport: %w", err)
+		}
+		if delegate == nil {
+			return fmt.Errorf("chain.ApplyRosterImport: delegate %d not found", update.DelegateID)
+		}
+		delegate.Name, delegate.Group = update.Name, update.Group
+		if err := vc.storage.UpdateDelegate(ctx, tx, *delegate); err != nil {
+			return fmt.Errorf("chain.ApplyRosterImport: %w", err)
+		}
+	}
+	for _, update := range rosterImport.UpdateCandidates {
+		candidate, err := vc.storage.GetCandidateByCandidateID(ctx, tx, update.CandidateID)
+		if err != nil {
+			return fmt.Errorf("chain.ApplyRosterImport: %w", err)
+		}
+		if candidate == nil {
+			return fmt.Errorf("chain.ApplyRosterImport: candidate %d not found", update.CandidateID)
+		}
+		candidate.Name, candidate.Course, candidate.Description = update.Name, update.Course, update.Description
+		if err := vc.storage.UpdateCandidate(ctx, tx, *candidate); err != nil {
+			return fmt.Errorf("chain.ApplyRosterImport: %w", err)
+		}
+	}
+
+	for _, delegate := range rosterImport.AddDelegates {
+		if err := vc.storage.AddDelegate(ctx, tx, delegate); err != nil {
+			return fmt.Errorf("chain.ApplyRosterImport: %w", err)
+		}
+	}
+	for _, candidate := range rosterImport.AddCandidates {
+		if err := vc.storage.AddCandidate(ctx, tx, candidate); err != nil {
+			return fmt.Errorf("chain.ApplyRosterImport: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("chain.ApplyRosterImport: can't commit transaction: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+package chain
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/lsdpls/schulze_election_telegram_bot/internal/models"
+
+	"github.com/jackc/pgx/v5"
+)
+
+func (vc *VoteChain) SetScheduleWindow(ctx context.Context, window models.ScheduleWindow) error {
+	tx, err := vc.storage.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.Serializable})
+	if err != nil {
+		return fmt.Errorf("chain.SetScheduleWindow: can't start transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	window.UpdatedAt = time.Now()
+	if err := vc.storage.SetScheduleWindow(ctx, tx, window); err != nil {
+		return fmt.Errorf("chain.SetScheduleWindow: %w", err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("chain.SetScheduleWindow: can't commit transaction: %w", err)
+	}
+	return nil
+}
+
+func (vc *VoteChain) GetScheduleWindow(ctx context.Context, kind string) (*models.ScheduleWindow, error) {
+	tx, err := vc.storage.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.Serializable})
+	if err != nil {
+		return nil, fmt.Errorf("chain.GetScheduleWindow: can't start transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	window, err := vc.storage.GetScheduleWindow(ctx, tx, kind)
+	if err != nil {
+		return nil, fmt.Errorf("chain.GetScheduleWindow: %w", err)
+	}
+	return window, nil
+}
+
+func (vc *VoteChain) GetAllScheduleWindows(ctx context.Context) ([]models.ScheduleWindow, error) {
+	tx, err := vc.storage.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.Serializable})
+	if err != nil {
+		return nil, fmt.Errorf("chain.GetAllScheduleWindows: can't start transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	windows, err := vc.storage.GetAllScheduleWindows(ctx, tx)
+	if err != nil {
+		return nil, fmt.Errorf("chain.GetAllScheduleWindows: %w", err)
+	}
+	return windows, nil
+}
+
+func (vc *VoteChain) UpdateScheduleWindowProgress(ctx context.Context, window models.ScheduleWindow) error {
+	tx, err := vc.storage.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.Serializable})
+	if err != nil {
+		return fmt.Errorf("chain.UpdateScheduleWindowProgress: can't start transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	// Проверяем, что окно не удалили, пока планировщик его обрабатывал
+	windowDB, err := vc.storage.GetScheduleWindow(ctx, tx, window.Kind)
+	if err != nil {
+		return fmt.Errorf("chain.UpdateScheduleWindowProgress: %w", err)
+	}
+	if windowDB == nil {
+		return fmt.Errorf("chain.UpdateScheduleWindowProgress: schedule window %s not found", window.Kind)
+	}
+
+	window.UpdatedAt = time.Now()
+	if err := vc.storage.UpdateScheduleWindowProgress(ctx, tx, window); err != nil {
+		return fmt.Errorf("chain.UpdateScheduleWindowProgress: %w", err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("chain.UpdateScheduleWindowProgress: can't commit transaction: %w", err)
+	}
+	return nil
+}
+
+func (vc *VoteChain) DeleteScheduleWindow(ctx context.Context, kind string) error {
+	tx, err := vc.storage.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.Serializable})
+	if err != nil {
+		return fmt.Errorf("chain.DeleteScheduleWindow: can't start transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if err := vc.storage.DeleteScheduleWindow(ctx, tx, kind); err != nil {
+		return fmt.Errorf("chain.DeleteScheduleWindow: %w", err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("chain.DeleteScheduleWindow: can't commit transaction: %w", err)
+	}
+	return nil
+}
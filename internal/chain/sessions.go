@@ -0,0 +1,91 @@
+package chain
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/lsdpls/schulze_election_telegram_bot/internal/models"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// Сессии читаются и изменяются по одному ключу, поэтому для них достаточно ReadCommitted
+func (vc *VoteChain) SetSession(ctx context.Context, session models.Session) error {
+	tx, err := vc.storage.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.ReadCommitted})
+	if err != nil {
+		return fmt.Errorf("chain.SetSession: can't start transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if err := vc.storage.SetSession(ctx, tx, session); err != nil {
+		return fmt.Errorf("chain.SetSession: %w", err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("chain.SetSession: can't commit transaction: %w", err)
+	}
+	return nil
+}
+
+func (vc *VoteChain) GetSession(ctx context.Context, key string) (*models.Session, error) {
+	tx, err := vc.storage.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.ReadCommitted})
+	if err != nil {
+		return nil, fmt.Errorf("chain.GetSession: can't start transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	session, err := vc.storage.GetSession(ctx, tx, key)
+	if err != nil {
+		return nil, fmt.Errorf("chain.GetSession: %w", err)
+	}
+	return session, nil
+}
+
+func (vc *VoteChain) TakeSession(ctx context.Context, key string) (*models.Session, error) {
+	tx, err := vc.storage.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.ReadCommitted})
+	if err != nil {
+		return nil, fmt.Errorf("chain.TakeSession: can't start transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	session, err := vc.storage.TakeSession(ctx, tx, key)
+	if err != nil {
+		return nil, fmt.Errorf("chain.TakeSession: %w", err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("chain.TakeSession: can't commit transaction: %w", err)
+	}
+	return session, nil
+}
+
+func (vc *VoteChain) DeleteSession(ctx context.Context, key string) error {
+	tx, err := vc.storage.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.ReadCommitted})
+	if err != nil {
+		return fmt.Errorf("chain.DeleteSession: can't start transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if err := vc.storage.DeleteSession(ctx, tx, key); err != nil {
+		return fmt.Errorf("chain.DeleteSession: %w", err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("chain.DeleteSession: can't commit transaction: %w", err)
+	}
+	return nil
+}
+
+func (vc *VoteChain) DeleteExpiredSessions(ctx context.Context) (int64, error) {
+	tx, err := vc.storage.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.ReadCommitted})
+	if err != nil {
+		return 0, fmt.Errorf("chain.DeleteExpiredSessions: can't start transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	deleted, err := vc.storage.DeleteExpiredSessions(ctx, tx)
+	if err != nil {
+		return 0, fmt.Errorf("chain.DeleteExpiredSessions: %w", err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("chain.DeleteExpiredSessions: can't commit transaction: %w", err)
+	}
+	return deleted, nil
+}
@@ -2,7 +2,9 @@ package chain
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
+	"strconv"
 	"time"
 
 	"github.com/lsdpls/schulze_election_telegram_bot/internal/models"
@@ -10,6 +12,8 @@ import (
 	"github.com/jackc/pgx/v5"
 )
 
+// AddVote записывает или заменяет голос делегата. Заместитель голосует под ID делегата с отметкой доверенности,
+// делегат с действующей доверенностью голосовать не может
 func (vc *VoteChain) AddVote(ctx context.Context, telegramID int64, votes []int) error {
 	tx, err := vc.storage.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.Serializable})
 	if err != nil {
@@ -17,7 +21,17 @@ func (vc *VoteChain) AddVote(ctx context.Context, telegramID int64, votes []int)
 	}
 	defer tx.Rollback(ctx)
 
-	delegate, err := vc.storage.GetDelegateByTelegramID(ctx, tx, telegramID)
+	voter, err := vc.resolveVoter(ctx, tx, telegramID)
+	if err != nil {
+		return fmt.Errorf("chain.AddVote: %w", err)
+	}
+	if voter == nil {
+		return fmt.Errorf("chain.AddVote: delegate not found")
+	}
+	if voter.Delegated() {
+		return fmt.Errorf("chain.AddVote: delegate has an active proxy")
+	}
+	delegate, err := vc.storage.GetDelegateByDelegateID(ctx, tx, voter.DelegateID)
 	if err != nil {
 		return fmt.Errorf("chain.AddVote: %w", err)
 	}
@@ -29,6 +43,23 @@ func (vc *VoteChain) AddVote(ctx context.Context, telegramID int64, votes []int)
 		DelegateID:        delegate.DelegateID,
 		CandidateRankings: votes,
 		CreatedAt:         time.Now(),
+		ProxyID:           voter.ProxyID,
+	}
+	if voter.ProxyID.Valid {
+		err = vc.storage.AddAuditRecord(ctx, tx, models.AuditRecord{
+			Action:     models.AuditProxyVoted,
+			DelegateID: delegate.DelegateID,
+			TelegramID: sql.NullInt64{Int64: telegramID, Valid: true},
+			Details:    strconv.Itoa(voter.SubstituteID),
+		})
+		if err != nil {
+			return fmt.Errorf("chain.AddVote: %w", err)
+		}
+	}
+
+	// Квитанция прежнего бюллетеня больше не относится к голосу: новую запишет ConfirmVote после подтверждения
+	if err := vc.storage.DeleteVoteReceipt(ctx, tx, delegate.DelegateID); err != nil {
+		return fmt.Errorf("chain.AddVote: %w", err)
 	}
 
 	currentVote, err := vc.storage.GetVoteByDelegateID(ctx, tx, delegate.DelegateID)
@@ -60,6 +91,38 @@ func (vc *VoteChain) AddVote(ctx context.Context, telegramID int64, votes []int)
 
 }
 
+// ConfirmVote сохраняет квитанцию — бюллетень, о принятии которого бот сообщил голосующему. Вызывается после
+// доставки подтверждения, поэтому неотправленное подтверждение квитанцией не считается
+func (vc *VoteChain) ConfirmVote(ctx context.Context, telegramID int64, votes []int) error {
+	tx, err := vc.storage.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.Serializable})
+	if err != nil {
+		return fmt.Errorf("chain.ConfirmVote: can't start transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	voter, err := vc.resolveVoter(ctx, tx, telegramID)
+	if err != nil {
+		return fmt.Errorf("chain.ConfirmVote: %w", err)
+	}
+	if voter == nil {
+		return fmt.Errorf("chain.ConfirmVote: delegate not found")
+	}
+	err = vc.storage.SetVoteReceipt(ctx, tx, models.VoteReceipt{
+		DelegateID:        voter.DelegateID,
+		CandidateRankings: votes,
+		TelegramID:        telegramID,
+		ConfirmedAt:       time.Now(),
+	})
+	if err != nil {
+		return fmt.Errorf("chain.ConfirmVote: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("chain.ConfirmVote: can't commit transaction: %w", err)
+	}
+	return nil
+}
+
 func (vc *VoteChain) GetAllVotes(ctx context.Context) ([]models.Vote, error) {
 	tx, err := vc.storage.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.Serializable})
 	if err != nil {
@@ -112,9 +175,32 @@ func (vc *VoteChain) DeleteVoteByDelegateID(ctx context.Context, delegateID int)
 	if err := vc.storage.DeleteVote(ctx, tx, voteID); err != nil {
 		return fmt.Errorf("chain.DeleteVote: %w", err)
 	}
+	if err := vc.storage.DeleteVoteReceipt(ctx, tx, delegateID); err != nil {
+		return fmt.Errorf("chain.DeleteVote: %w", err)
+	}
 
 	if err := tx.Commit(ctx); err != nil {
 		return fmt.Errorf("chain.DeleteVote: can't commit transaction: %w", err)
 	}
 	return nil
 }
+
+// GetVoteWithReceipt возвращает записанный голос делегата и бюллетень, который бот подтвердил последним.
+// nil — голоса или подтверждения нет
+func (vc *VoteChain) GetVoteWithReceipt(ctx context.Context, delegateID int) (*models.Vote, *models.VoteReceipt, error) {
+	tx, err := vc.storage.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.Serializable})
+	if err != nil {
+		return nil, nil, fmt.Errorf("chain.GetVoteWithReceipt: can't start transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	vote, err := vc.storage.GetVoteByDelegateID(ctx, tx, delegateID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("chain.GetVoteWithReceipt: %w", err)
+	}
+	receipt, err := vc.storage.GetVoteReceipt(ctx, tx, delegateID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("chain.GetVoteWithReceipt: %w", err)
+	}
+	return vote, receipt, nil
+}
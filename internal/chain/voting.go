@@ -0,0 +1,68 @@
+package chain
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/lsdpls/schulze_election_telegram_bot/internal/models"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// Ключ флага открытого голосования в таблице sessions (хранится бессрочно, значение — JSON true/false)
+const votingSessionKey = "voting:active"
+
+// SetVotingActive открывает или закрывает голосование. Флаг пишется в Serializable транзакции,
+// поэтому одновременное изменение полей, закрытых на время голосования, завершится ошибкой сериализации
+func (vc *VoteChain) SetVotingActive(ctx context.Context, active bool) error {
+	tx, err := vc.storage.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.Serializable})
+	if err != nil {
+		return fmt.Errorf("chain.SetVotingActive: can't start transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	value, err := json.Marshal(active)
+	if err != nil {
+		return fmt.Errorf("chain.SetVotingActive: marshal failed: %w", err)
+	}
+	if err := vc.storage.SetSession(ctx, tx, models.Session{Key: votingSessionKey, Value: value, UpdatedAt: time.Now()}); err != nil {
+		return fmt.Errorf("chain.SetVotingActive: %w", err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("chain.SetVotingActive: can't commit transaction: %w", err)
+	}
+	return nil
+}
+
+// VotingActive сообщает, открыто ли голосование
+func (vc *VoteChain) VotingActive(ctx context.Context) (bool, error) {
+	tx, err := vc.storage.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.Serializable})
+	if err != nil {
+		return false, fmt.Errorf("chain.VotingActive: can't start transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	active, err := vc.votingActive(ctx, tx)
+	if err != nil {
+		return false, fmt.Errorf("chain.VotingActive: %w", err)
+	}
+	return active, nil
+}
+
+// votingActive читает флаг открытого голосования в транзакции tx
+func (vc *VoteChain) votingActive(ctx context.Context, tx pgx.Tx) (bool, error) {
+	session, err := vc.storage.GetSession(ctx, tx, votingSessionKey)
+	if err != nil {
+		return false, err
+	}
+	if session == nil {
+		return false, nil
+	}
+	var active bool
+	if err := json.Unmarshal(session.Value, &active); err != nil {
+		return false, fmt.Errorf("voting flag unmarshal failed: %w", err)
+	}
+	return active, nil
+}
@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"os"
 	"strconv"
+	"strings"
+	"time"
 )
 
 // Telegram Bot
@@ -24,14 +26,58 @@ var PostgresSSLMode string
 var SMTPEmail string
 var SMTPPassword string
 
+// Email verification
+var VerificationCodeTTL time.Duration
+var VerificationResendCooldown time.Duration
+var VerificationMaxCodeAttempts int
+var VerificationMaxAccountAttempts int
+
 // App
 var AppPort string
 
+// Способ получения обновлений Telegram
+const (
+	UpdatesModeWebhook = "webhook" // Telegram отправляет обновления на адрес бота
+	UpdatesModePolling = "polling" // Бот сам запрашивает обновления через getUpdates
+)
+
+var UpdatesMode string
+
+// Webhook: публичный адрес бота и секрет, которым Telegram подписывает запросы
+var WebhookURL string
+var WebhookSecret string
+
+// Число обработчиков обновлений: обновления одного пользователя обрабатываются по порядку, разных — параллельно
+var UpdateWorkers int
+
+// Rate limits: частота действий одного Telegram аккаунта
+type RateLimit struct {
+	Burst    int           // Сколько действий можно выполнить подряд
+	Interval time.Duration // Через сколько восстанавливается одно действие
+}
+
+var RateLimitCommands RateLimit
+var RateLimitCallbacks RateLimit
+var RateLimitEmails RateLimit
+var RateLimitCodes RateLimit
+
 // Vote Token Security
 var VoteTokenSecret string
 
 // Election
 var TotalPlaces int
+var ElectionName string
+var ElectionTimezone string
+
+// Protocol
+var PDFFontDir string
+var ProtocolAPIToken string // Токен доступа к протоколу через API (пусто — протокол доступен только командой /protocol)
+
+// Mini App
+var WebAppURL string
+
+// Turnout
+var TurnoutRefreshInterval time.Duration
 
 // Logging
 var LogLevel string
@@ -110,12 +156,54 @@ func LoadConfig() error {
 		return fmt.Errorf("SMTP_PASSWORD is required")
 	}
 
+	// Email verification
+	VerificationCodeTTL, err = durationFromEnv("VERIFICATION_CODE_TTL", 10*time.Minute)
+	if err != nil {
+		return err
+	}
+	VerificationResendCooldown, err = durationFromEnv("VERIFICATION_RESEND_COOLDOWN", time.Minute)
+	if err != nil {
+		return err
+	}
+	VerificationMaxCodeAttempts, err = positiveIntFromEnv("VERIFICATION_MAX_CODE_ATTEMPTS", 3)
+	if err != nil {
+		return err
+	}
+	VerificationMaxAccountAttempts, err = positiveIntFromEnv("VERIFICATION_MAX_ACCOUNT_ATTEMPTS", 10)
+	if err != nil {
+		return err
+	}
+
 	// App Port
 	AppPort = os.Getenv("APP_PORT")
 	if AppPort == "" {
 		return fmt.Errorf("APP_PORT is required")
 	}
 
+	// Updates mode
+	UpdatesMode = os.Getenv("UPDATES_MODE")
+	if UpdatesMode == "" {
+		UpdatesMode = UpdatesModeWebhook // Значение по умолчанию
+	}
+	if UpdatesMode != UpdatesModeWebhook && UpdatesMode != UpdatesModePolling {
+		return fmt.Errorf("invalid UPDATES_MODE: %q (webhook or polling)", UpdatesMode)
+	}
+	if UpdatesMode == UpdatesModeWebhook {
+		WebhookURL = os.Getenv("WEBHOOK_URL")
+		if WebhookURL == "" {
+			return fmt.Errorf("WEBHOOK_URL is required in webhook mode")
+		}
+		WebhookSecret = os.Getenv("WEBHOOK_SECRET")
+		if !validWebhookSecret(WebhookSecret) {
+			return fmt.Errorf("WEBHOOK_SECRET is required in webhook mode: 32-256 characters A-Z, a-z, 0-9, _ and -")
+		}
+	}
+
+	UpdateWorkers, err = positiveIntFromEnv("UPDATE_WORKERS", 16)
+	if err != nil {
+		return err
+	}
+
 	// Vote Token Secret
 	VoteTokenSecret = os.Getenv("VOTE_TOKEN_SECRET")
 	if VoteTokenSecret == "" {
@@ -138,6 +226,52 @@ func LoadConfig() error {
 		return fmt.Errorf("TOTAL_PLACES must be greater than 0")
 	}
 
+	ElectionName = os.Getenv("ELECTION_NAME")
+	if ElectionName == "" {
+		ElectionName = "Выборы в Студенческий совет ПМ-ПУ" // Значение по умолчанию
+	}
+
+	ElectionTimezone = os.Getenv("ELECTION_TIMEZONE")
+	if ElectionTimezone == "" {
+		ElectionTimezone = "Europe/Moscow" // Значение по умолчанию
+	}
+	if _, err := time.LoadLocation(ElectionTimezone); err != nil {
+		return fmt.Errorf("invalid ELECTION_TIMEZONE: %v", err)
+	}
+
+	// Protocol
+	PDFFontDir = os.Getenv("PDF_FONT_DIR")
+	if PDFFontDir == "" {
+		PDFFontDir = "/usr/share/fonts/dejavu" // Значение по умолчанию
+	}
+	ProtocolAPIToken = os.Getenv("PROTOCOL_API_TOKEN")
+	if ProtocolAPIToken != "" && len(ProtocolAPIToken) < 32 {
+		return fmt.Errorf("PROTOCOL_API_TOKEN must be at least 32 characters long")
+	}
+
+	// Mini App (необязательно, без адреса бюллетень доступен только через кнопки в чате)
+	WebAppURL = os.Getenv("WEBAPP_URL")
+
+	// Rate limits
+	if RateLimitCommands, err = rateLimitFromEnv("RATE_LIMIT_COMMANDS", "20/1m"); err != nil {
+		return err
+	}
+	if RateLimitCallbacks, err = rateLimitFromEnv("RATE_LIMIT_CALLBACKS", "60/1m"); err != nil {
+		return err
+	}
+	if RateLimitEmails, err = rateLimitFromEnv("RATE_LIMIT_EMAILS", "5/1h"); err != nil {
+		return err
+	}
+	if RateLimitCodes, err = rateLimitFromEnv("RATE_LIMIT_CODES", "10/10m"); err != nil {
+		return err
+	}
+
+	// Turnout
+	TurnoutRefreshInterval, err = durationFromEnv("TURNOUT_REFRESH_INTERVAL", time.Minute)
+	if err != nil {
+		return err
+	}
+
 	// Собираем DATABASE_URL из отдельных компонентов
 	DatabaseURL = fmt.Sprintf("postgres://%s:%s@%s:%d/%s?sslmode=%s",
 		PostgresUser,
@@ -161,3 +295,68 @@ func LoadConfig() error {
 
 	return nil
 }
+
+// durationFromEnv читает длительность (например, 10m) из переменной окружения или возвращает значение по умолчанию
+func durationFromEnv(name string, defaultValue time.Duration) (time.Duration, error) {
+	value := os.Getenv(name)
+	if value == "" {
+		return defaultValue, nil
+	}
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s: %v", name, err)
+	}
+	if duration <= 0 {
+		return 0, fmt.Errorf("%s must be greater than 0", name)
+	}
+	return duration, nil
+}
+
+// rateLimitFromEnv читает ограничение частоты в формате <число>/<период> (например, 20/1m: 20 действий подряд,
+// далее одно действие каждые 3 секунды) или возвращает значение по умолчанию
+func rateLimitFromEnv(name, defaultValue string) (RateLimit, error) {
+	value := os.Getenv(name)
+	if value == "" {
+		value = defaultValue
+	}
+	count, period, ok := strings.Cut(value, "/")
+	burst, err := strconv.Atoi(strings.TrimSpace(count))
+	if !ok || err != nil || burst <= 0 {
+		return RateLimit{}, fmt.Errorf("invalid %s: %q (expected <count>/<period>, for example 20/1m)", name, value)
+	}
+	duration, err := time.ParseDuration(strings.TrimSpace(period))
+	if err != nil || duration <= 0 {
+		return RateLimit{}, fmt.Errorf("invalid %s: %q (expected <count>/<period>, for example 20/1m)", name, value)
+	}
+	return RateLimit{Burst: burst, Interval: duration / time.Duration(burst)}, nil
+}
+
+// positiveIntFromEnv читает положительное число из переменной окружения или возвращает значение по умолчанию
+func positiveIntFromEnv(name string, defaultValue int) (int, error) {
+	value := os.Getenv(name)
+	if value == "" {
+		return defaultValue, nil
+	}
+	number, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s: %v", name, err)
+	}
+	if number <= 0 {
+		return 0, fmt.Errorf("%s must be greater than 0", name)
+	}
+	return number, nil
+}
+
+// validWebhookSecret проверяет секрет по требованиям Telegram к secret_token (1-256 символов A-Z, a-z, 0-9, _ и -)
+// и не допускает слишком короткий секрет
+func validWebhookSecret(secret string) bool {
+	if len(secret) < 32 || len(secret) > 256 {
+		return false
+	}
+	for _, r := range secret {
+		if !(r >= 'A' && r <= 'Z' || r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '_' || r == '-') {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,75 @@
+package db
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/lsdpls/schulze_election_telegram_bot/internal/models"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// SetAdmin выдает роль пользователю, заменяя прежнюю
+func (s *Storage) SetAdmin(ctx context.Context, tx pgx.Tx, admin models.Admin) error {
+	_, err := tx.Exec(ctx,
+		`INSERT INTO admins (telegram_id, role, granted_by, granted_at) VALUES ($1, $2, $3, $4)
+		ON CONFLICT (telegram_id) DO UPDATE SET role = EXCLUDED.role, granted_by = EXCLUDED.granted_by, granted_at = EXCLUDED.granted_at`,
+		admin.TelegramID, admin.Role, admin.GrantedBy, admin.GrantedAt)
+	if err != nil {
+		return fmt.Errorf("SetAdmin: upsert failed: %w", err)
+	}
+	return nil
+}
+
+func (s *Storage) GetAdmin(ctx context.Context, tx pgx.Tx, telegramID int64) (*models.Admin, error) {
+	var admin models.Admin
+	err := tx.QueryRow(ctx,
+		"SELECT telegram_id, role, granted_by, granted_at FROM admins WHERE telegram_id = $1", telegramID).Scan(
+		&admin.TelegramID,
+		&admin.Role,
+		&admin.GrantedBy,
+		&admin.GrantedAt,
+	)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("GetAdmin: query failed: %w", err)
+	}
+	return &admin, nil
+}
+
+func (s *Storage) GetAllAdmins(ctx context.Context, tx pgx.Tx) ([]models.Admin, error) {
+	rows, err := tx.Query(ctx, "SELECT telegram_id, role, granted_by, granted_at FROM admins ORDER BY role, telegram_id")
+	if err != nil {
+		return nil, fmt.Errorf("GetAllAdmins: query failed: %w", err)
+	}
+	defer rows.Close()
+
+	var admins []models.Admin
+	for rows.Next() {
+		var admin models.Admin
+		if err := rows.Scan(
+			&admin.TelegramID,
+			&admin.Role,
+			&admin.GrantedBy,
+			&admin.GrantedAt,
+		); err != nil {
+			return nil, fmt.Errorf("GetAllAdmins: scan failed: %w", err)
+		}
+		admins = append(admins, admin)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("GetAllAdmins: rows iteration failed: %w", err)
+	}
+	return admins, nil
+}
+
+// DeleteAdmin отзывает роль. Возвращает false, если у пользователя не было роли
+func (s *Storage) DeleteAdmin(ctx context.Context, tx pgx.Tx, telegramID int64) (bool, error) {
+	tag, err := tx.Exec(ctx, "DELETE FROM admins WHERE telegram_id = $1", telegramID)
+	if err != nil {
+		return false, fmt.Errorf("DeleteAdmin: delete failed: %w", err)
+	}
+	return tag.RowsAffected() > 0, nil
+}
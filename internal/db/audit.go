@@ -0,0 +1,52 @@
+package db
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/lsdpls/schulze_election_telegram_bot/internal/models"
+
+	"github.com/jackc/pgx/v5"
+)
+
+func (s *Storage) AddAuditRecord(ctx context.Context, tx pgx.Tx, record models.AuditRecord) error {
+	_, err := tx.Exec(ctx,
+		"INSERT INTO audit_log (action, delegate_id, telegram_id, performed_by, details) VALUES ($1, $2, $3, $4, $5)",
+		record.Action, record.DelegateID, record.TelegramID, record.PerformedBy, record.Details)
+	if err != nil {
+		return fmt.Errorf("AddAuditRecord: insert failed: %w", err)
+	}
+	return nil
+}
+
+// GetAuditLog возвращает записи журнала делегата от старых к новым
+func (s *Storage) GetAuditLog(ctx context.Context, tx pgx.Tx, delegateID int) ([]models.AuditRecord, error) {
+	rows, err := tx.Query(ctx,
+		`SELECT id, action, delegate_id, telegram_id, performed_by, details, created_at
+		FROM audit_log WHERE delegate_id = $1 ORDER BY created_at, id`, delegateID)
+	if err != nil {
+		return nil, fmt.Errorf("GetAuditLog: query failed: %w", err)
+	}
+	defer rows.Close()
+
+	var records []models.AuditRecord
+	for rows.Next() {
+		var record models.AuditRecord
+		if err := rows.Scan(
+			&record.ID,
+			&record.Action,
+			&record.DelegateID,
+			&record.TelegramID,
+			&record.PerformedBy,
+			&record.Details,
+			&record.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("GetAuditLog: scan failed: %w", err)
+		}
+		records = append(records, record)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("GetAuditLog: %w", err)
+	}
+	return records, nil
+}
@@ -11,8 +11,8 @@ import (
 // Добавление кандидата
 func (s *Storage) AddCandidate(ctx context.Context, tx pgx.Tx, candidate models.Candidate) error {
 	_, err := tx.Exec(ctx,
-		"INSERT INTO candidates (candidate_id, name, course, description, is_eligible) VALUES ($1, $2, $3, $4, $5)",
-		candidate.CandidateID, candidate.Name, candidate.Course, candidate.Description, candidate.IsEligible)
+		"INSERT INTO candidates (candidate_id, name, course, description, is_eligible, photo_file_id, manifesto_url) VALUES ($1, $2, $3, $4, $5, $6, $7)",
+		candidate.CandidateID, candidate.Name, candidate.Course, candidate.Description, candidate.IsEligible, candidate.PhotoFileID, candidate.ManifestoURL)
 	if err != nil {
 		return fmt.Errorf("db.AddCandidate: insert failed: %w", err)
 	}
@@ -29,6 +29,8 @@ func (s *Storage) GetCandidateByCandidateID(ctx context.Context, tx pgx.Tx, cand
 		&candidate.Course,
 		&candidate.Description,
 		&candidate.IsEligible,
+		&candidate.PhotoFileID,
+		&candidate.ManifestoURL,
 	)
 	if err != nil {
 		if err == pgx.ErrNoRows {
@@ -54,6 +56,8 @@ func (s *Storage) GetAllCandidates(ctx context.Context, tx pgx.Tx) ([]models.Can
 			&candidate.Course,
 			&candidate.Description,
 			&candidate.IsEligible,
+			&candidate.PhotoFileID,
+			&candidate.ManifestoURL,
 		); err != nil {
 			return nil, fmt.Errorf("db.GetAllCandidates: %w", err)
 		}
@@ -79,6 +83,8 @@ func (s *Storage) GetAllEligibleCandidates(ctx context.Context, tx pgx.Tx) ([]mo
 			&candidate.Course,
 			&candidate.Description,
 			&candidate.IsEligible,
+			&candidate.PhotoFileID,
+			&candidate.ManifestoURL,
 		); err != nil {
 			return nil, fmt.Errorf("db.GetAllEligibleCandidates: %w", err)
 		}
@@ -93,8 +99,8 @@ func (s *Storage) GetAllEligibleCandidates(ctx context.Context, tx pgx.Tx) ([]mo
 // Обновление кандидата
 func (s *Storage) UpdateCandidate(ctx context.Context, tx pgx.Tx, candidate models.Candidate) error {
 	_, err := tx.Exec(ctx,
-		"UPDATE candidates SET name = $1, course = $2, description = $3, is_eligible = $4 WHERE candidate_id = $5",
-		candidate.Name, candidate.Course, candidate.Description, candidate.IsEligible, candidate.CandidateID)
+		"UPDATE candidates SET name = $1, course = $2, description = $3, is_eligible = $4, photo_file_id = $5, manifesto_url = $6 WHERE candidate_id = $7",
+		candidate.Name, candidate.Course, candidate.Description, candidate.IsEligible, candidate.PhotoFileID, candidate.ManifestoURL, candidate.CandidateID)
 	if err != nil {
 		return fmt.Errorf("db.UpdateCandidate: %w", err)
 	}
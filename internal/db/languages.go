@@ -0,0 +1,83 @@
+package db
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/lsdpls/schulze_election_telegram_bot/internal/models"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// SetUserLanguage сохраняет выбранный пользователем язык интерфейса
+func (s *Storage) SetUserLanguage(ctx context.Context, tx pgx.Tx, telegramID int64, language string) error {
+	_, err := tx.Exec(ctx,
+		`INSERT INTO user_languages (telegram_id, language, updated_at) VALUES ($1, $2, CURRENT_TIMESTAMP)
+		ON CONFLICT (telegram_id) DO UPDATE SET language = EXCLUDED.language, updated_at = EXCLUDED.updated_at`,
+		telegramID, language)
+	if err != nil {
+		return fmt.Errorf("SetUserLanguage: upsert failed: %w", err)
+	}
+	return nil
+}
+
+// GetUserLanguage возвращает выбранный пользователем язык или пустую строку, если язык не выбран
+func (s *Storage) GetUserLanguage(ctx context.Context, tx pgx.Tx, telegramID int64) (string, error) {
+	var language string
+	err := tx.QueryRow(ctx, "SELECT language FROM user_languages WHERE telegram_id = $1", telegramID).Scan(&language)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return "", nil
+		}
+		return "", fmt.Errorf("GetUserLanguage: query failed: %w", err)
+	}
+	return language, nil
+}
+
+// SetTextOverride сохраняет текст сообщения, заменяя прежний
+func (s *Storage) SetTextOverride(ctx context.Context, tx pgx.Tx, override models.TextOverride) error {
+	_, err := tx.Exec(ctx,
+		`INSERT INTO text_overrides (language, key, text, updated_by, updated_at) VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (language, key) DO UPDATE SET text = EXCLUDED.text, updated_by = EXCLUDED.updated_by, updated_at = EXCLUDED.updated_at`,
+		override.Language, override.Key, override.Text, override.UpdatedBy, override.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("SetTextOverride: upsert failed: %w", err)
+	}
+	return nil
+}
+
+func (s *Storage) GetAllTextOverrides(ctx context.Context, tx pgx.Tx) ([]models.TextOverride, error) {
+	rows, err := tx.Query(ctx, "SELECT language, key, text, updated_by, updated_at FROM text_overrides ORDER BY key, language")
+	if err != nil {
+		return nil, fmt.Errorf("GetAllTextOverrides: query failed: %w", err)
+	}
+	defer rows.Close()
+
+	var overrides []models.TextOverride
+	for rows.Next() {
+		var override models.TextOverride
+		if err := rows.Scan(
+			&override.Language,
+			&override.Key,
+			&override.Text,
+			&override.UpdatedBy,
+			&override.UpdatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("GetAllTextOverrides: scan failed: %w", err)
+		}
+		overrides = append(overrides, override)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("GetAllTextOverrides: rows iteration failed: %w", err)
+	}
+	return overrides, nil
+}
+
+// DeleteTextOverride возвращает исходный текст сообщения. Возвращает false, если текст не был переопределен
+func (s *Storage) DeleteTextOverride(ctx context.Context, tx pgx.Tx, language, key string) (bool, error) {
+	tag, err := tx.Exec(ctx, "DELETE FROM text_overrides WHERE language = $1 AND key = $2", language, key)
+	if err != nil {
+		return false, fmt.Errorf("DeleteTextOverride: delete failed: %w", err)
+	}
+	return tag.RowsAffected() > 0, nil
+}
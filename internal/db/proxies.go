@@ -0,0 +1,118 @@
+package db
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/lsdpls/schulze_election_telegram_bot/internal/models"
+
+	"github.com/jackc/pgx/v5"
+)
+
+const proxyColumns = "id, delegate_id, substitute_id, telegram_id, nominated_by, created_at, revoked_at, revoked_by"
+
+// Добавление доверенности, возвращает ее ID
+func (s *Storage) AddProxy(ctx context.Context, tx pgx.Tx, proxy models.Proxy) (int, error) {
+	var id int
+	err := tx.QueryRow(ctx,
+		"INSERT INTO proxies (delegate_id, substitute_id, nominated_by) VALUES ($1, $2, $3) RETURNING id",
+		proxy.DelegateID, proxy.SubstituteID, proxy.NominatedBy).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("AddProxy: insert failed: %w", err)
+	}
+	return id, nil
+}
+
+// Получение действующей доверенности делегата
+func (s *Storage) GetActiveProxyByDelegateID(ctx context.Context, tx pgx.Tx, delegateID int) (*models.Proxy, error) {
+	proxy, err := scanProxy(tx.QueryRow(ctx,
+		"SELECT "+proxyColumns+" FROM proxies WHERE delegate_id = $1 AND revoked_at IS NULL", delegateID))
+	if err != nil {
+		return nil, fmt.Errorf("GetActiveProxyByDelegateID: %w", err)
+	}
+	return proxy, nil
+}
+
+// Получение действующей доверенности по ID заместителя
+func (s *Storage) GetActiveProxyBySubstituteID(ctx context.Context, tx pgx.Tx, substituteID int) (*models.Proxy, error) {
+	proxy, err := scanProxy(tx.QueryRow(ctx,
+		"SELECT "+proxyColumns+" FROM proxies WHERE substitute_id = $1 AND revoked_at IS NULL", substituteID))
+	if err != nil {
+		return nil, fmt.Errorf("GetActiveProxyBySubstituteID: %w", err)
+	}
+	return proxy, nil
+}
+
+// Получение действующей доверенности по Telegram аккаунту заместителя
+func (s *Storage) GetActiveProxyByTelegramID(ctx context.Context, tx pgx.Tx, telegramID int64) (*models.Proxy, error) {
+	proxy, err := scanProxy(tx.QueryRow(ctx,
+		"SELECT "+proxyColumns+" FROM proxies WHERE telegram_id = $1 AND revoked_at IS NULL", telegramID))
+	if err != nil {
+		return nil, fmt.Errorf("GetActiveProxyByTelegramID: %w", err)
+	}
+	return proxy, nil
+}
+
+// Получение всех доверенностей, включая отозванные, от старых к новым
+func (s *Storage) GetAllProxies(ctx context.Context, tx pgx.Tx) ([]models.Proxy, error) {
+	rows, err := tx.Query(ctx, "SELECT "+proxyColumns+" FROM proxies ORDER BY created_at, id")
+	if err != nil {
+		return nil, fmt.Errorf("GetAllProxies: query failed: %w", err)
+	}
+	defer rows.Close()
+
+	var proxies []models.Proxy
+	for rows.Next() {
+		var proxy models.Proxy
+		if err := rows.Scan(
+			&proxy.ID,
+			&proxy.DelegateID,
+			&proxy.SubstituteID,
+			&proxy.TelegramID,
+			&proxy.NominatedBy,
+			&proxy.CreatedAt,
+			&proxy.RevokedAt,
+			&proxy.RevokedBy,
+		); err != nil {
+			return nil, fmt.Errorf("GetAllProxies: scan failed: %w", err)
+		}
+		proxies = append(proxies, proxy)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("GetAllProxies: rows error: %w", err)
+	}
+	return proxies, nil
+}
+
+// Обновление Telegram аккаунта заместителя и отметки об отзыве
+func (s *Storage) UpdateProxy(ctx context.Context, tx pgx.Tx, proxy models.Proxy) error {
+	_, err := tx.Exec(ctx,
+		"UPDATE proxies SET telegram_id = $1, revoked_at = $2, revoked_by = $3 WHERE id = $4",
+		proxy.TelegramID, proxy.RevokedAt, proxy.RevokedBy, proxy.ID)
+	if err != nil {
+		return fmt.Errorf("UpdateProxy: update failed: %w", err)
+	}
+	return nil
+}
+
+// scanProxy читает одну доверенность; nil, если строки нет
+func scanProxy(row pgx.Row) (*models.Proxy, error) {
+	var proxy models.Proxy
+	err := row.Scan(
+		&proxy.ID,
+		&proxy.DelegateID,
+		&proxy.SubstituteID,
+		&proxy.TelegramID,
+		&proxy.NominatedBy,
+		&proxy.CreatedAt,
+		&proxy.RevokedAt,
+		&proxy.RevokedBy,
+	)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("query failed: %w", err)
+	}
+	return &proxy, nil
+}
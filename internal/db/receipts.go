@@ -0,0 +1,52 @@
+package db
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/lsdpls/schulze_election_telegram_bot/internal/models"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// Сохранение подтвержденного бюллетеня: заменяет прежнее подтверждение делегата
+func (s *Storage) SetVoteReceipt(ctx context.Context, tx pgx.Tx, receipt models.VoteReceipt) error {
+	_, err := tx.Exec(ctx,
+		`INSERT INTO vote_receipts (delegate_id, candidate_rankings, telegram_id, confirmed_at) VALUES ($1, $2, $3, $4)
+		ON CONFLICT (delegate_id) DO UPDATE SET candidate_rankings = EXCLUDED.candidate_rankings,
+		telegram_id = EXCLUDED.telegram_id, confirmed_at = EXCLUDED.confirmed_at`,
+		receipt.DelegateID, receipt.CandidateRankings, receipt.TelegramID, receipt.ConfirmedAt)
+	if err != nil {
+		return fmt.Errorf("SetVoteReceipt: upsert failed: %w", err)
+	}
+	return nil
+}
+
+// Получение подтвержденного бюллетеня делегата
+func (s *Storage) GetVoteReceipt(ctx context.Context, tx pgx.Tx, delegateID int) (*models.VoteReceipt, error) {
+	var receipt models.VoteReceipt
+	err := tx.QueryRow(ctx,
+		"SELECT delegate_id, candidate_rankings, telegram_id, confirmed_at FROM vote_receipts WHERE delegate_id = $1",
+		delegateID).Scan(
+		&receipt.DelegateID,
+		&receipt.CandidateRankings,
+		&receipt.TelegramID,
+		&receipt.ConfirmedAt,
+	)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("GetVoteReceipt: query failed: %w", err)
+	}
+	return &receipt, nil
+}
+
+// Удаление подтвержденного бюллетеня вместе с голосом
+func (s *Storage) DeleteVoteReceipt(ctx context.Context, tx pgx.Tx, delegateID int) error {
+	_, err := tx.Exec(ctx, "DELETE FROM vote_receipts WHERE delegate_id = $1", delegateID)
+	if err != nil {
+		return fmt.Errorf("DeleteVoteReceipt: delete failed: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/lsdpls/schulze_election_telegram_bot/internal/models"
+
+	"github.com/jackc/pgx/v5"
+)
+
+func (s *Storage) AddReminder(ctx context.Context, tx pgx.Tx, reminder models.Reminder) (int, error) {
+	var id int
+	err := tx.QueryRow(ctx,
+		"INSERT INTO reminders (text, send_at, created_at) VALUES ($1, $2, $3) RETURNING id",
+		reminder.Text, reminder.SendAt, reminder.CreatedAt).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("AddReminder: insert failed: %w", err)
+	}
+	return id, nil
+}
+
+func (s *Storage) GetPendingReminders(ctx context.Context, tx pgx.Tx) ([]models.Reminder, error) {
+	rows, err := tx.Query(ctx,
+		"SELECT id, text, send_at, sent_at, delivered, blocked, failed, created_at FROM reminders WHERE sent_at IS NULL ORDER BY send_at")
+	if err != nil {
+		return nil, fmt.Errorf("GetPendingReminders: query failed: %w", err)
+	}
+	reminders, err := scanReminders(rows)
+	if err != nil {
+		return nil, fmt.Errorf("GetPendingReminders: %w", err)
+	}
+	return reminders, nil
+}
+
+// ClaimDueReminders отмечает наступившие напоминания начатыми и возвращает их, чтобы каждое было отправлено один раз
+func (s *Storage) ClaimDueReminders(ctx context.Context, tx pgx.Tx, now time.Time) ([]models.Reminder, error) {
+	rows, err := tx.Query(ctx,
+		`UPDATE reminders SET sent_at = $1 WHERE sent_at IS NULL AND send_at <= $1
+		RETURNING id, text, send_at, sent_at, delivered, blocked, failed, created_at`, now)
+	if err != nil {
+		return nil, fmt.Errorf("ClaimDueReminders: update failed: %w", err)
+	}
+	reminders, err := scanReminders(rows)
+	if err != nil {
+		return nil, fmt.Errorf("ClaimDueReminders: %w", err)
+	}
+	return reminders, nil
+}
+
+func (s *Storage) UpdateReminderReport(ctx context.Context, tx pgx.Tx, reminder models.Reminder) error {
+	_, err := tx.Exec(ctx,
+		"UPDATE reminders SET delivered = $1, blocked = $2, failed = $3 WHERE id = $4",
+		reminder.Delivered, reminder.Blocked, reminder.Failed, reminder.ID)
+	if err != nil {
+		return fmt.Errorf("UpdateReminderReport: update failed: %w", err)
+	}
+	return nil
+}
+
+// DeleteReminder удаляет еще не отправленное напоминание. Возвращает false, если такого нет
+func (s *Storage) DeleteReminder(ctx context.Context, tx pgx.Tx, reminderID int) (bool, error) {
+	tag, err := tx.Exec(ctx, "DELETE FROM reminders WHERE id = $1 AND sent_at IS NULL", reminderID)
+	if err != nil {
+		return false, fmt.Errorf("DeleteReminder: delete failed: %w", err)
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+func scanReminders(rows pgx.Rows) ([]models.Reminder, error) {
+	defer rows.Close()
+	var reminders []models.Reminder
+	for rows.Next() {
+		var reminder models.Reminder
+		if err := rows.Scan(
+			&reminder.ID,
+			&reminder.Text,
+			&reminder.SendAt,
+			&reminder.SentAt,
+			&reminder.Delivered,
+			&reminder.Blocked,
+			&reminder.Failed,
+			&reminder.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("scan failed: %w", err)
+		}
+		reminders = append(reminders, reminder)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration failed: %w", err)
+	}
+	return reminders, nil
+}
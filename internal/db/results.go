@@ -22,8 +22,8 @@ func (s *Storage) AddResult(ctx context.Context, tx pgx.Tx, result models.Result
 
 	// Вставка результатов в базу данных
 	_, err = tx.Exec(ctx,
-		"INSERT INTO results (course, winner_candidate_id, preferences, strongest_paths, stage) VALUES ($1, $2, $3, $4, $5)",
-		result.Course, result.WinnerCandidateID, preferencesJSON, strongestPathsJSON, result.Stage)
+		"INSERT INTO results (course, winner_candidate_id, preferences, strongest_paths, stage, computed_at) VALUES ($1, $2, $3, $4, $5, $6)",
+		result.Course, result.WinnerCandidateID, preferencesJSON, strongestPathsJSON, result.Stage, result.ComputedAt)
 	if err != nil {
 		return fmt.Errorf("AddResult: insert failed: %w", err)
 	}
@@ -40,6 +40,7 @@ func (s *Storage) GetResultByCourse(ctx context.Context, tx pgx.Tx, course strin
 		&preferencesJSON,    // Считываем JSON как строку
 		&strongestPathsJSON, // Считываем JSON как строку
 		&result.Stage,
+		&result.ComputedAt,
 	)
 	if err != nil {
 		if err == pgx.ErrNoRows {
@@ -75,6 +76,7 @@ func (s *Storage) GetAllResults(ctx context.Context, tx pgx.Tx) ([]models.Result
 			&preferencesJSON,    // Считываем JSON как строку
 			&strongestPathsJSON, // Считываем JSON как строку
 			&result.Stage,
+			&result.ComputedAt,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("GetAllResults: scan failed: %w", err)
@@ -107,8 +109,8 @@ func (s *Storage) UpdateResult(ctx context.Context, tx pgx.Tx, result models.Res
 
 	// Обновление результатов в базе данных
 	_, err = tx.Exec(ctx,
-		"UPDATE results SET winner_candidate_id = $1, preferences = $2, strongest_paths = $3, stage = $4 WHERE course = $5",
-		result.WinnerCandidateID, preferencesJSON, strongestPathsJSON, result.Stage, result.Course)
+		"UPDATE results SET winner_candidate_id = $1, preferences = $2, strongest_paths = $3, stage = $4, computed_at = $5 WHERE course = $6",
+		result.WinnerCandidateID, preferencesJSON, strongestPathsJSON, result.Stage, result.ComputedAt, result.Course)
 	if err != nil {
 		return fmt.Errorf("UpdateResult: update failed: %w", err)
 	}
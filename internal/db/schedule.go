@@ -0,0 +1,97 @@
+package db
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/lsdpls/schulze_election_telegram_bot/internal/models"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// SetScheduleWindow создает или заменяет окно расписания. Отметки планировщика при этом сбрасываются
+func (s *Storage) SetScheduleWindow(ctx context.Context, tx pgx.Tx, window models.ScheduleWindow) error {
+	_, err := tx.Exec(ctx,
+		`INSERT INTO election_schedule (kind, starts_at, ends_at, timezone, opened_at, closed_at, announcements, updated_at)
+		VALUES ($1, $2, $3, $4, NULL, NULL, '{}', $5)
+		ON CONFLICT (kind) DO UPDATE SET starts_at = EXCLUDED.starts_at, ends_at = EXCLUDED.ends_at, timezone = EXCLUDED.timezone,
+		opened_at = NULL, closed_at = NULL, announcements = '{}', updated_at = EXCLUDED.updated_at`,
+		window.Kind, window.StartsAt, window.EndsAt, window.Timezone, window.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("SetScheduleWindow: upsert failed: %w", err)
+	}
+	return nil
+}
+
+func (s *Storage) GetScheduleWindow(ctx context.Context, tx pgx.Tx, kind string) (*models.ScheduleWindow, error) {
+	var window models.ScheduleWindow
+	err := tx.QueryRow(ctx,
+		"SELECT kind, starts_at, ends_at, timezone, opened_at, closed_at, announcements, updated_at FROM election_schedule WHERE kind = $1",
+		kind).Scan(
+		&window.Kind,
+		&window.StartsAt,
+		&window.EndsAt,
+		&window.Timezone,
+		&window.OpenedAt,
+		&window.ClosedAt,
+		&window.Announcements,
+		&window.UpdatedAt,
+	)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("GetScheduleWindow: query failed: %w", err)
+	}
+	return &window, nil
+}
+
+func (s *Storage) GetAllScheduleWindows(ctx context.Context, tx pgx.Tx) ([]models.ScheduleWindow, error) {
+	rows, err := tx.Query(ctx,
+		"SELECT kind, starts_at, ends_at, timezone, opened_at, closed_at, announcements, updated_at FROM election_schedule ORDER BY starts_at")
+	if err != nil {
+		return nil, fmt.Errorf("GetAllScheduleWindows: query failed: %w", err)
+	}
+	defer rows.Close()
+
+	var windows []models.ScheduleWindow
+	for rows.Next() {
+		var window models.ScheduleWindow
+		if err := rows.Scan(
+			&window.Kind,
+			&window.StartsAt,
+			&window.EndsAt,
+			&window.Timezone,
+			&window.OpenedAt,
+			&window.ClosedAt,
+			&window.Announcements,
+			&window.UpdatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("GetAllScheduleWindows: scan failed: %w", err)
+		}
+		windows = append(windows, window)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("GetAllScheduleWindows: rows iteration failed: %w", err)
+	}
+	return windows, nil
+}
+
+// UpdateScheduleWindowProgress сохраняет отметки планировщика: открытие, закрытие и отправленные объявления
+func (s *Storage) UpdateScheduleWindowProgress(ctx context.Context, tx pgx.Tx, window models.ScheduleWindow) error {
+	_, err := tx.Exec(ctx,
+		"UPDATE election_schedule SET opened_at = $1, closed_at = $2, announcements = $3, updated_at = $4 WHERE kind = $5",
+		window.OpenedAt, window.ClosedAt, window.Announcements, window.UpdatedAt, window.Kind)
+	if err != nil {
+		return fmt.Errorf("UpdateScheduleWindowProgress: update failed: %w", err)
+	}
+	return nil
+}
+
+func (s *Storage) DeleteScheduleWindow(ctx context.Context, tx pgx.Tx, kind string) error {
+	_, err := tx.Exec(ctx, "DELETE FROM election_schedule WHERE kind = $1", kind)
+	if err != nil {
+		return fmt.Errorf("DeleteScheduleWindow: delete failed: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+package db
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/lsdpls/schulze_election_telegram_bot/internal/models"
+
+	"github.com/jackc/pgx/v5"
+)
+
+func (s *Storage) SetSession(ctx context.Context, tx pgx.Tx, session models.Session) error {
+	_, err := tx.Exec(ctx,
+		`INSERT INTO sessions (key, value, expires_at, updated_at) VALUES ($1, $2, $3, $4)
+		ON CONFLICT (key) DO UPDATE SET value = EXCLUDED.value, expires_at = EXCLUDED.expires_at, updated_at = EXCLUDED.updated_at`,
+		session.Key, session.Value, session.ExpiresAt, session.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("SetSession: upsert failed: %w", err)
+	}
+	return nil
+}
+
+// GetSession возвращает сессию, если она существует и не истекла
+func (s *Storage) GetSession(ctx context.Context, tx pgx.Tx, key string) (*models.Session, error) {
+	var session models.Session
+	err := tx.QueryRow(ctx,
+		"SELECT key, value, expires_at, updated_at FROM sessions WHERE key = $1 AND (expires_at IS NULL OR expires_at > NOW())",
+		key).Scan(
+		&session.Key,
+		&session.Value,
+		&session.ExpiresAt,
+		&session.UpdatedAt,
+	)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("GetSession: query failed: %w", err)
+	}
+	return &session, nil
+}
+
+// TakeSession удаляет сессию и возвращает ее, если она существовала и не истекла.
+// Из параллельных запросов сессию получает только один
+func (s *Storage) TakeSession(ctx context.Context, tx pgx.Tx, key string) (*models.Session, error) {
+	var session models.Session
+	err := tx.QueryRow(ctx,
+		`DELETE FROM sessions WHERE key = $1 AND (expires_at IS NULL OR expires_at > NOW())
+		RETURNING key, value, expires_at, updated_at`,
+		key).Scan(
+		&session.Key,
+		&session.Value,
+		&session.ExpiresAt,
+		&session.UpdatedAt,
+	)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("TakeSession: delete failed: %w", err)
+	}
+	return &session, nil
+}
+
+func (s *Storage) DeleteSession(ctx context.Context, tx pgx.Tx, key string) error {
+	_, err := tx.Exec(ctx, "DELETE FROM sessions WHERE key = $1", key)
+	if err != nil {
+		return fmt.Errorf("DeleteSession: delete failed: %w", err)
+	}
+	return nil
+}
+
+func (s *Storage) DeleteExpiredSessions(ctx context.Context, tx pgx.Tx) (int64, error) {
+	tag, err := tx.Exec(ctx, "DELETE FROM sessions WHERE expires_at IS NOT NULL AND expires_at <= NOW()")
+	if err != nil {
+		return 0, fmt.Errorf("DeleteExpiredSessions: delete failed: %w", err)
+	}
+	return tag.RowsAffected(), nil
+}
@@ -11,8 +11,8 @@ import (
 // Добавление голоса
 func (s *Storage) AddVote(ctx context.Context, tx pgx.Tx, vote models.Vote) error {
 	_, err := tx.Exec(ctx,
-		"INSERT INTO votes (delegate_id, candidate_rankings, created_at) VALUES ($1, $2, $3)",
-		vote.DelegateID, vote.CandidateRankings, vote.CreatedAt)
+		"INSERT INTO votes (delegate_id, candidate_rankings, created_at, proxy_id) VALUES ($1, $2, $3, $4)",
+		vote.DelegateID, vote.CandidateRankings, vote.CreatedAt, vote.ProxyID)
 	if err != nil {
 		return fmt.Errorf("AddVote: insert failed: %w", err)
 	}
@@ -23,11 +23,12 @@ func (s *Storage) AddVote(ctx context.Context, tx pgx.Tx, vote models.Vote) erro
 // Получение голоса по ID делегата
 func (s *Storage) GetVoteByDelegateID(ctx context.Context, tx pgx.Tx, delegateID int) (*models.Vote, error) {
 	var vote models.Vote
-	err := tx.QueryRow(ctx, "SELECT * FROM votes WHERE delegate_id = $1", delegateID).Scan(
+	err := tx.QueryRow(ctx, "SELECT id, delegate_id, candidate_rankings, created_at, proxy_id FROM votes WHERE delegate_id = $1", delegateID).Scan(
 		&vote.ID,
 		&vote.DelegateID,
 		&vote.CandidateRankings,
 		&vote.CreatedAt,
+		&vote.ProxyID,
 	)
 	if err != nil {
 		if err == pgx.ErrNoRows {
@@ -40,7 +41,7 @@ func (s *Storage) GetVoteByDelegateID(ctx context.Context, tx pgx.Tx, delegateID
 
 // Получение всех голосов
 func (s *Storage) GetAllVotes(ctx context.Context, tx pgx.Tx) ([]models.Vote, error) {
-	rows, err := tx.Query(ctx, "SELECT * FROM votes")
+	rows, err := tx.Query(ctx, "SELECT id, delegate_id, candidate_rankings, created_at, proxy_id FROM votes")
 	if err != nil {
 		return nil, fmt.Errorf("GetAllVotes: query failed: %w", err)
 	}
@@ -54,6 +55,7 @@ func (s *Storage) GetAllVotes(ctx context.Context, tx pgx.Tx) ([]models.Vote, er
 			&vote.DelegateID,
 			&vote.CandidateRankings,
 			&vote.CreatedAt,
+			&vote.ProxyID,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("GetAllVotes: scan failed: %w", err)
@@ -69,8 +71,8 @@ func (s *Storage) GetAllVotes(ctx context.Context, tx pgx.Tx) ([]models.Vote, er
 // Обновление голоса
 func (s *Storage) UpdateVote(ctx context.Context, tx pgx.Tx, vote models.Vote) error {
 	_, err := tx.Exec(ctx,
-		"UPDATE votes SET candidate_rankings = $1, created_at = $2 WHERE delegate_id = $3",
-		vote.CandidateRankings, vote.CreatedAt, vote.DelegateID)
+		"UPDATE votes SET candidate_rankings = $1, created_at = $2, proxy_id = $3 WHERE delegate_id = $4",
+		vote.CandidateRankings, vote.CreatedAt, vote.ProxyID, vote.DelegateID)
 	if err != nil {
 		return fmt.Errorf("UpdateVote: update failed: %w", err)
 	}
@@ -0,0 +1,118 @@
+// Package dispatcher обрабатывает обновления Telegram ограниченным числом обработчиков.
+// Обновления одного пользователя обрабатываются строго по порядку, разных пользователей — параллельно
+package dispatcher
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// Размер очереди одного обработчика
+const queueSize = 100
+
+// Время на обработку одного обновления
+const handleTimeout = 10 * time.Second
+
+// ErrClosed возвращается при попытке передать обновление остановленному диспетчеру
+var ErrClosed = errors.New("dispatcher is closed")
+
+type job struct {
+	ctx    context.Context
+	update tgbotapi.Update
+	done   func() // Вызывается после обработки, может быть nil
+}
+
+// Dispatcher распределяет обновления по обработчикам по ID пользователя: все обновления пользователя
+// попадают в одну очередь, поэтому не обгоняют друг друга
+type Dispatcher struct {
+	queues  []chan job
+	handler func(ctx context.Context, update tgbotapi.Update)
+
+	mu     sync.RWMutex // Защищает queues от закрытия во время Dispatch
+	closed bool
+	wg     sync.WaitGroup
+}
+
+// New запускает workers обработчиков. handler вызывается с контекстом, у которого есть таймаут,
+// но нет отмены контекста Dispatch: ответ вебхуку не прерывает обработку
+func New(workers int, handler func(ctx context.Context, update tgbotapi.Update)) *Dispatcher {
+	if workers < 1 {
+		workers = 1
+	}
+	d := &Dispatcher{queues: make([]chan job, workers), handler: handler}
+	for i := range d.queues {
+		d.queues[i] = make(chan job, queueSize)
+		d.wg.Add(1)
+		go d.work(d.queues[i])
+	}
+	return d
+}
+
+// Dispatch ставит обновление в очередь его пользователя. Если очередь заполнена, Dispatch ждет места
+// до отмены ctx
+func (d *Dispatcher) Dispatch(ctx context.Context, update tgbotapi.Update) error {
+	return d.DispatchNotify(ctx, update, nil)
+}
+
+// DispatchNotify ставит обновление в очередь, как Dispatch, и вызывает done, когда обработчик закончил
+// с обновлением. Если обновление не принято в очередь, done не вызывается
+func (d *Dispatcher) DispatchNotify(ctx context.Context, update tgbotapi.Update, done func()) error {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	if d.closed {
+		return fmt.Errorf("dispatcher.DispatchNotify: %w", ErrClosed)
+	}
+	queue := d.queues[shard(updateKey(update), len(d.queues))]
+	select {
+	case queue <- job{ctx: context.WithoutCancel(ctx), update: update, done: done}:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("dispatcher.DispatchNotify: %w", ctx.Err())
+	}
+}
+
+// Close перестает принимать обновления и дожидается обработки уже поставленных в очередь
+func (d *Dispatcher) Close() {
+	d.mu.Lock()
+	if !d.closed {
+		d.closed = true
+		for _, queue := range d.queues {
+			close(queue)
+		}
+	}
+	d.mu.Unlock()
+	d.wg.Wait()
+}
+
+func (d *Dispatcher) work(queue <-chan job) {
+	defer d.wg.Done()
+	for j := range queue {
+		ctx, cancel := context.WithTimeout(j.ctx, handleTimeout)
+		d.handler(ctx, j.update)
+		cancel()
+		if j.done != nil {
+			j.done()
+		}
+	}
+}
+
+// updateKey возвращает ID пользователя, отправившего обновление, или ID чата для обновлений без отправителя
+func updateKey(update tgbotapi.Update) int64 {
+	if from := update.SentFrom(); from != nil {
+		return from.ID
+	}
+	if chat := update.FromChat(); chat != nil {
+		return chat.ID
+	}
+	return 0
+}
+
+// shard возвращает номер очереди для ключа
+func shard(key int64, n int) int {
+	return int(uint64(key) % uint64(n))
+}
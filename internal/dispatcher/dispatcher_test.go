@@ -0,0 +1,91 @@
+package dispatcher
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func messageFrom(userID int64, updateID int) tgbotapi.Update {
+	return tgbotapi.Update{UpdateID: updateID, Message: &tgbotapi.Message{
+		From: &tgbotapi.User{ID: userID},
+		Chat: &tgbotapi.Chat{ID: userID},
+	}}
+}
+
+func TestDispatcherOrdersUpdatesPerUser(t *testing.T) {
+	t.Parallel()
+
+	var mu sync.Mutex
+	handled := make(map[int64][]int)
+	d := New(4, func(_ context.Context, update tgbotapi.Update) {
+		// Случайные задержки не должны менять порядок обновлений пользователя
+		time.Sleep(time.Duration(update.UpdateID%3) * time.Millisecond)
+		mu.Lock()
+		defer mu.Unlock()
+		userID := update.Message.From.ID
+		handled[userID] = append(handled[userID], update.UpdateID)
+	})
+
+	want := make(map[int64][]int)
+	for i := 0; i < 50; i++ {
+		for userID := int64(1); userID <= 6; userID++ {
+			require.NoError(t, d.Dispatch(context.Background(), messageFrom(userID, i)))
+			want[userID] = append(want[userID], i)
+		}
+	}
+	d.Close()
+	assert.Equal(t, want, handled)
+	assert.ErrorIs(t, d.Dispatch(context.Background(), messageFrom(1, 50)), ErrClosed)
+}
+
+func TestDispatcherProcessesUsersInParallel(t *testing.T) {
+	t.Parallel()
+
+	// Обработчик первого пользователя ждет, пока не будет обработано обновление второго
+	release := make(chan struct{})
+	var handled atomic.Int32
+	d := New(2, func(_ context.Context, update tgbotapi.Update) {
+		if update.Message.From.ID == 2 {
+			<-release
+		} else {
+			close(release)
+		}
+		handled.Add(1)
+	})
+	require.NoError(t, d.Dispatch(context.Background(), messageFrom(2, 1)))
+	require.NoError(t, d.Dispatch(context.Background(), messageFrom(1, 2)))
+	d.Close()
+	assert.Equal(t, int32(2), handled.Load())
+}
+
+func TestDispatcherHandlerOutlivesDispatchContext(t *testing.T) {
+	t.Parallel()
+
+	done := make(chan error, 1)
+	d := New(1, func(ctx context.Context, _ tgbotapi.Update) {
+		done <- ctx.Err()
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	require.NoError(t, d.Dispatch(ctx, messageFrom(1, 1)))
+	cancel()
+	d.Close()
+	assert.NoError(t, <-done)
+}
+
+func TestShard(t *testing.T) {
+	t.Parallel()
+
+	for _, key := range []int64{0, 1, 7, -1001234567890} {
+		n := shard(key, 4)
+		assert.GreaterOrEqual(t, n, 0)
+		assert.Less(t, n, 4)
+		assert.Equal(t, n, shard(key, 4))
+	}
+}
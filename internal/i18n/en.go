@@ -0,0 +1,408 @@
+package i18n
+
+// en — тексты сообщений на английском языке. Формы множественного числа: #one, #other
+var en = map[string]string{
+	// Общие сообщения
+	"error.generic":           "Something went wrong. Please try again",
+	"error.delegate_check":    "Failed to check the delegate. Please try again",
+	"command.unknown":         "Unknown command",
+	"admin.forbidden":         "You are not allowed to use this command",
+	"admin.unknown_command":   "Unknown admin command",
+	"text.hint":               "Use /start to register and /vote to vote",
+	"help.header":             "Available commands:",
+	"command.start":           "register",
+	"command.vote":            "vote",
+	"command.verify":          "check your recorded ballot",
+	"command.candidates":      "view candidate profiles",
+	"command.language":        "choose the language",
+	"command.proxy":           "hand your vote to a substitute or view your proxy",
+	"command.revoke_proxy":    "revoke your proxy and vote yourself",
+	"command.help":            "show available commands",
+	"command.time_format":     "DD.MM.YYYY HH:MM",
+	"command.missing_arg":     "Missing argument <%s>.\nUsage: %s",
+	"command.invalid_arg":     "Invalid value of <%s>: %s.\nUsage: %s",
+	"command.extra_args":      "Too many arguments. Separate arguments with commas.\nUsage: %s",
+	"command.arg.id":          "a six-digit number is expected",
+	"command.arg.telegram_id": "a numeric Telegram ID is expected",
+	"command.arg.int":         "an integer is expected",
+	"command.arg.time":        "a time in the DD.MM.YYYY HH:MM format is expected",
+	"command.arg.url":         "a link starting with http:// or https:// is expected",
+	"command.arg.course":      "a course is expected: 1-4 бакалавриат or 1-2 магистратура",
+	"command.arg.choice":      "allowed values: %s",
+	"language.choose":         "Choose your language",
+	"language.changed":        "Interface language: English",
+
+	// Регистрация
+	"start.already_registered":            "You are already registered! Use /vote to vote",
+	"start.welcome":                       "Welcome to the voting bot for the AMCP Student Council election!\n\nYou need to register before voting.\n<b>Please enter your st email (format: stXXXXXX)</b>",
+	"registration.invalid_email":          "Invalid email format. Please enter your email as stXXXXXX.",
+	"registration.invalid_delegate_id":    "Could not get the delegate ID from the email. Please try again",
+	"registration.delegate_not_found":     "Delegate not found. Make sure you entered the correct email.",
+	"registration.already_verified":       "A delegate with this email is already registered",
+	"registration.send_code_failed":       "Failed to send the code to your email. Please try again",
+	"registration.resend_cooldown#one":    "The code has already been sent. You can request a new one in %d second.",
+	"registration.resend_cooldown#other":  "The code has already been sent. You can request a new one in %d seconds.",
+	"registration.email_cooldown":         "A code was recently sent to this email. Please wait and try again.",
+	"registration.code_sent#one":          "A confirmation code has been sent to your email. Please enter the code.\nThe code is valid for %d minute. If you can't find the email, check your spam folder or contact the organizers",
+	"registration.code_sent#other":        "A confirmation code has been sent to your email. Please enter the code.\nThe code is valid for %d minutes. If you can't find the email, check your spam folder or contact the organizers",
+	"registration.invalid_code_format":    "Invalid code format. Please enter the numeric code.",
+	"registration.code_check_failed":      "Failed to check the code. Please try again",
+	"registration.code_not_found":         "No confirmation code found. Please start the registration again.",
+	"registration.code_expired":           "The code has expired. Enter your email again to get a new code.",
+	"registration.verification_failed":    "Verification failed. Please try again",
+	"registration.completed":              "Registration completed! Use /vote to vote",
+	"registration.vote_transferred":       "The vote you cast from your previous account has been kept. Your new vote verification token:\n\n🔑 <code>%s</code>",
+	"registration.account_locked":         "Too many wrong codes. Registration is locked, please contact the organizers.",
+	"registration.code_attempts_exceeded": "Too many attempts for this code. Enter your email again to get a new code.",
+	"registration.wrong_code#one":         "Wrong code. %d attempt left.",
+	"registration.wrong_code#other":       "Wrong code. %d attempts left.",
+	"registration.locked":                 "Registration is locked after too many wrong codes. Please contact the organizers.",
+
+	// Голосование
+	"vote.closed":                    "Voting has already ended or has not started yet",
+	"vote.registration_check_failed": "Failed to check your registration. Please try again",
+	"vote.not_registered":            "You are not registered! Use /start to register",
+	"vote.help":                      "The Schulze method asks you to build a ranked list of candidates in which <b>every candidate must be ranked</b> relative to the others.\nFor example, if you think candidate A is better than candidate B, put candidate A higher in the list.\n\nIn this bot you pick the candidates one by one, from the most preferred to the least preferred.\nUse the buttons under the ballot message to pick each next candidate.\n\nImportant:\n• You must rank <b>all candidates</b>.\n• Make sure your ballot is accepted: <b>you will receive a confirmation message</b>.\n• You can change your ballot at any time before voting ends.\n• Do not pick the next candidate until the ballot message has changed.\n• Use ⬆️ and ⬇️ to change the order, «Undo last» to remove the last pick and «Clear» to start over.\n• Before submitting, the bot will show the final order for review.",
+	"vote.candidates_header":         "Candidates:",
+	"vote.save_failed":               "Failed to record your vote. Please try again",
+	"vote.accepted":                  "<b>Your ballot has been accepted✅</b>\n\nYou can change your ballot before voting ends by voting again with /vote\n\n🔑 <code>%s</code>",
+
+	// Бюллетень
+	"ballot.header":         "Pick all candidates from the most to the least preferred:",
+	"ballot.create_failed":  "Failed to create the ballot. Please try again",
+	"ballot.undo":           "↩️ Undo last",
+	"ballot.clear":          "🗑 Clear",
+	"ballot.review":         "✅ Review and submit",
+	"ballot.button_failed":  "Failed to process the button. Please try again",
+	"ballot.outdated":       "This ballot has already been submitted or is outdated. Use /vote to get a new ballot.",
+	"ballot.superseded":     "This ballot has been replaced by a newer one. Use the latest ballot or /vote.",
+	"ballot.stale_press":    "The ballot has just been updated, press the button again",
+	"ballot.undone":         "Last pick undone",
+	"ballot.cleared":        "Ballot cleared",
+	"ballot.incomplete":     "Rank all candidates",
+	"ballot.already_ranked": "The candidate is already on the ballot",
+	"ballot.ranked":         "Candidate added",
+	"ballot.edit":           "✏️ Edit",
+	"ballot.submit":         "📨 Submit",
+	"ballot.review_header":  "Review your ballot before submitting:",
+	"ballot.review_footer":  "If the order is correct, press «Submit». To change the order, press «Edit».",
+	"ballot.final_header":   "Your final ballot:",
+
+	// Профили кандидатов
+	"candidates.load_failed":      "Failed to load the candidates. Please try again",
+	"candidates.empty":            "There are no candidates yet",
+	"candidates.choose_course":    "Choose a year to see the candidates",
+	"candidates.choose_candidate": "Choose a candidate to open the profile",
+	"candidates.page":             "Page %d of %d",
+	"candidates.back":             "⬅️ Back to years",
+	"candidates.not_found":        "Candidate not found",
+	"candidates.manifesto":        "Candidate's manifesto",
+	"course.bachelor":             "Bachelor's, year %d",
+	"course.master":               "Master's, year %d",
+
+	// Mini App
+	"webapp.bad_request":     "Bad request",
+	"webapp.unauthorized":    "Could not verify your Telegram account. Please reopen the ballot",
+	"webapp.expired":         "The session has expired. Please reopen the ballot",
+	"webapp.rate_limited":    "Too many requests. Please try again later",
+	"webapp.invalid_ranking": "Rank every candidate exactly once",
+	"webapp.open":            "🗳 Open the ballot",
+	"webapp.button_text":     "It is easier to rank candidates by drag and drop in the ballot app. The ballot with buttons below still works.",
+
+	// Расписание
+	"schedule.in_24h":                       "24 hours",
+	"schedule.in_1h":                        "1 hour",
+	"schedule.in_10m":                       "10 minutes",
+	"schedule.countdown_start.registration": "⏳ Registration opens in %s. Start: %s",
+	"schedule.countdown_start.voting":       "⏳ Voting opens in %s. Start: %s",
+	"schedule.opened.registration":          "✅ Registration is open until %s",
+	"schedule.opened.voting":                "✅ Voting is open until %s\nUse /vote to vote",
+	"schedule.countdown_end.registration":   "⏳ Registration closes in %s. End: %s",
+	"schedule.countdown_end.voting":         "⏳ Voting closes in %s. End: %s\nIf you have not voted yet, use /vote",
+	"schedule.closed.registration":          "🏁 Registration is closed",
+	"schedule.closed.voting":                "🏁 Voting is closed",
+	"schedule.not_started.registration":     "Registration has not started yet. Start: %s",
+	"schedule.not_started.voting":           "Voting has not started yet. Start: %s",
+	"schedule.ended.registration":           "Registration is closed",
+	"schedule.ended.voting":                 "Voting is closed",
+	"schedule.none":                         "No schedule set",
+	"schedule.header":                       "Schedule:",
+	"schedule.status.pending":               "pending",
+	"schedule.status.open":                  "open",
+	"schedule.status.closed":                "closed",
+	"schedule.line.registration":            "Registration: %s — %s, %s",
+	"schedule.line.voting":                  "Voting: %s — %s, %s",
+
+	// Напоминания
+	"reminder.default":       "Reminder: voting is open and your vote has not been recorded yet. Use /vote to vote",
+	"reminder.sent#one":      "Reminder sent to %d delegate\ndelivered: %d, blocked the bot: %d, errors: %d",
+	"reminder.sent#other":    "Reminder sent to %d delegates\ndelivered: %d, blocked the bot: %d, errors: %d",
+	"reminders.none":         "No reminders scheduled",
+	"reminders.header":       "Scheduled reminders:",
+	"reminders.default_text": "default text",
+
+	// Импорт списков
+	"list.more":                  "… and %d more",
+	"import.help":                "Send the bot a CSV or XLSX file with the delegate and candidate lists.\n\nThe first row is a header with the columns: type, id, name, group, course, description.\ntype is delegate or candidate; id is a six-digit number (stXXXXXX is accepted);\ndelegates require a group (XX.БXX-пу), candidates require a course (for example, 1 бакалавриат).\n\nThe bot shows who will be added, changed and removed, and applies the changes only after confirmation. Delegates or candidates missing from the file are removed if the file has at least one row of that type.",
+	"import.forbidden":           "You are not allowed to import lists",
+	"import.unsupported_format":  "Only CSV and XLSX files are supported. File format: /import",
+	"import.too_large":           "The file is too large",
+	"import.download_failed":     "Could not download the file. Please try again",
+	"import.read_failed":         "Could not read the file. Check the format: /import",
+	"import.errors":              "The file was not imported, errors found: %d",
+	"import.no_changes":          "No changes",
+	"import.apply":               "✅ Apply",
+	"import.cancel":              "❌ Cancel",
+	"import.expired":             "The import has expired or was already processed. Send the file again",
+	"import.cancelled":           "Import cancelled",
+	"import.failed":              "The import was not applied, changes rolled back: %s\nCheck the file and send it again",
+	"import.applied":             "✅ Import applied",
+	"roster.empty_file":          "the file is empty",
+	"roster.missing_column":      "the header has no %s column",
+	"roster.row_error":           "row %d: %s",
+	"roster.unknown_type":        "unknown type %q (delegate or candidate)",
+	"roster.invalid_id":          "invalid ID %q, a six-digit number is expected",
+	"roster.missing_name":        "the name is missing",
+	"roster.invalid_group":       "invalid group %q, the XX.БXX-пу or XX.МXX-пу format is expected",
+	"roster.duplicate_delegate":  "delegate st%06d is already listed in row %d",
+	"roster.duplicate_group":     "group %s is already listed in row %d",
+	"roster.invalid_course":      "invalid course %q, for example «1 бакалавриат» or «2 магистратура» is expected",
+	"roster.duplicate_candidate": "candidate st%06d is already listed in row %d",
+	"roster.no_rows":             "the file has no delegate or candidate rows",
+	"roster.add_delegate":        "+ delegate st%06d, %s, %s",
+	"roster.update_delegate":     "~ delegate st%06d: %s, %s → %s, %s",
+	"roster.delete_delegate":     "- delegate st%06d, %s, %s",
+	"roster.voted_delegate_kept": "delegate st%06d, %s is missing from the file but has already voted and will not be removed",
+	"roster.add_candidate":       "+ candidate st%06d, %s, %s",
+	"roster.update_candidate":    "~ candidate st%06d: %s, %s → %s, %s",
+	"roster.delete_candidate":    "- candidate st%06d, %s, %s",
+	"roster.preview":             "<b>Import preview</b> (changes are not applied yet)\nDelegates: add %d, change %d, remove %d\nCandidates: add %d, change %d, remove %d",
+
+	// Администрирование
+	"admin.help_header":                "Your role: %s\nAvailable commands:",
+	"admin.help_footer":                "Always separate command arguments with commas when there are several of them",
+	"admin.command.add_delegate":       "add a delegate",
+	"admin.command.delete_delegate":    "remove a delegate",
+	"admin.command.reset_registration": "unlink a delegate's Telegram account so they can register again; discard deletes their vote",
+	"admin.command.audit":              "history of a delegate's Telegram account links",
+	"admin.command.set_proxy":          "nominate a substitute for a delegate",
+	"admin.command.cancel_proxy":       "revoke a delegate's proxy",
+	"admin.command.proxies":            "list proxies, including revoked ones",
+	"admin.command.edit_delegate":      "change a delegate's name or group; without a field, shows a card with buttons",
+	"admin.command.add_candidate":      "add a candidate",
+	"admin.command.ban_candidate":      "ban a candidate",
+	"admin.command.unban_candidate":    "allow a banned candidate again (only outside voting)",
+	"admin.command.edit_candidate":     "change a candidate's name, course or description; without a field, shows a card with buttons",
+	"admin.command.set_photo":          "set a candidate photo: send as a reply to a message with a photo",
+	"admin.command.set_manifesto":      "set or remove the link to a candidate's manifesto",
+	"admin.command.delete_candidate":   "remove a candidate",
+	"admin.command.unlock":             "lift the email verification lockout",
+	"admin.command.show_delegates":     "show the delegate list",
+	"admin.command.show_candidates":    "show the candidate list",
+	"admin.command.show_votes":         "show the vote list (observers and operators see only the counts)",
+	"admin.command.turnout":            "show turnout",
+	"admin.command.start_voting":       "start voting",
+	"admin.command.stop_voting":        "stop voting",
+	"admin.command.schedule":           "show the schedule or schedule registration or voting",
+	"admin.command.cancel_schedule":    "cancel a schedule",
+	"admin.command.remind":             "remind delegates who have not voted",
+	"admin.command.remind_at":          "schedule a reminder",
+	"admin.command.reminders":          "show scheduled reminders",
+	"admin.command.cancel_reminder":    "cancel a reminder",
+	"admin.command.results":            "calculate the election results",
+	"admin.command.print":              "print the election results",
+	"admin.command.csv":                "save the results to a CSV file",
+	"admin.command.xlsx":               "export the results and delegate lists to Excel",
+	"admin.command.protocol":           "generate the PDF voting protocol",
+	"admin.command.import":             "import delegates and candidates from a CSV or XLSX file: send the file to the bot",
+	"admin.command.grant":              "grant a role",
+	"admin.command.revoke":             "revoke a role",
+	"admin.command.admins":             "show the administrator list",
+	"admin.command.log":                "set the log level (Debug, Info, Warn, Error)",
+	"admin.command.send_logs":          "send the log file",
+	"admin.command.texts":              "show overridden message texts",
+	"admin.command.text":               "show a message text in all languages",
+	"admin.command.set_text":           "override a message text",
+	"admin.command.reset_text":         "restore the original message text",
+	"admin.delegates_header":           "Delegates:",
+	"admin.candidates_header":          "Candidates:",
+	"admin.votes_header":               "Votes:",
+	"admin.vote_counts":                "Delegates: %d\nRegistered: %d\nVoted: %d",
+	"role.superadmin":                  "superadmin",
+	"role.operator":                    "operator",
+	"role.observer":                    "observer",
+	"role.tally_officer":               "tally officer",
+	"admins.none":                      "No roles granted. All commands are available only in the admin chat",
+	"admins.header":                    "Administrators:",
+	"admins.line":                      "• <a href=\"tg://user?id=%d\">%d</a>, %s, since %s",
+
+	// Тексты сообщений
+	"texts.override":       "Overridden: <code>%s</code>",
+	"texts.none":           "No overridden texts",
+	"texts.header":         "<b>Overridden texts:</b>",
+	"texts.unknown_key":    "Unknown message key <code>%s</code>. Keys are listed in internal/i18n",
+	"texts.invalid":        "Text not saved: %s",
+	"texts.saved":          "✅ Text <code>%s</code> (%s) changed",
+	"texts.not_overridden": "Text <code>%s</code> (%s) is not overridden",
+	"texts.reset":          "✅ Text <code>%s</code> (%s) reset to the original",
+
+	// Явка
+	"turnout.header":       "Turnout",
+	"turnout.registered":   "Registered: %d of %d (%d%%)",
+	"turnout.voted":        "Voted: %d of %d registered (%d%%)",
+	"turnout.by_year":      "By year of admission:",
+	"turnout.year":         "• %s: registered %d of %d, voted %d (%d%%)",
+	"turnout.unknown_year": "no group",
+	"turnout.finished":     "Voting is closed",
+	"turnout.updated":      "Updated: %s",
+
+	// Rate limits
+	"ratelimit.commands":         "Too many commands. Please try again in %s.",
+	"ratelimit.callbacks":        "Too many button presses. Please try again in %s.",
+	"ratelimit.emails":           "Too many code requests. You can request a new code in %s.",
+	"ratelimit.codes":            "Too many code attempts. Please try again in %s.",
+	"ratelimit.seconds#one":      "%d second",
+	"ratelimit.seconds#other":    "%d seconds",
+	"ratelimit.minutes#one":      "%d minute",
+	"ratelimit.minutes#other":    "%d minutes",
+	"ratelimit.action.commands":  "commands",
+	"ratelimit.action.callbacks": "button presses",
+	"ratelimit.action.emails":    "email code requests",
+	"ratelimit.action.codes":     "code attempts",
+	"ratelimit.abuse":            "⚠️ Possible flood: <a href=\"tg://user?id=%d\">%d</a> keeps exceeding rate limits (%s). Requests over the limit are rejected",
+
+	// Confirmation of destructive actions
+	"confirm.delete_delegate":         "Delete delegate st%06d (%s, %s)?",
+	"confirm.delegate_registered":     "Telegram: <a href=\"tg://user?id=%d\">%d</a>",
+	"confirm.delegate_not_registered": "No Telegram account linked",
+	"confirm.delegate_has_vote":       "⚠️ The delegate has already voted: the vote will be deleted permanently",
+	"confirm.delegate_no_vote":        "The delegate has not voted yet",
+	"confirm.delete_candidate":        "Delete candidate st%06d (%s, %s)?",
+	"confirm.candidate_ballots#one":   "⚠️ The candidate is ranked on %d ballot",
+	"confirm.candidate_ballots#other": "⚠️ The candidate is ranked on %d ballots",
+	"confirm.candidate_no_ballots":    "The candidate is not ranked on any ballot",
+	"confirm.expires#one":             "Please confirm within %d minute.",
+	"confirm.expires#other":           "Please confirm within %d minutes.",
+	"confirm.yes":                     "✅ Confirm",
+	"confirm.no":                      "❌ Cancel",
+	"confirm.expired":                 "This confirmation has expired or was already handled. Please send the command again",
+	"confirm.cancelled":               "Action cancelled",
+	"confirm.failed":                  "The action failed. Please try again",
+	"confirm.state_changed":           "The data changed after the confirmation was requested, so the action was not performed. Send the command again to see the current consequences",
+	"admin.delegate_not_found":        "Delegate st%06d not found",
+	"admin.delegate_deleted":          "✅ Delegate st%06d deleted",
+	"admin.candidate_not_found":       "Candidate st%06d not found",
+	"admin.candidate_deleted":         "✅ Candidate st%06d deleted",
+	"admin.delegate_added":            "✅ Delegate st%06d added",
+	"admin.candidate_added":           "✅ Candidate st%06d added",
+	"admin.candidate_banned":          "✅ Candidate st%06d banned",
+	"admin.photo_reply_required":      "Send /set_photo &lt;candidate_id&gt; as a reply to a message with a photo",
+	"admin.photo_saved":               "✅ Photo of candidate st%06d saved",
+	"admin.manifesto_saved":           "✅ Manifesto link of candidate st%06d saved",
+	"admin.manifesto_removed":         "✅ Manifesto link of candidate st%06d removed",
+	"admin.unlocked":                  "✅ Verification lock of user %d removed",
+	"admin.voting_started":            "✅ Voting is open",
+	"admin.voting_stopped":            "🏁 Voting is closed",
+	"admin.results_computed":          "✅ Results computed",
+	"admin.results_failed":            "Failed to compute the results. See the logs for details",
+	"admin.export_failed":             "Failed to build the file. See the logs for details",
+	"admin.role_granted":              "✅ User %d was granted the %s role",
+	"admin.role_revoked":              "✅ Role of user %d revoked",
+	"admin.role_missing":              "User %d has no role",
+	"schedule.unknown_timezone":       "Unknown time zone %s",
+	"schedule.ends_before_start":      "The end time must be later than the start time",
+	"schedule.ends_in_past":           "The end time has already passed",
+	"schedule.saved.registration":     "✅ Registration period scheduled: %s — %s",
+	"schedule.saved.voting":           "✅ Voting period scheduled: %s — %s",
+	"schedule.cancelled.registration": "✅ Registration period schedule cancelled",
+	"schedule.cancelled.voting":       "✅ Voting period schedule cancelled",
+	"reminder.in_past":                "The reminder time has already passed",
+	"reminder.scheduled":              "✅ Reminder %d scheduled for %s",
+	"reminder.cancelled":              "✅ Reminder %d cancelled",
+	"reminder.not_found":              "Reminder %d not found or already sent",
+
+	// Editing delegates and candidates
+	"edit.delegate_header":            "<b>Delegate st%06d</b>",
+	"edit.candidate_header":           "<b>Candidate st%06d</b>",
+	"edit.field_value":                "%s: %s",
+	"edit.field.name":                 "Name",
+	"edit.field.group":                "Group",
+	"edit.field.course":               "Course",
+	"edit.field.description":          "Description",
+	"edit.choose_field":               "Choose the field to change:",
+	"edit.cancel":                     "❌ Cancel",
+	"edit.cancelled":                  "Editing cancelled",
+	"edit.prompt":                     "Send the new value of «%s» as a reply to this message.\nCurrent value: %s",
+	"edit.invalid_value":              "Invalid value of «%s». %s\nSend another value as a reply to the bot's message",
+	"edit.hint.delegate.name":         "The name can't be empty.",
+	"edit.hint.delegate.group":        "Group format: XX.БXX-пу or XX.МXX-пу.",
+	"edit.hint.candidate.name":        "The name can't be empty.",
+	"edit.hint.candidate.course":      "Course: 1-4 бакалавриат or 1-2 магистратура.",
+	"edit.hint.candidate.description": "The description can't be empty.",
+	"edit.locked_during_voting":       "«%s» can't be changed while voting is open",
+	"edit.failed":                     "Failed to save the change. Please try again",
+	"edit.done":                       "✅ st%06d: «%s» changed: %s → %s",
+	"edit.unban_during_voting":        "Candidates can't be allowed while voting is open: the list of candidates on ballots doesn't change",
+	"edit.unbanned":                   "✅ Candidate st%06d is allowed to run again",
+
+	// Registration reset
+	"reset.not_registered":            "Delegate st%06d hasn't linked a Telegram account yet",
+	"reset.discard_closed":            "The vote can't be deleted while voting is closed: it would change the results. Reset the registration without discard",
+	"reset.summary":                   "Unlink the Telegram account of delegate st%06d (%s, %s)?",
+	"reset.vote_keep":                 "The vote will be kept and moved to the new account; the vote verification token will change",
+	"reset.vote_discard":              "⚠️ The delegate's vote will be deleted: they will have to vote again",
+	"reset.next_steps":                "After the reset the delegate can register again with /start from any account.",
+	"reset.notice":                    "An administrator has unlinked this Telegram account from the delegate. If this is a mistake, please contact the organizers",
+	"reset.done":                      "✅ Registration of delegate st%06d reset",
+	"reset.done_vote_discarded":       "✅ Registration of delegate st%06d reset, vote deleted",
+	"audit.none":                      "No records for delegate st%06d",
+	"audit.header":                    "<b>History of delegate st%06d:</b>",
+	"audit.action.verification":       "verification",
+	"audit.action.reset_registration": "registration reset",
+	"audit.account":                   "account <a href=\"tg://user?id=%d\">%d</a>",
+	"audit.details.vote_kept":         "vote kept",
+	"audit.details.vote_discarded":    "vote deleted",
+	"audit.details.no_vote":           "no vote",
+	"audit.performed_by":              "by <a href=\"tg://user?id=%d\">%d</a>",
+	"admin.vote_by_proxy":             "(by proxy)",
+	"audit.action.proxy_nominated":    "substitute nominated",
+	"audit.action.proxy_verified":     "substitute verified email",
+	"audit.action.proxy_revoked":      "proxy revoked",
+	"audit.action.proxy_voted":        "substitute voted",
+	"audit.substitute":                "substitute st%06d",
+	"proxy.delegates_only":            "Only a registered delegate can hand over their vote. Use /start to register",
+	"proxy.none":                      "You have no substitute. To hand over your vote, send /proxy with the substitute's ID — the six digits of their email, for example: /proxy 123456\nYour substitute should be another student of your group who is not a delegate. The bot knows the groups of delegates only and cannot check this, so choosing the substitute is up to you. The substitute verifies their email with /start and votes on your behalf.",
+	"proxy.status_pending":            "Your substitute is st%06d. They have not verified their email yet. While the proxy is active, only the substitute votes. Revoke the proxy: /revoke_proxy",
+	"proxy.status_verified":           "Your substitute is st%06d, email verified. While the proxy is active, only the substitute votes. Revoke the proxy: /revoke_proxy",
+	"proxy.self":                      "You cannot nominate yourself as a substitute",
+	"proxy.substitute_is_delegate":    "st%06d is a delegate and votes on their own. A substitute must be a student who is not a delegate",
+	"proxy.already_exists":            "Delegate st%06d already has a substitute. Revoke that proxy first",
+	"proxy.substitute_busy":           "st%06d already substitutes for another delegate",
+	"proxy.nominated":                 "✅ st%06d is now the substitute of delegate st%06d. The substitute should send /start to the bot and verify the email st%06d. While the proxy is active, only the substitute votes",
+	"proxy.no_active":                 "Delegate st%06d has no active proxy",
+	"proxy.revoked":                   "✅ The proxy of substitute st%06d is revoked, delegate st%06d votes on their own again. A vote already cast by the substitute is kept until the delegate votes again",
+	"proxy.notice_nominated":          "An administrator nominated st%06d as your substitute. While the proxy is active, only the substitute votes. Details: /proxy",
+	"proxy.notice_verified":           "Substitute st%06d verified their email and can vote on your behalf",
+	"proxy.notice_revoked":            "An administrator revoked your proxy: you vote on your own again",
+	"proxy.substitute_revoked":        "The proxy of delegate st%06d is revoked: you can no longer vote on their behalf",
+	"proxy.header":                    "<b>Proxies:</b>",
+	"proxy.list_none":                 "No proxies",
+	"proxy.line":                      "• st%06d → st%06d, nominated %s",
+	"proxy.line_pending":              "email not verified",
+	"proxy.line_revoked":              "revoked %s",
+	"registration.proxy_completed":    "✅ Email verified. You are the substitute of delegate st%06d and vote on their behalf: /vote",
+	"registration.proxy_revoked":      "The proxy was revoked, substitute registration is cancelled",
+	"vote.proxy_active":               "You handed your vote to substitute st%06d, so they vote. To vote yourself, revoke the proxy: /revoke_proxy",
+	"vote.as_proxy":                   "You are voting by proxy on behalf of delegate st%06d",
+	"verify.no_vote":                  "Your ballot has not been recorded yet. Use /vote to vote",
+	"verify.no_vote_delegated":        "No ballot has been recorded yet. Your substitute st%06d votes for you",
+	"verify.missing":                  "⚠️ The bot confirmed a ballot on %s, but no vote is recorded now. Please contact the organizers",
+	"verify.cast_by_substitute":       "The vote of delegate st%06d was cast by a substitute by proxy. The substitute can check the ballot with /verify",
+	"verify.cast_by_delegate":         "The vote of delegate st%06d was cast by the delegate. The delegate can check the ballot with /verify",
+	"verify.ballot":                   "<b>Recorded ballot</b> (cast %s):",
+	"verify.token":                    "🔑 <code>%s</code>",
+	"verify.matches":                  "✅ Matches the ballot the bot confirmed on %s. Use the token to find your vote in the published list",
+	"verify.mismatch":                 "⚠️ The recorded ballot differs from the ballot the bot confirmed on %s. Please contact the organizers. Confirmed ballot:",
+	"verify.unconfirmed":              "ℹ️ No confirmation was saved for this ballot: it was cast before ballot checks were introduced or the ballot accepted message was not delivered. Compare the order with the ballot you submitted",
+	"verify.confirmed_by_other":       "ℹ️ The bot sent the ballot confirmation to another account: 🔑 <code>%s</code>",
+}
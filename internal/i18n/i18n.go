@@ -0,0 +1,255 @@
+// Package i18n содержит каталог сообщений бота на русском и английском языках
+package i18n
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// Поддерживаемые языки
+const (
+	Russian = "ru"
+	English = "en"
+
+	// DefaultLanguage используется, если язык пользователя неизвестен
+	DefaultLanguage = Russian
+)
+
+// Languages — поддерживаемые языки в порядке вывода
+var Languages = []string{Russian, English}
+
+// LanguageNames — названия языков на самих языках
+var LanguageNames = map[string]string{
+	Russian: "Русский",
+	English: "English",
+}
+
+// Разделитель ключа сообщения и формы множественного числа: "votes#one", "votes#few", "votes#many", "votes#other"
+const pluralSeparator = "#"
+
+// Языки Telegram, для которых понятнее русский интерфейс
+var russianSpeaking = []string{"ru", "uk", "be", "kk"}
+
+var placeholderRe = regexp.MustCompile(`%(?:\[\d+\])?[-+# 0]*\d*(?:\.\d+)?[a-zA-Z%]`)
+
+// Теги HTML, которые Telegram поддерживает в сообщениях с ParseMode HTML
+var markupTags = []string{"b", "strong", "i", "em", "u", "ins", "s", "strike", "del", "span", "tg-spoiler", "a",
+	"code", "pre", "blockquote", "tg-emoji"}
+
+// Сообщения, которые бот отправляет без HTML разметки: подсказки по командам с угловыми скобками
+// и сообщения с клавиатурой. Разметка их текстов не проверяется
+var plainTextPrefixes = []string{"command.", "help.", "admin.command.", "admin.help_", "admin.unknown_command",
+	"text.hint", "language.choose", "ballot.header", "candidates.choose_course", "import.help"}
+
+var (
+	tagRe    = regexp.MustCompile(`^<(/?)([a-zA-Z][a-zA-Z0-9-]*)(?:\s[^<>]*)?>`)
+	entityRe = regexp.MustCompile(`^&(?:lt|gt|amp|quot|#\d+|#x[0-9a-fA-F]+);`)
+)
+
+// Catalog хранит тексты сообщений и переопределения администраторов
+type Catalog struct {
+	mu        sync.RWMutex
+	bundles   map[string]map[string]string // язык -> ключ -> текст
+	overrides map[string]map[string]string // язык -> ключ -> текст, заданный администратором
+}
+
+// NewCatalog создает каталог из наборов текстов по языкам
+func NewCatalog(bundles map[string]map[string]string) *Catalog {
+	return &Catalog{
+		bundles:   bundles,
+		overrides: make(map[string]map[string]string),
+	}
+}
+
+var defaultCatalog = NewCatalog(map[string]map[string]string{
+	Russian: ru,
+	English: en,
+})
+
+// T возвращает текст сообщения key на языке lang, подставляя args
+func T(lang, key string, args ...any) string { return defaultCatalog.T(lang, key, args...) }
+
+// N возвращает текст сообщения key в форме множественного числа для n, подставляя args
+func N(lang, key string, n int, args ...any) string { return defaultCatalog.N(lang, key, n, args...) }
+
+// SetOverrides заменяет переопределения текстов в каталоге по умолчанию
+func SetOverrides(overrides map[string]map[string]string) { defaultCatalog.SetOverrides(overrides) }
+
+// Base возвращает исходный текст сообщения из каталога по умолчанию
+func Base(lang, key string) (string, bool) { return defaultCatalog.Base(lang, key) }
+
+// Validate проверяет переопределение текста для каталога по умолчанию
+func Validate(lang, key, text string) error { return defaultCatalog.Validate(lang, key, text) }
+
+// T возвращает текст сообщения key на языке lang. Если текста нет, используется язык по умолчанию, затем сам ключ
+func (c *Catalog) T(lang, key string, args ...any) string {
+	text, ok := c.lookup(lang, key)
+	if !ok {
+		return key
+	}
+	if len(args) == 0 {
+		return text
+	}
+	return fmt.Sprintf(text, args...)
+}
+
+// N возвращает текст сообщения key в форме множественного числа, соответствующей n
+func (c *Catalog) N(lang, key string, n int, args ...any) string {
+	for _, form := range []string{pluralForm(lang, n), "other", "many"} {
+		if _, ok := c.lookup(lang, key+pluralSeparator+form); ok {
+			return c.T(lang, key+pluralSeparator+form, args...)
+		}
+	}
+	return key
+}
+
+// lookup ищет текст: переопределение, исходный текст, затем то же на языке по умолчанию
+func (c *Catalog) lookup(lang, key string) (string, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	for _, l := range []string{lang, DefaultLanguage} {
+		if text, ok := c.overrides[l][key]; ok {
+			return text, true
+		}
+		if text, ok := c.bundles[l][key]; ok {
+			return text, true
+		}
+	}
+	return "", false
+}
+
+// SetOverrides заменяет все переопределения текстов
+func (c *Catalog) SetOverrides(overrides map[string]map[string]string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.overrides = overrides
+}
+
+// Base возвращает исходный текст сообщения без учета переопределений
+func (c *Catalog) Base(lang, key string) (string, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	text, ok := c.bundles[lang][key]
+	return text, ok
+}
+
+// Validate проверяет, что переопределение относится к существующему сообщению, содержит те же подстановки
+// (%s, %d, ...) в том же порядке, что и исходный текст, и корректную HTML разметку
+func (c *Catalog) Validate(lang, key, text string) error {
+	if !IsSupported(lang) {
+		return fmt.Errorf("unknown language %q", lang)
+	}
+	base, ok := c.Base(lang, key)
+	if !ok {
+		return fmt.Errorf("unknown message key %q", key)
+	}
+	if strings.TrimSpace(text) == "" {
+		return fmt.Errorf("empty text")
+	}
+	want, got := placeholders(base), placeholders(text)
+	if !slices.Equal(want, got) {
+		return fmt.Errorf("placeholders mismatch: want %v, got %v", want, got)
+	}
+	if err := validateMarkup(text); err != nil && !isPlainText(key) {
+		return fmt.Errorf("invalid markup: %w", err)
+	}
+	return nil
+}
+
+// isPlainText проверяет, что сообщение отправляется без HTML разметки
+func isPlainText(key string) bool {
+	return slices.ContainsFunc(plainTextPrefixes, func(prefix string) bool { return strings.HasPrefix(key, prefix) })
+}
+
+// validateMarkup проверяет разметку так, как ее разбирает Telegram: только поддерживаемые теги,
+// закрытые в обратном порядке, символы < и & экранированы. Иначе Telegram не отправит сообщение
+func validateMarkup(text string) error {
+	var open []string
+	for i := 0; i < len(text); i++ {
+		switch text[i] {
+		case '<':
+			match := tagRe.FindStringSubmatch(text[i:])
+			if match == nil {
+				return fmt.Errorf("unescaped < at byte %d", i)
+			}
+			name := strings.ToLower(match[2])
+			if !slices.Contains(markupTags, name) {
+				return fmt.Errorf("unsupported tag <%s>", name)
+			}
+			if match[1] == "/" {
+				if len(open) == 0 || open[len(open)-1] != name {
+					return fmt.Errorf("unexpected </%s>", name)
+				}
+				open = open[:len(open)-1]
+			} else {
+				open = append(open, name)
+			}
+			i += len(match[0]) - 1
+		case '&':
+			if !entityRe.MatchString(text[i:]) {
+				return fmt.Errorf("unescaped & at byte %d", i)
+			}
+		}
+	}
+	if len(open) > 0 {
+		return fmt.Errorf("unclosed <%s>", open[len(open)-1])
+	}
+	return nil
+}
+
+// placeholders возвращает подстановки fmt в тексте, кроме %%
+func placeholders(text string) []string {
+	var result []string
+	for _, placeholder := range placeholderRe.FindAllString(text, -1) {
+		if placeholder != "%%" {
+			result = append(result, placeholder)
+		}
+	}
+	return result
+}
+
+// pluralForm возвращает форму множественного числа для n: one, few, many (русский) или one, other (английский)
+func pluralForm(lang string, n int) string {
+	if n < 0 {
+		n = -n
+	}
+	if lang != Russian {
+		if n == 1 {
+			return "one"
+		}
+		return "other"
+	}
+	switch {
+	case n%10 == 1 && n%100 != 11:
+		return "one"
+	case n%10 >= 2 && n%10 <= 4 && (n%100 < 12 || n%100 > 14):
+		return "few"
+	default:
+		return "many"
+	}
+}
+
+// IsSupported проверяет, что язык поддерживается
+func IsSupported(lang string) bool {
+	return slices.Contains(Languages, lang)
+}
+
+// Match подбирает язык интерфейса по language_code Telegram или заголовку Accept-Language.
+// Русскоязычным и пользователям без языка — русский, остальным — английский
+func Match(languageCode string) string {
+	code := strings.ToLower(strings.TrimSpace(languageCode))
+	if code == "" {
+		return DefaultLanguage
+	}
+	code, _, _ = strings.Cut(code, ",")
+	code, _, _ = strings.Cut(code, ";")
+	code, _, _ = strings.Cut(code, "-")
+	code, _, _ = strings.Cut(code, "_")
+	if slices.Contains(russianSpeaking, code) {
+		return Russian
+	}
+	return English
+}
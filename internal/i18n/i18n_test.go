@@ -0,0 +1,188 @@
+package i18n
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPluralForm(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		lang string
+		n    int
+		want string
+	}{
+		{Russian, 1, "one"},
+		{Russian, 21, "one"},
+		{Russian, 2, "few"},
+		{Russian, 24, "few"},
+		{Russian, 5, "many"},
+		{Russian, 11, "many"},
+		{Russian, 12, "many"},
+		{Russian, 111, "many"},
+		{Russian, 0, "many"},
+		{English, 1, "one"},
+		{English, 0, "other"},
+		{English, 21, "other"},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, pluralForm(tt.lang, tt.n), "%s %d", tt.lang, tt.n)
+	}
+}
+
+func TestMatch(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]string{
+		"":                        Russian,
+		"ru":                      Russian,
+		"uk":                      Russian,
+		"en":                      English,
+		"en-US":                   English,
+		"pt_BR":                   English,
+		"ru-RU,ru;q=0.9,en;q=0.8": Russian,
+		"de-DE,de;q=0.9":          English,
+	}
+	for code, want := range tests {
+		assert.Equal(t, want, Match(code), code)
+	}
+}
+
+func TestCatalog(t *testing.T) {
+	t.Parallel()
+
+	catalog := NewCatalog(map[string]map[string]string{
+		Russian: {
+			"greeting":   "Привет, %s",
+			"only_ru":    "Только по-русски",
+			"votes#one":  "%d голос",
+			"votes#few":  "%d голоса",
+			"votes#many": "%d голосов",
+			"percent":    "100%%",
+		},
+		English: {
+			"greeting":    "Hello, %s",
+			"votes#one":   "%d vote",
+			"votes#other": "%d votes",
+		},
+	})
+
+	assert.Equal(t, "Hello, Ann", catalog.T(English, "greeting", "Ann"))
+	assert.Equal(t, "Только по-русски", catalog.T(English, "only_ru"), "fallback to the default language")
+	assert.Equal(t, "missing", catalog.T(English, "missing"), "fallback to the key")
+	assert.Equal(t, "100%%", catalog.T(Russian, "percent"), "no formatting without args")
+
+	assert.Equal(t, "22 голоса", catalog.N(Russian, "votes", 22, 22))
+	assert.Equal(t, "25 голосов", catalog.N(Russian, "votes", 25, 25))
+	assert.Equal(t, "1 vote", catalog.N(English, "votes", 1, 1))
+	assert.Equal(t, "3 votes", catalog.N(English, "votes", 3, 3))
+
+	catalog.SetOverrides(map[string]map[string]string{English: {"greeting": "Hi, %s!"}})
+	assert.Equal(t, "Hi, Ann!", catalog.T(English, "greeting", "Ann"))
+	base, ok := catalog.Base(English, "greeting")
+	assert.True(t, ok)
+	assert.Equal(t, "Hello, %s", base)
+}
+
+func TestValidate(t *testing.T) {
+	t.Parallel()
+
+	catalog := NewCatalog(map[string]map[string]string{
+		Russian: {"greeting": "Привет, %s! У вас %d голосов"},
+	})
+
+	assert.NoError(t, catalog.Validate(Russian, "greeting", "Здравствуйте, %s. Голосов: %d"))
+	assert.Error(t, catalog.Validate("de", "greeting", "Hallo, %s %d"), "unsupported language")
+	assert.Error(t, catalog.Validate(Russian, "missing", "Текст"), "unknown key")
+	assert.Error(t, catalog.Validate(Russian, "greeting", "  "), "empty text")
+	assert.Error(t, catalog.Validate(Russian, "greeting", "Здравствуйте, %s"), "missing placeholder")
+	assert.Error(t, catalog.Validate(Russian, "greeting", "Голосов: %d, %s"), "reordered placeholders")
+}
+
+func TestValidateMarkup(t *testing.T) {
+	t.Parallel()
+
+	valid := []string{
+		"Текст без разметки",
+		"<b>Жирный</b> и <i>курсив</i>",
+		"<b><a href=\"tg://user?id=%d\">%d</a></b>",
+		"<code>&lt;candidate_id&gt;</code> &amp; &quot;текст&quot; &#128512;",
+		"1 > 0",
+	}
+	for _, text := range valid {
+		assert.NoError(t, validateMarkup(text), text)
+	}
+
+	invalid := []string{
+		"<b>Незакрытый тег",
+		"<b><i>Неверный порядок</b></i>",
+		"Лишний </b>",
+		"<h1>Заголовок</h1>",
+		"/set_photo <candidate_id>",
+		"1 < 2",
+		"Rock & Roll",
+	}
+	for _, text := range invalid {
+		assert.Error(t, validateMarkup(text), text)
+	}
+
+	catalog := NewCatalog(map[string]map[string]string{
+		Russian: {"greeting": "<b>Привет</b>, %s"},
+	})
+	assert.NoError(t, catalog.Validate(Russian, "greeting", "<i>Здравствуйте</i>, %s"))
+	assert.Error(t, catalog.Validate(Russian, "greeting", "<b>Здравствуйте, %s"), "unclosed tag")
+
+	// Подсказки по командам отправляются без разметки
+	catalog = NewCatalog(map[string]map[string]string{
+		Russian: {"command.missing_arg": "Не указан аргумент <%s>"},
+	})
+	assert.NoError(t, catalog.Validate(Russian, "command.missing_arg", "Аргумент <%s> не указан"))
+}
+
+// Каждое сообщение должно быть переведено, формы множественного числа — заданы полностью
+func TestBundles(t *testing.T) {
+	t.Parallel()
+
+	pluralForms := map[string][]string{
+		Russian: {"one", "few", "many"},
+		English: {"one", "other"},
+	}
+	plurals := make(map[string]map[string]bool) // язык -> ключ без формы
+	for lang, bundle := range map[string]map[string]string{Russian: ru, English: en} {
+		plurals[lang] = make(map[string]bool)
+		for key, text := range bundle {
+			assert.Equal(t, strings.TrimSpace(text), text, "%s %s: leading or trailing whitespace", lang, key)
+			if !isPlainText(key) {
+				assert.NoError(t, validateMarkup(text), "%s %s: invalid markup", lang, key)
+			}
+			if base, form, ok := strings.Cut(key, pluralSeparator); ok {
+				assert.Contains(t, pluralForms[lang], form, "%s %s", lang, key)
+				plurals[lang][base] = true
+			}
+		}
+		for base := range plurals[lang] {
+			for _, form := range pluralForms[lang] {
+				assert.Contains(t, bundle, base+pluralSeparator+form, "%s: incomplete plural forms", lang)
+			}
+		}
+	}
+	require.Equal(t, plurals[Russian], plurals[English], "plural messages differ between languages")
+
+	for key, text := range ru {
+		if strings.Contains(key, pluralSeparator) {
+			continue
+		}
+		if assert.Contains(t, en, key, "no English translation") {
+			assert.Equal(t, placeholders(text), placeholders(en[key]), "%s: placeholders differ", key)
+		}
+	}
+	for key := range en {
+		if !strings.Contains(key, pluralSeparator) {
+			assert.Contains(t, ru, key, "no Russian text")
+		}
+	}
+}
@@ -0,0 +1,416 @@
+package i18n
+
+// ru — тексты сообщений на русском языке. Формы множественного числа: #one, #few, #many
+var ru = map[string]string{
+	// Общие сообщения
+	"error.generic":           "Произошла ошибка. Пожалуйста, попробуйте снова",
+	"error.delegate_check":    "Произошла ошибка при проверке делегата. Пожалуйста, попробуйте снова",
+	"command.unknown":         "Неизвестная команда",
+	"admin.forbidden":         "Недостаточно прав для этой команды",
+	"admin.unknown_command":   "Неизвестная администрирующая команда",
+	"text.hint":               "Используйте /start для регистрации и /vote для голосования",
+	"help.header":             "Список доступных команд:",
+	"command.start":           "начать регистрацию",
+	"command.vote":            "начать голосование",
+	"command.verify":          "проверить записанный бюллетень",
+	"command.candidates":      "посмотреть профили кандидатов",
+	"command.language":        "выбрать язык",
+	"command.proxy":           "передать голос заместителю или посмотреть доверенность",
+	"command.revoke_proxy":    "отозвать доверенность и голосовать самому",
+	"command.help":            "показать список доступных команд",
+	"command.time_format":     "ДД.ММ.ГГГГ ЧЧ:ММ",
+	"command.missing_arg":     "Не указан аргумент <%s>.\nИспользование: %s",
+	"command.invalid_arg":     "Неверное значение аргумента <%s>: %s.\nИспользование: %s",
+	"command.extra_args":      "Слишком много аргументов. Разделяйте аргументы запятыми.\nИспользование: %s",
+	"command.arg.id":          "нужно шестизначное число",
+	"command.arg.telegram_id": "нужен числовой Telegram ID",
+	"command.arg.int":         "нужно целое число",
+	"command.arg.time":        "нужно время в формате ДД.ММ.ГГГГ ЧЧ:ММ",
+	"command.arg.url":         "нужна ссылка, начинающаяся с http:// или https://",
+	"command.arg.course":      "нужен курс: 1-4 бакалавриат или 1-2 магистратура",
+	"command.arg.choice":      "допустимые значения: %s",
+	"language.choose":         "Выберите язык",
+	"language.changed":        "Язык интерфейса: русский",
+
+	// Регистрация
+	"start.already_registered":            "Вы уже зарегистрированы! Используйте команду /vote для голосования",
+	"start.welcome":                       "Добро пожаловать в бот для голосования на выборах в Студенческий совет ПМ-ПУ!\n\nЧтобы начать голосование, необходимо пройти регистрацию.\n<b>Пожалуйста, введите свою st почту (в формате: stXXXXXX)</b>",
+	"registration.invalid_email":          "Неверный формат почты. Пожалуйста, введите почту в формате stXXXXXX.",
+	"registration.invalid_delegate_id":    "Невозможно получить ID делегата из почты. Пожалуйста, попробуйте снова",
+	"registration.delegate_not_found":     "Такой делегат не найден. Убедитесь, что вы ввели правильную почту.",
+	"registration.already_verified":       "Делегат с такой почтой уже зарегистрировался",
+	"registration.send_code_failed":       "Произошла ошибка при отправке кода на почту. Пожалуйста, попробуйте снова",
+	"registration.resend_cooldown#one":    "Код уже отправлен. Повторно запросить код можно через %d секунду.",
+	"registration.resend_cooldown#few":    "Код уже отправлен. Повторно запросить код можно через %d секунды.",
+	"registration.resend_cooldown#many":   "Код уже отправлен. Повторно запросить код можно через %d секунд.",
+	"registration.email_cooldown":         "На эту почту недавно уже был отправлен код. Пожалуйста, подождите и попробуйте снова.",
+	"registration.code_sent#one":          "Код подтверждения отправлен на ваш email. Пожалуйста, введите код.\nКод действует %d минуту. Если Вы не видите письмо - проверьте Спам или обратитесь к организаторам",
+	"registration.code_sent#few":          "Код подтверждения отправлен на ваш email. Пожалуйста, введите код.\nКод действует %d минуты. Если Вы не видите письмо - проверьте Спам или обратитесь к организаторам",
+	"registration.code_sent#many":         "Код подтверждения отправлен на ваш email. Пожалуйста, введите код.\nКод действует %d минут. Если Вы не видите письмо - проверьте Спам или обратитесь к организаторам",
+	"registration.invalid_code_format":    "Неверный формат кода. Пожалуйста, введите числовой код.",
+	"registration.code_check_failed":      "Произошла ошибка при проверке кода. Пожалуйста, попробуйте снова",
+	"registration.code_not_found":         "Не найден код для подтверждения. Попробуйте начать регистрацию заново.",
+	"registration.code_expired":           "Срок действия кода истек. Введите почту еще раз, чтобы получить новый код.",
+	"registration.verification_failed":    "Произошла ошибка при верификации. Пожалуйста, попробуйте снова",
+	"registration.completed":              "Регистрация успешно завершена! Используйте команду /vote для голосования",
+	"registration.vote_transferred":       "Ваш голос, отданный с прежнего аккаунта, сохранен. Новый токен проверки голоса:\n\n🔑 <code>%s</code>",
+	"registration.account_locked":         "Превышено количество попыток ввода кода. Регистрация заблокирована, обратитесь к организаторам.",
+	"registration.code_attempts_exceeded": "Превышено количество попыток для этого кода. Введите почту еще раз, чтобы получить новый код.",
+	"registration.wrong_code#one":         "Неверный код. Осталась %d попытка.",
+	"registration.wrong_code#few":         "Неверный код. Осталось %d попытки.",
+	"registration.wrong_code#many":        "Неверный код. Осталось %d попыток.",
+	"registration.locked":                 "Регистрация заблокирована из-за превышения количества попыток ввода кода. Обратитесь к организаторам.",
+
+	// Голосование
+	"vote.closed":                    "Голосование уже завершилось или еще не началось",
+	"vote.registration_check_failed": "Произошла ошибка при проверке регистрации делегата. Пожалуйста, попробуйте снова",
+	"vote.not_registered":            "Вы не зарегистрированы! Используйте команду /start для регистрации",
+	"vote.help":                      "Принцип голосования по методу Шульце заключается в формировании ранжированного списка кандидатов, в котором <b>каждый кандидат должен быть ранжирован</b> по отношению к другим.\nНапример, если вы считаете, что кандидат А лучше кандидата Б, то вы должны поставить кандидата А выше в списке.\n\nВ контексте использования данного бота Вы должны последовательно выбрать кандидатов, от наиболее предпочитаемого к наименее предпочитаемому.\nДля этого используйте кнопки меню в сообщении-бюллетени, последовательно выбирая нужного кандидата.\n\nВажно:\n• Вы должны ранжировать <b>всех кандидатов</b>.\n• Удостоверьтесь, что Ваш бюллетень принят, <b>получив соответствующее сообщение</b>.\n• Вы cможете изменить свой бюллетень ранжирования в любое время до окончания голосования.\n• Не выбирайте следующего кандидата, пока не увидите изменение в теле сообщения-бюллетеня.\n• Кнопками ⬆️ и ⬇️ можно изменить порядок, кнопкой «Отменить последний» — убрать последний выбор, кнопкой «Очистить» — начать заново.\n• Перед отправкой бот покажет итоговый порядок для проверки.",
+	"vote.candidates_header":         "Cписок кандидатов:",
+	"vote.save_failed":               "Произошла ошибка при регистрации голоса. Пожалуйста, попробуйте снова",
+	"vote.accepted":                  "<b>Ваш бюллетень принят✅</b>\n\nВы можете изменить свой бюллетень до окончания голосования, проголосовав заново, отправив для этого команду /vote\n\n🔑 <code>%s</code>",
+
+	// Бюллетень
+	"ballot.header":         "Выберите всех кандидатов от наиболее к наименее предпочтительному:",
+	"ballot.create_failed":  "Произошла ошибка при создании бюллетеня. Пожалуйста, попробуйте снова",
+	"ballot.undo":           "↩️ Отменить последний",
+	"ballot.clear":          "🗑 Очистить",
+	"ballot.review":         "✅ Проверить и отправить",
+	"ballot.button_failed":  "Произошла ошибка при обработке кнопки. Пожалуйста, попробуйте снова",
+	"ballot.outdated":       "Этот бюллетень уже отправлен или устарел. Используйте команду /vote для получения нового бюллетеня.",
+	"ballot.superseded":     "Этот бюллетень заменен новым. Используйте последний бюллетень или команду /vote.",
+	"ballot.stale_press":    "Бюллетень уже обновлен, нажмите кнопку еще раз",
+	"ballot.undone":         "Последний выбор отменен",
+	"ballot.cleared":        "Бюллетень очищен",
+	"ballot.incomplete":     "Ранжируйте всех кандидатов",
+	"ballot.already_ranked": "Кандидат уже в бюллетене",
+	"ballot.ranked":         "Кандидат учтен",
+	"ballot.edit":           "✏️ Изменить",
+	"ballot.submit":         "📨 Отправить",
+	"ballot.review_header":  "Проверьте бюллетень перед отправкой:",
+	"ballot.review_footer":  "Если порядок верный, нажмите «Отправить». Чтобы изменить порядок, нажмите «Изменить».",
+	"ballot.final_header":   "Ваш итоговый бюллетень:",
+
+	// Профили кандидатов
+	"candidates.load_failed":      "Произошла ошибка при получении списка кандидатов. Пожалуйста, попробуйте снова",
+	"candidates.empty":            "Список кандидатов пока пуст",
+	"candidates.choose_course":    "Выберите курс, чтобы посмотреть кандидатов",
+	"candidates.choose_candidate": "Выберите кандидата, чтобы открыть профиль",
+	"candidates.page":             "Страница %d из %d",
+	"candidates.back":             "⬅️ К курсам",
+	"candidates.not_found":        "Кандидат не найден",
+	"candidates.manifesto":        "Программа кандидата",
+	"course.bachelor":             "%d бакалавриат",
+	"course.master":               "%d магистратура",
+
+	// Mini App
+	"webapp.bad_request":     "Некорректный запрос",
+	"webapp.unauthorized":    "Не удалось подтвердить пользователя Telegram. Откройте бюллетень заново",
+	"webapp.expired":         "Сессия устарела. Откройте бюллетень заново",
+	"webapp.rate_limited":    "Слишком много запросов. Пожалуйста, попробуйте позже",
+	"webapp.invalid_ranking": "Ранжируйте всех кандидатов ровно по одному разу",
+	"webapp.open":            "🗳 Открыть бюллетень",
+	"webapp.button_text":     "Удобнее ранжировать кандидатов перетаскиванием можно в бюллетене-приложении. Бюллетень с кнопками ниже по-прежнему работает.",
+
+	// Расписание
+	"schedule.in_24h":                       "24 часа",
+	"schedule.in_1h":                        "1 час",
+	"schedule.in_10m":                       "10 минут",
+	"schedule.countdown_start.registration": "⏳ До начала регистрации осталось %s. Начало: %s",
+	"schedule.countdown_start.voting":       "⏳ До начала голосования осталось %s. Начало: %s",
+	"schedule.opened.registration":          "✅ Период регистрации начался и продлится до %s",
+	"schedule.opened.voting":                "✅ Период голосования начался и продлится до %s\nИспользуйте /vote для голосования",
+	"schedule.countdown_end.registration":   "⏳ До окончания регистрации осталось %s. Окончание: %s",
+	"schedule.countdown_end.voting":         "⏳ До окончания голосования осталось %s. Окончание: %s\nЕсли Вы еще не проголосовали, используйте /vote",
+	"schedule.closed.registration":          "🏁 Период регистрации завершен",
+	"schedule.closed.voting":                "🏁 Период голосования завершен",
+	"schedule.not_started.registration":     "Период регистрации еще не начался. Начало: %s",
+	"schedule.not_started.voting":           "Период голосования еще не начался. Начало: %s",
+	"schedule.ended.registration":           "Период регистрации завершен",
+	"schedule.ended.voting":                 "Период голосования завершен",
+	"schedule.none":                         "Расписание не задано",
+	"schedule.header":                       "Расписание:",
+	"schedule.status.pending":               "ожидает начала",
+	"schedule.status.open":                  "идет",
+	"schedule.status.closed":                "завершено",
+	"schedule.line.registration":            "Период регистрации: %s — %s, %s",
+	"schedule.line.voting":                  "Период голосования: %s — %s, %s",
+
+	// Напоминания
+	"reminder.default":       "Напоминаем: голосование идет, а Ваш голос еще не учтен. Используйте /vote, чтобы проголосовать",
+	"reminder.sent#one":      "Напоминание разослано %d делегату\nдоставлено: %d, заблокировали бота: %d, ошибок: %d",
+	"reminder.sent#few":      "Напоминание разослано %d делегатам\nдоставлено: %d, заблокировали бота: %d, ошибок: %d",
+	"reminder.sent#many":     "Напоминание разослано %d делегатам\nдоставлено: %d, заблокировали бота: %d, ошибок: %d",
+	"reminders.none":         "Запланированных напоминаний нет",
+	"reminders.header":       "Запланированные напоминания:",
+	"reminders.default_text": "стандартный текст",
+
+	// Импорт списков
+	"list.more":                  "… и еще %d",
+	"import.help":                "Отправьте боту CSV или XLSX файл со списками делегатов и кандидатов.\n\nПервая строка — заголовок с колонками: type, id, name, group, course, description (или тип, id, фио, группа, курс, описание).\ntype — delegate или candidate; id — шестизначный номер (можно stXXXXXX);\nдля делегатов обязательна группа (XX.БXX-пу), для кандидатов — курс (например, 1 бакалавриат).\n\nБот покажет, кого добавит, изменит и удалит, и применит изменения только после подтверждения. Делегаты или кандидаты, которых нет в файле, удаляются, если в файле есть хотя бы одна строка этого типа.",
+	"import.forbidden":           "Недостаточно прав для импорта",
+	"import.unsupported_format":  "Поддерживаются только файлы CSV и XLSX. Формат файла: /import",
+	"import.too_large":           "Файл слишком большой",
+	"import.download_failed":     "Не удалось загрузить файл. Пожалуйста, попробуйте снова",
+	"import.read_failed":         "Не удалось прочитать файл. Проверьте формат: /import",
+	"import.errors":              "Файл не импортирован, найдено ошибок: %d",
+	"import.no_changes":          "Изменений нет",
+	"import.apply":               "✅ Применить",
+	"import.cancel":              "❌ Отменить",
+	"import.expired":             "Импорт устарел или уже обработан. Отправьте файл снова",
+	"import.cancelled":           "Импорт отменен",
+	"import.failed":              "Импорт не применен, изменения отменены: %s\nПроверьте файл и отправьте его снова",
+	"import.applied":             "✅ Импорт применен",
+	"roster.empty_file":          "файл пуст",
+	"roster.missing_column":      "в заголовке нет колонки %s",
+	"roster.row_error":           "строка %d: %s",
+	"roster.unknown_type":        "неизвестный тип %q (delegate или candidate)",
+	"roster.invalid_id":          "неверный ID %q, ожидается шестизначное число",
+	"roster.missing_name":        "не указано имя",
+	"roster.invalid_group":       "неверная группа %q, ожидается формат XX.БXX-пу или XX.МXX-пу",
+	"roster.duplicate_delegate":  "делегат st%06d уже указан в строке %d",
+	"roster.duplicate_group":     "группа %s уже указана в строке %d",
+	"roster.invalid_course":      "неверный курс %q, ожидается например «1 бакалавриат» или «2 магистратура»",
+	"roster.duplicate_candidate": "кандидат st%06d уже указан в строке %d",
+	"roster.no_rows":             "в файле нет ни одной строки с делегатом или кандидатом",
+	"roster.add_delegate":        "+ делегат st%06d, %s, %s",
+	"roster.update_delegate":     "~ делегат st%06d: %s, %s → %s, %s",
+	"roster.delete_delegate":     "- делегат st%06d, %s, %s",
+	"roster.voted_delegate_kept": "делегат st%06d, %s отсутствует в файле, но уже проголосовал и не будет удален",
+	"roster.add_candidate":       "+ кандидат st%06d, %s, %s",
+	"roster.update_candidate":    "~ кандидат st%06d: %s, %s → %s, %s",
+	"roster.delete_candidate":    "- кандидат st%06d, %s, %s",
+	"roster.preview":             "<b>Предпросмотр импорта</b> (изменения еще не применены)\nДелегаты: добавить %d, изменить %d, удалить %d\nКандидаты: добавить %d, изменить %d, удалить %d",
+
+	// Администрирование
+	"admin.help_header":                "Ваша роль: %s\nСписок доступных команд:",
+	"admin.help_footer":                "Всегда используйте запятые между аргументами команды, если идет перечисление аргументов",
+	"admin.command.add_delegate":       "добавить делегата",
+	"admin.command.delete_delegate":    "удалить делегата",
+	"admin.command.reset_registration": "отвязать Telegram аккаунт делегата для повторной регистрации; discard — удалить его голос",
+	"admin.command.audit":              "история привязок Telegram аккаунтов делегата",
+	"admin.command.set_proxy":          "назначить делегату заместителя",
+	"admin.command.cancel_proxy":       "отозвать доверенность делегата",
+	"admin.command.proxies":            "список доверенностей, включая отозванные",
+	"admin.command.edit_delegate":      "изменить ФИО или группу делегата; без поля — карточка с кнопками",
+	"admin.command.add_candidate":      "добавить кандидата",
+	"admin.command.ban_candidate":      "заблокировать кандидата",
+	"admin.command.unban_candidate":    "снова допустить кандидата (только вне голосования)",
+	"admin.command.edit_candidate":     "изменить ФИО, курс или описание кандидата; без поля — карточка с кнопками",
+	"admin.command.set_photo":          "задать фото кандидата: отправьте ответом на сообщение с фото",
+	"admin.command.set_manifesto":      "задать или удалить ссылку на программу кандидата",
+	"admin.command.delete_candidate":   "удалить кандидата",
+	"admin.command.unlock":             "снять блокировку верификации почты",
+	"admin.command.show_delegates":     "показать список делегатов",
+	"admin.command.show_candidates":    "показать список кандидатов",
+	"admin.command.show_votes":         "показать список голосов (наблюдателям и операторам — только количество)",
+	"admin.command.turnout":            "показать явку",
+	"admin.command.start_voting":       "начать голосование",
+	"admin.command.stop_voting":        "остановить голосование",
+	"admin.command.schedule":           "показать расписание или запланировать регистрацию или голосование",
+	"admin.command.cancel_schedule":    "отменить расписание",
+	"admin.command.remind":             "напомнить непроголосовавшим делегатам",
+	"admin.command.remind_at":          "запланировать напоминание",
+	"admin.command.reminders":          "показать запланированные напоминания",
+	"admin.command.cancel_reminder":    "отменить напоминание",
+	"admin.command.results":            "вычислить результаты голосования",
+	"admin.command.print":              "вывести результаты голосования",
+	"admin.command.csv":                "сохранить результаты в CSV файл",
+	"admin.command.xlsx":               "выгрузить результаты и списки делегатов в Excel",
+	"admin.command.protocol":           "сформировать PDF протокол голосования",
+	"admin.command.import":             "импорт делегатов и кандидатов из CSV или XLSX файла: отправьте файл боту",
+	"admin.command.grant":              "выдать роль",
+	"admin.command.revoke":             "отозвать роль",
+	"admin.command.admins":             "показать список администраторов",
+	"admin.command.log":                "установить уровень логирования (Debug, Info, Warn, Error)",
+	"admin.command.send_logs":          "отправить файл логов",
+	"admin.command.texts":              "показать переопределенные тексты сообщений",
+	"admin.command.text":               "показать текст сообщения на всех языках",
+	"admin.command.set_text":           "переопределить текст сообщения",
+	"admin.command.reset_text":         "вернуть исходный текст сообщения",
+	"admin.delegates_header":           "Список делегатов:",
+	"admin.candidates_header":          "Список кандидатов:",
+	"admin.votes_header":               "Список голосов:",
+	"admin.vote_counts":                "Делегатов: %d\nЗарегистрировано: %d\nПроголосовало: %d",
+	"role.superadmin":                  "суперадминистратор",
+	"role.operator":                    "оператор",
+	"role.observer":                    "наблюдатель",
+	"role.tally_officer":               "счетная комиссия",
+	"admins.none":                      "Роли не выданы. Все команды доступны только в чате администраторов",
+	"admins.header":                    "Список администраторов:",
+	"admins.line":                      "• <a href=\"tg://user?id=%d\">%d</a>, %s, с %s",
+
+	// Тексты сообщений
+	"texts.override":       "Переопределено: <code>%s</code>",
+	"texts.none":           "Переопределенных текстов нет",
+	"texts.header":         "<b>Переопределенные тексты:</b>",
+	"texts.unknown_key":    "Неизвестный ключ сообщения <code>%s</code>. Ключи перечислены в internal/i18n",
+	"texts.invalid":        "Текст не сохранен: %s",
+	"texts.saved":          "✅ Текст <code>%s</code> (%s) изменен",
+	"texts.not_overridden": "Текст <code>%s</code> (%s) не переопределен",
+	"texts.reset":          "✅ Текст <code>%s</code> (%s) возвращен к исходному",
+
+	// Явка
+	"turnout.header":       "Явка",
+	"turnout.registered":   "Зарегистрировано: %d из %d (%d%%)",
+	"turnout.voted":        "Проголосовало: %d из %d зарегистрированных (%d%%)",
+	"turnout.by_year":      "По году поступления:",
+	"turnout.year":         "• %s: зарегистрировано %d из %d, проголосовало %d (%d%%)",
+	"turnout.unknown_year": "группа не указана",
+	"turnout.finished":     "Голосование завершено",
+	"turnout.updated":      "Обновлено: %s",
+
+	// Ограничение частоты действий
+	"ratelimit.commands":         "Слишком много команд. Пожалуйста, попробуйте снова через %s.",
+	"ratelimit.callbacks":        "Слишком много нажатий. Пожалуйста, попробуйте снова через %s.",
+	"ratelimit.emails":           "Слишком много запросов кода. Запросить новый код можно через %s.",
+	"ratelimit.codes":            "Слишком много попыток ввода кода. Пожалуйста, попробуйте снова через %s.",
+	"ratelimit.seconds#one":      "%d секунду",
+	"ratelimit.seconds#few":      "%d секунды",
+	"ratelimit.seconds#many":     "%d секунд",
+	"ratelimit.minutes#one":      "%d минуту",
+	"ratelimit.minutes#few":      "%d минуты",
+	"ratelimit.minutes#many":     "%d минут",
+	"ratelimit.action.commands":  "команды",
+	"ratelimit.action.callbacks": "нажатия кнопок",
+	"ratelimit.action.emails":    "запросы кода на почту",
+	"ratelimit.action.codes":     "попытки ввода кода",
+	"ratelimit.abuse":            "⚠️ Возможный флуд: <a href=\"tg://user?id=%d\">%d</a> продолжает превышать ограничения (%s). Запросы сверх лимита отклоняются",
+
+	// Подтверждение необратимых действий
+	"confirm.delete_delegate":         "Удалить делегата st%06d (%s, %s)?",
+	"confirm.delegate_registered":     "Telegram: <a href=\"tg://user?id=%d\">%d</a>",
+	"confirm.delegate_not_registered": "Telegram аккаунт не привязан",
+	"confirm.delegate_has_vote":       "⚠️ Делегат уже проголосовал: его голос будет удален безвозвратно",
+	"confirm.delegate_no_vote":        "Делегат еще не голосовал",
+	"confirm.delete_candidate":        "Удалить кандидата st%06d (%s, %s)?",
+	"confirm.candidate_ballots#one":   "⚠️ Кандидат указан в %d бюллетене",
+	"confirm.candidate_ballots#few":   "⚠️ Кандидат указан в %d бюллетенях",
+	"confirm.candidate_ballots#many":  "⚠️ Кандидат указан в %d бюллетенях",
+	"confirm.candidate_no_ballots":    "Кандидат не указан ни в одном бюллетене",
+	"confirm.expires#one":             "Подтвердите в течение %d минуты.",
+	"confirm.expires#few":             "Подтвердите в течение %d минут.",
+	"confirm.expires#many":            "Подтвердите в течение %d минут.",
+	"confirm.yes":                     "✅ Подтвердить",
+	"confirm.no":                      "❌ Отменить",
+	"confirm.expired":                 "Подтверждение устарело или уже обработано. Отправьте команду снова",
+	"confirm.cancelled":               "Действие отменено",
+	"confirm.failed":                  "Не удалось выполнить действие. Пожалуйста, попробуйте снова",
+	"confirm.state_changed":           "Данные изменились после запроса подтверждения, действие не выполнено. Отправьте команду снова, чтобы увидеть актуальные последствия",
+	"admin.delegate_not_found":        "Делегат st%06d не найден",
+	"admin.delegate_deleted":          "✅ Делегат st%06d удален",
+	"admin.candidate_not_found":       "Кандидат st%06d не найден",
+	"admin.candidate_deleted":         "✅ Кандидат st%06d удален",
+	"admin.delegate_added":            "✅ Делегат st%06d добавлен",
+	"admin.candidate_added":           "✅ Кандидат st%06d добавлен",
+	"admin.candidate_banned":          "✅ Кандидат st%06d заблокирован",
+	"admin.photo_reply_required":      "Отправьте /set_photo &lt;candidate_id&gt; ответом на сообщение с фото",
+	"admin.photo_saved":               "✅ Фото кандидата st%06d сохранено",
+	"admin.manifesto_saved":           "✅ Ссылка на программу кандидата st%06d сохранена",
+	"admin.manifesto_removed":         "✅ Ссылка на программу кандидата st%06d удалена",
+	"admin.unlocked":                  "✅ Блокировка верификации пользователя %d снята",
+	"admin.voting_started":            "✅ Голосование открыто",
+	"admin.voting_stopped":            "🏁 Голосование закрыто",
+	"admin.results_computed":          "✅ Результаты вычислены",
+	"admin.results_failed":            "Не удалось вычислить результаты. Подробности в логах",
+	"admin.export_failed":             "Не удалось сформировать файл. Подробности в логах",
+	"admin.role_granted":              "✅ Пользователю %d выдана роль %s",
+	"admin.role_revoked":              "✅ У пользователя %d отозвана роль",
+	"admin.role_missing":              "У пользователя %d нет роли",
+	"schedule.unknown_timezone":       "Неизвестный часовой пояс %s",
+	"schedule.ends_before_start":      "Время окончания должно быть позже времени начала",
+	"schedule.ends_in_past":           "Время окончания уже прошло",
+	"schedule.saved.registration":     "✅ Период регистрации запланирован: %s — %s",
+	"schedule.saved.voting":           "✅ Период голосования запланирован: %s — %s",
+	"schedule.cancelled.registration": "✅ Расписание периода регистрации отменено",
+	"schedule.cancelled.voting":       "✅ Расписание периода голосования отменено",
+	"reminder.in_past":                "Время напоминания уже прошло",
+	"reminder.scheduled":              "✅ Напоминание %d запланировано на %s",
+	"reminder.cancelled":              "✅ Напоминание %d отменено",
+	"reminder.not_found":              "Напоминание %d не найдено или уже отправлено",
+
+	// Изменение делегатов и кандидатов
+	"edit.delegate_header":            "<b>Делегат st%06d</b>",
+	"edit.candidate_header":           "<b>Кандидат st%06d</b>",
+	"edit.field_value":                "%s: %s",
+	"edit.field.name":                 "ФИО",
+	"edit.field.group":                "Группа",
+	"edit.field.course":               "Курс",
+	"edit.field.description":          "Описание",
+	"edit.choose_field":               "Выберите поле, которое нужно изменить:",
+	"edit.cancel":                     "❌ Отменить",
+	"edit.cancelled":                  "Изменение отменено",
+	"edit.prompt":                     "Отправьте новое значение поля «%s» ответом на это сообщение.\nТекущее значение: %s",
+	"edit.invalid_value":              "Недопустимое значение поля «%s». %s\nОтправьте другое значение ответом на сообщение бота",
+	"edit.hint.delegate.name":         "ФИО не может быть пустым.",
+	"edit.hint.delegate.group":        "Формат группы: XX.БXX-пу или XX.МXX-пу.",
+	"edit.hint.candidate.name":        "ФИО не может быть пустым.",
+	"edit.hint.candidate.course":      "Курс: 1-4 бакалавриат или 1-2 магистратура.",
+	"edit.hint.candidate.description": "Описание не может быть пустым.",
+	"edit.locked_during_voting":       "Поле «%s» нельзя менять, пока открыто голосование",
+	"edit.failed":                     "Не удалось сохранить изменение. Пожалуйста, попробуйте снова",
+	"edit.done":                       "✅ st%06d: поле «%s» изменено: %s → %s",
+	"edit.unban_during_voting":        "Допускать кандидатов нельзя, пока открыто голосование: список кандидатов в бюллетенях не меняется",
+	"edit.unbanned":                   "✅ Кандидат st%06d снова допущен до выборов",
+
+	// Сброс регистрации
+	"reset.not_registered":            "Делегат st%06d еще не привязал Telegram аккаунт",
+	"reset.discard_closed":            "Голос нельзя удалить, пока голосование закрыто: это изменит итоги. Сбросьте регистрацию без discard",
+	"reset.summary":                   "Отвязать Telegram аккаунт делегата st%06d (%s, %s)?",
+	"reset.vote_keep":                 "Голос сохранится и перейдет к новому аккаунту, токен проверки голоса изменится",
+	"reset.vote_discard":              "⚠️ Голос делегата будет удален: проголосовать нужно будет заново",
+	"reset.next_steps":                "После сброса делегат сможет заново пройти регистрацию через /start с любого аккаунта.",
+	"reset.notice":                    "Администратор отвязал этот Telegram аккаунт от делегата. Если это ошибка, обратитесь к организаторам",
+	"reset.done":                      "✅ Регистрация делегата st%06d сброшена",
+	"reset.done_vote_discarded":       "✅ Регистрация делегата st%06d сброшена, голос удален",
+	"audit.none":                      "Для делегата st%06d записей нет",
+	"audit.header":                    "<b>История делегата st%06d:</b>",
+	"audit.action.verification":       "верификация",
+	"audit.action.reset_registration": "сброс регистрации",
+	"audit.account":                   "аккаунт <a href=\"tg://user?id=%d\">%d</a>",
+	"audit.details.vote_kept":         "голос сохранен",
+	"audit.details.vote_discarded":    "голос удален",
+	"audit.details.no_vote":           "без голоса",
+	"audit.performed_by":              "выполнил <a href=\"tg://user?id=%d\">%d</a>",
+	"admin.vote_by_proxy":             "(по доверенности)",
+	"audit.action.proxy_nominated":    "назначен заместитель",
+	"audit.action.proxy_verified":     "заместитель подтвердил почту",
+	"audit.action.proxy_revoked":      "доверенность отозвана",
+	"audit.action.proxy_voted":        "заместитель проголосовал",
+	"audit.substitute":                "заместитель st%06d",
+	"proxy.delegates_only":            "Передать голос может только зарегистрированный делегат. Для регистрации используйте команду /start",
+	"proxy.none":                      "У вас нет заместителя. Чтобы передать голос, отправьте /proxy и ID заместителя — шесть цифр из его почты, например: /proxy 123456\nЗаместителем должен быть другой студент вашей группы, который не является делегатом. Бот не знает групп студентов, кроме делегатов, и не может это проверить — за выбор заместителя отвечаете вы. Заместитель подтвердит почту через /start и проголосует вместо вас.",
+	"proxy.status_pending":            "Ваш заместитель — st%06d. Он еще не подтвердил почту. Пока доверенность действует, голосует только заместитель. Отозвать доверенность: /revoke_proxy",
+	"proxy.status_verified":           "Ваш заместитель — st%06d, почта подтверждена. Пока доверенность действует, голосует только заместитель. Отозвать доверенность: /revoke_proxy",
+	"proxy.self":                      "Нельзя назначить заместителем самого себя",
+	"proxy.substitute_is_delegate":    "st%06d — делегат и голосует сам. Заместителем может быть только студент, который не является делегатом",
+	"proxy.already_exists":            "У делегата st%06d уже есть заместитель. Сначала отзовите доверенность",
+	"proxy.substitute_busy":           "st%06d уже замещает другого делегата",
+	"proxy.nominated":                 "✅ st%06d назначен заместителем делегата st%06d. Заместителю нужно отправить боту /start и подтвердить почту st%06d. Пока доверенность действует, голосует только заместитель",
+	"proxy.no_active":                 "У делегата st%06d нет действующей доверенности",
+	"proxy.revoked":                   "✅ Доверенность заместителя st%06d отозвана, делегат st%06d снова голосует сам. Голос, уже отданный заместителем, сохраняется, пока делегат не проголосует заново",
+	"proxy.notice_nominated":          "Администратор назначил вам заместителя st%06d. Пока доверенность действует, голосует только заместитель. Подробнее: /proxy",
+	"proxy.notice_verified":           "Заместитель st%06d подтвердил почту и может голосовать за вас",
+	"proxy.notice_revoked":            "Администратор отозвал вашу доверенность: вы снова голосуете сами",
+	"proxy.substitute_revoked":        "Доверенность делегата st%06d отозвана: голосовать за него вы больше не можете",
+	"proxy.header":                    "<b>Доверенности:</b>",
+	"proxy.list_none":                 "Доверенностей нет",
+	"proxy.line":                      "• st%06d → st%06d, назначен %s",
+	"proxy.line_pending":              "почта не подтверждена",
+	"proxy.line_revoked":              "отозвана %s",
+	"registration.proxy_completed":    "✅ Почта подтверждена. Вы заместитель делегата st%06d и голосуете вместо него: /vote",
+	"registration.proxy_revoked":      "Доверенность отозвана, регистрация заместителя отменена",
+	"vote.proxy_active":               "Вы передали голос заместителю st%06d, поэтому голосует он. Чтобы голосовать самому, отзовите доверенность: /revoke_proxy",
+	"vote.as_proxy":                   "Вы голосуете по доверенности за делегата st%06d",
+	"verify.no_vote":                  "Ваш бюллетень еще не записан. Для голосования используйте команду /vote",
+	"verify.no_vote_delegated":        "Бюллетень еще не записан. Голосует ваш заместитель st%06d",
+	"verify.missing":                  "⚠️ Бот подтвердил бюллетень %s, но сейчас голос не записан. Сообщите организаторам",
+	"verify.cast_by_substitute":       "Голос за делегата st%06d подан заместителем по доверенности. Проверить бюллетень может заместитель командой /verify",
+	"verify.cast_by_delegate":         "Голос за делегата st%06d подан самим делегатом. Проверить бюллетень может делегат командой /verify",
+	"verify.ballot":                   "<b>Записанный бюллетень</b> (подан %s):",
+	"verify.token":                    "🔑 <code>%s</code>",
+	"verify.matches":                  "✅ Совпадает с бюллетенем, который бот подтвердил %s. Проверить голос в опубликованном списке можно по токену",
+	"verify.mismatch":                 "⚠️ Записанный бюллетень отличается от бюллетеня, который бот подтвердил %s. Сообщите организаторам. Подтвержденный бюллетень:",
+	"verify.unconfirmed":              "ℹ️ Подтверждение бюллетеня не сохранено: голос подан до появления проверки или сообщение о принятии не доставлено. Сверьте порядок с тем, что вы отправили",
+	"verify.confirmed_by_other":       "ℹ️ Подтверждение бюллетеня бот отправил другому аккаунту: 🔑 <code>%s</code>",
+}
@@ -2,6 +2,7 @@ package models
 
 import (
 	"database/sql"
+	"slices"
 	"time"
 )
 
@@ -16,19 +17,33 @@ type Delegate struct {
 
 // Candidate представляет модель кандидата
 type Candidate struct {
-	CandidateID int    `db:"candidate_id"` // Шестизначный код из st-email
-	Name        string `db:"name"`         // Имя кандидата
-	Course      string `db:"course"`       // Курс кандидата
-	Description string `db:"description"`  // Описание кандидата
-	IsEligible  bool   `db:"is_eligible"`  // Допущен ли кандидат до выборов
+	CandidateID  int    `db:"candidate_id"`  // Шестизначный код из st-email
+	Name         string `db:"name"`          // Имя кандидата
+	Course       string `db:"course"`        // Курс кандидата
+	Description  string `db:"description"`   // Описание кандидата
+	IsEligible   bool   `db:"is_eligible"`   // Допущен ли кандидат до выборов
+	PhotoFileID  string `db:"photo_file_id"` // file_id фото кандидата в Telegram (пусто — без фото)
+	ManifestoURL string `db:"manifesto_url"` // Ссылка на программу кандидата (пусто — без ссылки)
 }
 
 // Vote представляет модель голосования
 type Vote struct {
-	ID                int       `db:"id"`                 // Уникальный идентификатор голосования
-	DelegateID        int       `db:"delegate_id"`        // ID делегата
-	CandidateRankings []int     `db:"candidate_rankings"` // Ранжирование кандидатов
-	CreatedAt         time.Time `db:"created_at"`         // Время создания голосования
+	ID                int           `db:"id"`                 // Уникальный идентификатор голосования
+	DelegateID        int           `db:"delegate_id"`        // ID делегата
+	CandidateRankings []int         `db:"candidate_rankings"` // Ранжирование кандидатов
+	CreatedAt         time.Time     `db:"created_at"`         // Время создания голосования
+	ProxyID           sql.NullInt64 `db:"proxy_id"`           // Доверенность, по которой голосовал заместитель (NULL — голосовал делегат)
+}
+
+// CountBallots возвращает число бюллетеней, в которых указан кандидат
+func CountBallots(votes []Vote, candidateID int) int {
+	ballots := 0
+	for _, vote := range votes {
+		if slices.Contains(vote.CandidateRankings, candidateID) {
+			ballots++
+		}
+	}
+	return ballots
 }
 
 // Result представляет модель результатов
@@ -39,4 +54,163 @@ type Result struct {
 	Preferences       map[int]map[int]int `db:"preferences"`         // Парные предпочтения
 	StrongestPaths    map[int]map[int]int `db:"strongest_paths"`     // Сильнейшие пути
 	Stage             string              `db:"stage"`               // Состояние результатов (на каком этапе получены результаты)
+	ComputedAt        time.Time           `db:"computed_at"`         // Время вычисления результатов
+}
+
+// Session представляет модель сохраненного состояния бота
+type Session struct {
+	Key       string       `db:"key"`        // Ключ сессии
+	Value     []byte       `db:"value"`      // Состояние в формате JSON
+	ExpiresAt sql.NullTime `db:"expires_at"` // Время истечения (NULL — бессрочно)
+	UpdatedAt time.Time    `db:"updated_at"` // Время последнего изменения
+}
+
+// Виды окон расписания выборов
+const (
+	ScheduleRegistration = "registration"
+	ScheduleVoting       = "voting"
+)
+
+// ScheduleWindow представляет окно расписания выборов (регистрация или голосование)
+type ScheduleWindow struct {
+	Kind          string       `db:"kind"`          // Вид окна (ScheduleRegistration, ScheduleVoting)
+	StartsAt      time.Time    `db:"starts_at"`     // Начало окна
+	EndsAt        time.Time    `db:"ends_at"`       // Окончание окна
+	Timezone      string       `db:"timezone"`      // Часовой пояс для отображения времени
+	OpenedAt      sql.NullTime `db:"opened_at"`     // Когда планировщик открыл окно
+	ClosedAt      sql.NullTime `db:"closed_at"`     // Когда планировщик закрыл окно
+	Announcements []string     `db:"announcements"` // Уже отправленные объявления
+	UpdatedAt     time.Time    `db:"updated_at"`    // Время последнего изменения
+}
+
+// Reminder представляет модель запланированного напоминания непроголосовавшим делегатам
+type Reminder struct {
+	ID        int          `db:"id"`         // Уникальный идентификатор напоминания
+	Text      string       `db:"text"`       // Текст напоминания
+	SendAt    time.Time    `db:"send_at"`    // Запланированное время отправки
+	SentAt    sql.NullTime `db:"sent_at"`    // Когда началась рассылка
+	Delivered int          `db:"delivered"`  // Доставлено сообщений
+	Blocked   int          `db:"blocked"`    // Делегаты, заблокировавшие бота
+	Failed    int          `db:"failed"`     // Ошибки отправки
+	CreatedAt time.Time    `db:"created_at"` // Время создания
+}
+
+// Роли администраторов
+const (
+	RoleSuperadmin   = "superadmin"    // Все команды, включая выдачу ролей
+	RoleOperator     = "operator"      // Управление делегатами, кандидатами и ходом голосования
+	RoleObserver     = "observer"      // Только просмотр, без содержимого бюллетеней
+	RoleTallyOfficer = "tally_officer" // Подсчет и публикация результатов
+)
+
+// Admin представляет модель администратора с ролью
+type Admin struct {
+	TelegramID int64         `db:"telegram_id"` // ID пользователя в Telegram
+	Role       string        `db:"role"`        // Роль (RoleSuperadmin, RoleOperator, RoleObserver, RoleTallyOfficer)
+	GrantedBy  sql.NullInt64 `db:"granted_by"`  // Кто выдал роль
+	GrantedAt  time.Time     `db:"granted_at"`  // Когда выдана роль
+}
+
+// TextOverride представляет текст сообщения, переопределенный администратором
+type TextOverride struct {
+	Language  string        `db:"language"`   // Язык текста
+	Key       string        `db:"key"`        // Ключ сообщения в каталоге
+	Text      string        `db:"text"`       // Текст
+	UpdatedBy sql.NullInt64 `db:"updated_by"` // Кто изменил текст
+	UpdatedAt time.Time     `db:"updated_at"` // Когда изменен текст
+}
+
+// AuditRecord представляет запись журнала действий с регистрацией делегата
+type AuditRecord struct {
+	ID          int           `db:"id"`           // Уникальный идентификатор записи
+	Action      string        `db:"action"`       // Действие (AuditResetRegistration и т. д.)
+	DelegateID  int           `db:"delegate_id"`  // ID делегата
+	TelegramID  sql.NullInt64 `db:"telegram_id"`  // Telegram аккаунт, которого касается действие
+	PerformedBy sql.NullInt64 `db:"performed_by"` // Кто выполнил действие
+	Details     string        `db:"details"`      // Подробности
+	CreatedAt   time.Time     `db:"created_at"`   // Время действия
+}
+
+// Действия в журнале
+const (
+	AuditResetRegistration = "reset_registration" // Администратор отвязал Telegram аккаунт делегата
+	AuditVerification      = "verification"       // Делегат подтвердил почту и привязал Telegram аккаунт
+	AuditProxyNominated    = "proxy_nominated"    // Делегат или администратор назначил заместителя
+	AuditProxyVerified     = "proxy_verified"     // Заместитель подтвердил почту и привязал Telegram аккаунт
+	AuditProxyRevoked      = "proxy_revoked"      // Доверенность отозвана
+	AuditProxyVoted        = "proxy_voted"        // Заместитель проголосовал за делегата
+)
+
+// Подробности сброса регистрации: что стало с голосом делегата
+const (
+	AuditVoteKept      = "vote_kept"      // Голос остался за делегатом и перейдет к новому аккаунту
+	AuditVoteDiscarded = "vote_discarded" // Голос удален, делегат проголосует заново
+	AuditNoVote        = "no_vote"        // Делегат еще не голосовал
+)
+
+// Proxy представляет доверенность: заместитель голосует вместо делегата
+type Proxy struct {
+	ID           int           `db:"id"`            // Уникальный идентификатор доверенности
+	DelegateID   int           `db:"delegate_id"`   // Делегат, передавший голос
+	SubstituteID int           `db:"substitute_id"` // Шестизначный код заместителя из st-email
+	TelegramID   sql.NullInt64 `db:"telegram_id"`   // Telegram аккаунт заместителя (NULL — почта еще не подтверждена)
+	NominatedBy  sql.NullInt64 `db:"nominated_by"`  // Кто назначил заместителя
+	CreatedAt    time.Time     `db:"created_at"`    // Время назначения
+	RevokedAt    sql.NullTime  `db:"revoked_at"`    // Время отзыва (NULL — доверенность действует)
+	RevokedBy    sql.NullInt64 `db:"revoked_by"`    // Кто отозвал доверенность
+}
+
+// Voter описывает, за кого голосует Telegram аккаунт
+type Voter struct {
+	DelegateID   int           // Делегат, под ID которого записывается голос
+	ProxyID      sql.NullInt64 // Доверенность, если голосует заместитель
+	SubstituteID int           // Заместитель делегата с действующей доверенностью (0 — нет)
+}
+
+// Delegated сообщает, что делегат передал голос заместителю и не может голосовать сам
+func (v Voter) Delegated() bool {
+	return !v.ProxyID.Valid && v.SubstituteID != 0
+}
+
+// VoteReceipt представляет бюллетень, о принятии которого бот последним сообщил голосующему (сообщение доставлено).
+// Сверка с таблицей votes показывает, что записанный голос совпадает с поданным
+type VoteReceipt struct {
+	DelegateID        int       `db:"delegate_id"`        // Делегат, за которого подан бюллетень
+	CandidateRankings []int     `db:"candidate_rankings"` // Подтвержденное ранжирование
+	TelegramID        int64     `db:"telegram_id"`        // Аккаунт, которому отправлено подтверждение
+	ConfirmedAt       time.Time `db:"confirmed_at"`       // Время подтверждения
+}
+
+// Результаты сверки записанного голоса с подтвержденным бюллетенем
+const (
+	ReceiptMatches     = "matches"     // Голос совпадает с подтвержденным бюллетенем
+	ReceiptMismatch    = "mismatch"    // Голос отличается от подтвержденного бюллетеня или удален
+	ReceiptUnconfirmed = "unconfirmed" // Подтверждения нет: голос подан до появления квитанций или подтверждение не доставлено
+)
+
+// Check сверяет записанный голос (nil — голоса нет) с подтвержденным бюллетенем (nil — подтверждения нет)
+func (r *VoteReceipt) Check(vote *Vote) string {
+	switch {
+	case r == nil:
+		return ReceiptUnconfirmed
+	case vote == nil || !slices.Equal(vote.CandidateRankings, r.CandidateRankings):
+		return ReceiptMismatch
+	}
+	return ReceiptMatches
+}
+
+// RosterImport представляет изменения списков делегатов и кандидатов, подготовленные импортом файла
+type RosterImport struct {
+	AddDelegates     []Delegate  // Новые делегаты
+	UpdateDelegates  []Delegate  // Делегаты с измененными именем или группой
+	DeleteDelegates  []int       // ID делегатов, которых нет в файле
+	AddCandidates    []Candidate // Новые кандидаты
+	UpdateCandidates []Candidate // Кандидаты с измененными именем, курсом или описанием
+	DeleteCandidates []int       // ID кандидатов, которых нет в файле
+}
+
+// IsEmpty сообщает, что импорт ничего не меняет
+func (r RosterImport) IsEmpty() bool {
+	return len(r.AddDelegates)+len(r.UpdateDelegates)+len(r.DeleteDelegates)+
+		len(r.AddCandidates)+len(r.UpdateCandidates)+len(r.DeleteCandidates) == 0
 }
@@ -0,0 +1,46 @@
+package models
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCountBallots(t *testing.T) {
+	t.Parallel()
+
+	votes := []Vote{
+		{DelegateID: 100001, CandidateRankings: []int{200001, 200002}},
+		{DelegateID: 100002, CandidateRankings: []int{200002}},
+		{DelegateID: 100003},
+	}
+	assert.Equal(t, 1, CountBallots(votes, 200001))
+	assert.Equal(t, 2, CountBallots(votes, 200002))
+	assert.Equal(t, 0, CountBallots(votes, 200003))
+	assert.Equal(t, 0, CountBallots(nil, 200001))
+}
+
+// Делегат с действующей доверенностью не голосует, заместитель голосует под его ID
+func TestVoterDelegated(t *testing.T) {
+	t.Parallel()
+
+	assert.False(t, Voter{DelegateID: 123456}.Delegated())
+	assert.True(t, Voter{DelegateID: 123456, SubstituteID: 654321}.Delegated())
+	assert.False(t, Voter{DelegateID: 123456, SubstituteID: 654321,
+		ProxyID: sql.NullInt64{Int64: 1, Valid: true}}.Delegated())
+}
+
+func TestReceiptCheck(t *testing.T) {
+	t.Parallel()
+
+	vote := &Vote{CandidateRankings: []int{1, 2, 3}}
+	var receipt *VoteReceipt
+	assert.Equal(t, ReceiptUnconfirmed, receipt.Check(vote))
+
+	receipt = &VoteReceipt{CandidateRankings: []int{1, 2, 3}}
+	assert.Equal(t, ReceiptMatches, receipt.Check(vote))
+	assert.Equal(t, ReceiptMismatch, receipt.Check(&Vote{CandidateRankings: []int{2, 1, 3}}))
+	// Подтвержденный голос пропал из таблицы голосов
+	assert.Equal(t, ReceiptMismatch, receipt.Check(nil))
+}
@@ -0,0 +1,162 @@
+// Package polling получает обновления Telegram через getUpdates — альтернатива вебхуку,
+// которой не нужен публичный адрес
+package polling
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// Ключ смещения getUpdates в хранилище сессий (хранится бессрочно)
+const offsetKey = "polling:offset"
+
+// Сколько секунд Telegram держит запрос getUpdates, если новых обновлений нет
+const pollTimeout = 30
+
+// Время на обработку одного обновления, как у запроса вебхука
+const handleTimeout = 10 * time.Second
+
+// Пауза перед повтором после ошибки getUpdates
+const retryDelay = 3 * time.Second
+
+type botAPI interface {
+	Request(c tgbotapi.Chattable) (*tgbotapi.APIResponse, error)
+	GetUpdates(config tgbotapi.UpdateConfig) ([]tgbotapi.Update, error)
+}
+
+// Логгер проекта (internal/logger), который передает бот
+type logger interface {
+	Info(args ...any)
+	Infof(format string, args ...any)
+	Errorf(format string, args ...any)
+}
+
+type offsetStore interface {
+	Get(ctx context.Context, key string, dest any) (bool, error)
+	Set(ctx context.Context, key string, value any, ttl time.Duration) error
+}
+
+// Handler принимает обновление и вызывает done, когда закончил с ним, — сразу или позже из другой горутины
+// (например, из диспетчера). Пока done не вызван, сохраненное смещение не переходит через это обновление
+type Handler func(ctx context.Context, update tgbotapi.Update, done func())
+
+// Poller запрашивает обновления и передает их обработчику по одному
+type Poller struct {
+	botAPI  botAPI
+	store   offsetStore
+	log     logger
+	handler Handler
+
+	mu       sync.Mutex   // Защищает pending, received и saved
+	pending  map[int]bool // Переданные обработчику, но еще не обработанные обновления
+	received int          // Смещение после последнего полученного обновления
+	saved    int          // Последнее сохраненное смещение
+}
+
+// NewPoller создает получатель обновлений. Смещение сохраняется в store, чтобы после перезапуска
+// не обрабатывать обновления повторно и не терять необработанные
+func NewPoller(botAPI botAPI, store offsetStore, log logger, handler Handler) *Poller {
+	return &Poller{botAPI: botAPI, store: store, log: log, handler: handler, pending: make(map[int]bool)}
+}
+
+// Run удаляет вебхук и обрабатывает обновления до отмены контекста.
+// Начатое обновление обрабатывается до конца, поэтому после отмены Run возвращается не сразу
+func (p *Poller) Run(ctx context.Context) error {
+	// getUpdates не работает, пока у бота установлен вебхук. Ожидающие обновления сохраняются
+	if _, err := p.botAPI.Request(tgbotapi.DeleteWebhookConfig{}); err != nil {
+		return fmt.Errorf("polling.Poller.Run: delete webhook: %w", err)
+	}
+	var offset int
+	if _, err := p.store.Get(ctx, offsetKey, &offset); err != nil {
+		return fmt.Errorf("polling.Poller.Run: %w", err)
+	}
+	p.mu.Lock()
+	p.received, p.saved = offset, offset
+	p.mu.Unlock()
+	p.log.Infof("Long polling started from offset %d", offset)
+
+	for ctx.Err() == nil {
+		updates, err := p.getUpdates(ctx, offset)
+		if ctx.Err() != nil {
+			break
+		}
+		if err != nil {
+			p.log.Errorf("Failed to get updates: %v", err)
+			select {
+			case <-ctx.Done():
+			case <-time.After(retryDelay):
+			}
+			continue
+		}
+		for _, update := range updates {
+			// Следующий запрос getUpdates подтверждает полученные обновления, а сохраненное смещение
+			// продвигается только по обработанным: после перезапуска обновления, которые ждали в очереди
+			// или обрабатывались, будут получены снова
+			offset = update.UpdateID + 1
+			p.mu.Lock()
+			p.pending[update.UpdateID] = true
+			p.received = offset
+			p.mu.Unlock()
+			p.handle(ctx, update)
+		}
+	}
+	p.log.Info("Long polling stopped")
+	return nil
+}
+
+// getUpdates ждет ответа getUpdates или отмены контекста. Ответ на брошенный запрос не подтверждается
+// смещением, поэтому Telegram отправит эти обновления снова при следующем запуске
+func (p *Poller) getUpdates(ctx context.Context, offset int) ([]tgbotapi.Update, error) {
+	type result struct {
+		updates []tgbotapi.Update
+		err     error
+	}
+	done := make(chan result, 1)
+	go func() {
+		config := tgbotapi.NewUpdate(offset)
+		config.Timeout = pollTimeout
+		config.AllowedUpdates = []string{"message", "callback_query"}
+		updates, err := p.botAPI.GetUpdates(config)
+		done <- result{updates, err}
+	}()
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case r := <-done:
+		return r.updates, r.err
+	}
+}
+
+// handle передает одно обновление обработчику. Отмена ctx при остановке не прерывает начатую обработку
+func (p *Poller) handle(ctx context.Context, update tgbotapi.Update) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), handleTimeout)
+	defer cancel()
+	var once sync.Once
+	p.handler(ctx, update, func() {
+		once.Do(func() { p.done(update.UpdateID) })
+	})
+}
+
+// done отмечает обновление обработанным и сохраняет смещение до первого необработанного обновления.
+// Обработчики завершаются в любом порядке, поэтому смещение не обгоняет обновления, которые еще обрабатываются
+func (p *Poller) done(updateID int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	delete(p.pending, updateID)
+	offset := p.received
+	for id := range p.pending {
+		offset = min(offset, id)
+	}
+	if offset <= p.saved {
+		return
+	}
+	if err := p.store.Set(context.Background(), offsetKey, offset, 0); err != nil {
+		p.log.Errorf("Failed to save updates offset: %v", err)
+		return
+	}
+	p.saved = offset
+}
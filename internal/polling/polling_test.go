@@ -0,0 +1,154 @@
+package polling
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/lsdpls/schulze_election_telegram_bot/internal/dispatcher"
+	"github.com/lsdpls/schulze_election_telegram_bot/internal/session"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeBotAPI — поддельный Bot API: отдает обновления с ID не меньше запрошенного смещения
+type fakeBotAPI struct {
+	mu             sync.Mutex
+	updates        []tgbotapi.Update
+	offsets        []int // Смещения из запросов getUpdates
+	webhookDeletes int
+}
+
+func (f *fakeBotAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var result any
+	switch r.URL.Path {
+	case "/bottoken/getMe":
+		result = tgbotapi.User{ID: 1, IsBot: true, FirstName: "bot", UserName: "bot"}
+	case "/bottoken/deleteWebhook":
+		f.webhookDeletes++
+		result = true
+	case "/bottoken/getUpdates":
+		offset, _ := strconv.Atoi(r.FormValue("offset"))
+		f.offsets = append(f.offsets, offset)
+		updates := []tgbotapi.Update{}
+		for _, update := range f.updates {
+			if update.UpdateID >= offset {
+				updates = append(updates, update)
+			}
+		}
+		result = updates
+	default:
+		http.NotFound(w, r)
+		return
+	}
+	data, _ := json.Marshal(result)
+	json.NewEncoder(w).Encode(tgbotapi.APIResponse{Ok: true, Result: data})
+}
+
+func TestPoller(t *testing.T) {
+	t.Parallel()
+
+	fake := &fakeBotAPI{updates: []tgbotapi.Update{
+		{UpdateID: 5, Message: &tgbotapi.Message{Text: "/start"}},
+		{UpdateID: 6, Message: &tgbotapi.Message{Text: "/vote"}},
+	}}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+	api, err := tgbotapi.NewBotAPIWithAPIEndpoint("token", server.URL+"/bot%s/%s")
+	require.NoError(t, err)
+	store := session.NewMemoryStore()
+
+	// Обработчик останавливает получение после последнего известного обновления
+	run := func(lastID int) []int {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		var handled []int
+		poller := NewPoller(api, store, logrus.StandardLogger(), func(_ context.Context, update tgbotapi.Update, done func()) {
+			defer done()
+			handled = append(handled, update.UpdateID)
+			if update.UpdateID == lastID {
+				cancel()
+			}
+		})
+		require.NoError(t, poller.Run(ctx))
+		return handled
+	}
+
+	assert.Equal(t, []int{5, 6}, run(6))
+	var offset int
+	ok, err := store.Get(context.Background(), offsetKey, &offset)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, 7, offset)
+
+	// После перезапуска обработанные обновления не повторяются
+	fake.mu.Lock()
+	fake.updates = append(fake.updates, tgbotapi.Update{UpdateID: 7, Message: &tgbotapi.Message{Text: "/help"}})
+	fake.mu.Unlock()
+	assert.Equal(t, []int{7}, run(7))
+
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	assert.Equal(t, 2, fake.webhookDeletes)
+	assert.Equal(t, []int{0, 7}, fake.offsets)
+}
+
+// С диспетчером обработчик только ставит обновление в очередь: смещение не должно обгонять обновления,
+// которые еще ждут в очереди или обрабатываются
+func TestPollerWithDispatcher(t *testing.T) {
+	t.Parallel()
+
+	message := func(updateID int, userID int64) tgbotapi.Update {
+		return tgbotapi.Update{UpdateID: updateID, Message: &tgbotapi.Message{
+			From: &tgbotapi.User{ID: userID}, Chat: &tgbotapi.Chat{ID: userID}, Text: "/start"}}
+	}
+	fake := &fakeBotAPI{updates: []tgbotapi.Update{message(5, 1), message(6, 2)}}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+	api, err := tgbotapi.NewBotAPIWithAPIEndpoint("token", server.URL+"/bot%s/%s")
+	require.NoError(t, err)
+	store := session.NewMemoryStore()
+	savedOffset := func() int {
+		var offset int
+		_, err := store.Get(context.Background(), offsetKey, &offset)
+		require.NoError(t, err)
+		return offset
+	}
+
+	// Обновление 5 обрабатывается, пока его не отпустят; обновление 6 другого пользователя обрабатывается сразу
+	release := make(chan struct{})
+	handled := make(chan int, 2)
+	d := dispatcher.New(2, func(_ context.Context, update tgbotapi.Update) {
+		if update.UpdateID == 5 {
+			<-release
+		}
+		handled <- update.UpdateID
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	poller := NewPoller(api, store, logrus.StandardLogger(), func(ctx context.Context, update tgbotapi.Update, done func()) {
+		require.NoError(t, d.DispatchNotify(ctx, update, done))
+		if update.UpdateID == 6 {
+			cancel()
+		}
+	})
+	require.NoError(t, poller.Run(ctx))
+
+	assert.Equal(t, 6, <-handled)
+	// Обновление 5 еще обрабатывается: после сбоя получение начнется с него, и оба обновления придут снова
+	assert.Eventually(t, func() bool { return savedOffset() == 5 }, time.Second, time.Millisecond)
+
+	close(release)
+	assert.Equal(t, 5, <-handled)
+	d.Close()
+	assert.Equal(t, 7, savedOffset())
+}
@@ -0,0 +1,93 @@
+// Package ratelimit ограничивает частоту действий по ключу (например, Telegram ID) алгоритмом token bucket
+package ratelimit
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// Limiter хранит по корзине токенов на ключ. Корзина вмещает burst токенов и пополняется на один токен
+// каждые interval; каждое действие расходует токен. Безопасен для параллельного использования
+type Limiter struct {
+	interval time.Duration
+	burst    int
+	now      func() time.Time
+
+	mu      sync.Mutex
+	buckets map[int64]*bucket
+	checked time.Time // Время последней очистки заполненных корзин
+}
+
+type bucket struct {
+	tokens  float64
+	updated time.Time
+}
+
+// New создает ограничитель: не более burst действий подряд и далее одно действие в interval
+func New(interval time.Duration, burst int) *Limiter {
+	if burst < 1 {
+		burst = 1
+	}
+	return &Limiter{interval: interval, burst: burst, now: time.Now, buckets: make(map[int64]*bucket)}
+}
+
+// Allow расходует токен ключа. false — токенов нет и действие нужно отклонить
+func (l *Limiter) Allow(key int64) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := l.now()
+	l.cleanup(now)
+
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(l.burst), updated: now}
+		l.buckets[key] = b
+	}
+	b.tokens = l.refill(b, now)
+	b.updated = now
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+// RetryAfter возвращает, через сколько у ключа появится токен. 0 — действие разрешено уже сейчас
+func (l *Limiter) RetryAfter(key int64) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	b, ok := l.buckets[key]
+	if !ok {
+		return 0
+	}
+	tokens := l.refill(b, l.now())
+	if tokens >= 1 {
+		return 0
+	}
+	return time.Duration(math.Ceil((1 - tokens) * float64(l.interval)))
+}
+
+// refill возвращает число токенов корзины к моменту now
+func (l *Limiter) refill(b *bucket, now time.Time) float64 {
+	if l.interval <= 0 {
+		return float64(l.burst)
+	}
+	tokens := b.tokens + float64(now.Sub(b.updated))/float64(l.interval)
+	return min(tokens, float64(l.burst))
+}
+
+// cleanup удаляет заполненные корзины: они ничем не отличаются от новых. Выполняется не чаще,
+// чем корзина заполняется с нуля, поэтому не замедляет Allow
+func (l *Limiter) cleanup(now time.Time) {
+	fillTime := l.interval * time.Duration(l.burst)
+	if now.Sub(l.checked) < fillTime {
+		return
+	}
+	l.checked = now
+	for key, b := range l.buckets {
+		if l.refill(b, now) >= float64(l.burst) {
+			delete(l.buckets, key)
+		}
+	}
+}
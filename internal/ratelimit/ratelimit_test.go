@@ -0,0 +1,63 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLimiter(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	limiter := New(10*time.Second, 3)
+	limiter.now = func() time.Time { return now }
+
+	// Корзина позволяет burst действий подряд
+	for i := 0; i < 3; i++ {
+		assert.True(t, limiter.Allow(1), i)
+	}
+	assert.False(t, limiter.Allow(1))
+	// Ключи не влияют друг на друга
+	assert.True(t, limiter.Allow(2))
+
+	// Токен восстанавливается через interval
+	assert.Equal(t, 10*time.Second, limiter.RetryAfter(1))
+	assert.Zero(t, limiter.RetryAfter(2))
+	assert.Zero(t, limiter.RetryAfter(3), "unknown key")
+	now = now.Add(9 * time.Second)
+	assert.Equal(t, time.Second, limiter.RetryAfter(1))
+	assert.False(t, limiter.Allow(1))
+	now = now.Add(time.Second)
+	assert.True(t, limiter.Allow(1))
+	assert.False(t, limiter.Allow(1))
+
+	// Корзина не наполняется сверх burst
+	now = now.Add(time.Hour)
+	for i := 0; i < 3; i++ {
+		assert.True(t, limiter.Allow(1), i)
+	}
+	assert.False(t, limiter.Allow(1))
+}
+
+func TestLimiterCleanup(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	limiter := New(time.Second, 2)
+	limiter.now = func() time.Time { return now }
+
+	limiter.Allow(1)
+	limiter.Allow(2)
+	assert.Len(t, limiter.buckets, 2)
+
+	// Заполненные корзины удаляются, использованные — остаются
+	now = now.Add(time.Minute)
+	limiter.Allow(3)
+	limiter.Allow(3)
+	assert.Len(t, limiter.buckets, 1)
+	now = now.Add(time.Minute)
+	assert.True(t, limiter.Allow(4))
+	assert.Len(t, limiter.buckets, 1)
+}
@@ -154,6 +154,11 @@ func (s *Schulze) computeStrongestPaths(preferences map[int]map[int]int, candida
 
 // Шаг 3: Нахождение потенциальных победителей
 func (s *Schulze) findPotentialWinners(strongestPaths map[int]map[int]int, candidates []models.Candidate) []models.Candidate {
+	return potentialWinners(strongestPaths, candidates)
+}
+
+// potentialWinners возвращает кандидатов, которых никто из candidates не побеждает по сильнейшим путям
+func potentialWinners(strongestPaths map[int]map[int]int, candidates []models.Candidate) []models.Candidate {
 	potentialWinners := make([]models.Candidate, 0)
 	// Проверяем, есть ли однозначный победитель
 	for _, candidate := range candidates {
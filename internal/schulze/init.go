@@ -41,6 +41,8 @@ type chain interface {
 	AddResult(ctx context.Context, result models.Result) error
 	GetAllResults(ctx context.Context) ([]models.Result, error)
 	GetCandidateByCandidateID(ctx context.Context, candidateID int) (*models.Candidate, error)
+	GetAllCandidates(ctx context.Context) ([]models.Candidate, error)
+	GetAllDelegates(ctx context.Context) ([]models.Delegate, error)
 }
 
 func (s *Schulze) SetCandidates() error {
@@ -0,0 +1,384 @@
+package schulze
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/lsdpls/schulze_election_telegram_bot/internal/config"
+	"github.com/lsdpls/schulze_election_telegram_bot/internal/models"
+
+	"github.com/jung-kurt/gofpdf"
+)
+
+const (
+	pdfFont       = "DejaVu"
+	pdfPageWidth  = 190.0 // Ширина рабочей области A4 с полями 10 мм
+	pdfLineHeight = 6.0
+)
+
+// BuildProtocolPDF формирует официальный протокол голосования в формате PDF
+func (s *Schulze) BuildProtocolPDF(ctx context.Context) ([]byte, error) {
+	results, err := s.voteChain.GetAllResults(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("BuildProtocolPDF: failed to get results: %w", err)
+	}
+	if len(results) == 0 {
+		return nil, fmt.Errorf("BuildProtocolPDF: no results found")
+	}
+	candidates, err := s.voteChain.GetAllCandidates(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("BuildProtocolPDF: failed to get candidates: %w", err)
+	}
+	delegates, err := s.voteChain.GetAllDelegates(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("BuildProtocolPDF: failed to get delegates: %w", err)
+	}
+	votes, err := s.voteChain.GetAllVotes(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("BuildProtocolPDF: failed to get votes: %w", err)
+	}
+
+	candidatesByID := make(map[int]models.Candidate, len(candidates))
+	for _, candidate := range candidates {
+		candidatesByID[candidate.CandidateID] = candidate
+	}
+	courseResults, commonResult := splitResults(results)
+
+	pdf := gofpdf.New("P", "mm", "A4", config.PDFFontDir)
+	pdf.SetMargins(10, 10, 10)
+	pdf.SetAutoPageBreak(true, 15)
+	pdf.AddUTF8Font(pdfFont, "", "DejaVuSansCondensed.ttf")
+	pdf.AddUTF8Font(pdfFont, "B", "DejaVuSansCondensed-Bold.ttf")
+	pdf.AliasNbPages("")
+	pdf.SetFooterFunc(func() {
+		pdf.SetY(-12)
+		pdf.SetFont(pdfFont, "", 8)
+		pdf.CellFormat(0, 5, fmt.Sprintf("Страница %d из {nb}", pdf.PageNo()), "", 0, "C", false, 0, "")
+	})
+	pdf.AddPage()
+
+	// 1. Шапка и метаданные выборов
+	pdf.SetFont(pdfFont, "B", 14)
+	pdf.CellFormat(0, 8, "ПРОТОКОЛ", "", 1, "C", false, 0, "")
+	pdf.SetFont(pdfFont, "", 11)
+	pdf.MultiCell(0, pdfLineHeight, "счётной комиссии об итогах голосования: "+config.ElectionName, "", "C", false)
+	pdf.Ln(4)
+
+	registered, voted := 0, 0
+	for _, delegate := range delegates {
+		if delegate.TelegramID.Valid {
+			registered++
+		}
+		if delegate.HasVoted {
+			voted++
+		}
+	}
+	eligible := 0
+	for _, candidate := range candidates {
+		if candidate.IsEligible {
+			eligible++
+		}
+	}
+	pdfKeyValue(pdf, "Дата составления протокола", time.Now().Format("02.01.2006 15:04"))
+	pdfKeyValue(pdf, "Метод подсчёта голосов", "метод Шульце (ранжированное голосование)")
+	pdfKeyValue(pdf, "Количество мест в совете", strconv.Itoa(config.TotalPlaces))
+	pdfKeyValue(pdf, "Кандидатов (допущено / всего)", fmt.Sprintf("%d / %d", eligible, len(candidates)))
+	pdfKeyValue(pdf, "Делегатов в списке", strconv.Itoa(len(delegates)))
+	pdfKeyValue(pdf, "Зарегистрировано делегатов", strconv.Itoa(registered))
+	pdfKeyValue(pdf, "Принято бюллетеней", strconv.Itoa(len(votes)))
+	pdfKeyValue(pdf, "Явка", formatTurnout(voted, len(delegates)))
+
+	// 2. Явка по группам делегатов
+	pdfSection(pdf, "1. Явка по группам делегатов")
+	pdfTable(pdf, []string{"Группа", "Делегатов", "Зарегистрировано", "Проголосовало", "Явка"},
+		[]float64{50, 35, 40, 35, 30}, turnoutRows(delegates))
+
+	// 3. Список кандидатов с допуском
+	pdfSection(pdf, "2. Список кандидатов")
+	candidateRows := make([][]string, 0, len(candidates))
+	for _, candidate := range sortedCandidates(candidates) {
+		eligibleStatus := "допущен"
+		if !candidate.IsEligible {
+			eligibleStatus = "не допущен"
+		}
+		candidateRows = append(candidateRows, []string{
+			"st" + idtos(candidate.CandidateID), candidate.Name, candidate.Course, eligibleStatus,
+		})
+	}
+	pdfTable(pdf, []string{"ID", "ФИО", "Курс", "Допуск"}, []float64{25, 80, 50, 35}, candidateRows)
+
+	// 4. Итоги по курсам
+	pdfSection(pdf, "3. Итоги голосования по курсам")
+	courseRows := make([][]string, 0, len(courseResults))
+	for _, result := range courseResults {
+		courseRows = append(courseRows, []string{
+			result.Course, winnersToString(result.WinnerCandidateID, candidatesByID), StageDescription(result.Stage),
+		})
+	}
+	pdfTable(pdf, []string{"Курс", "Победитель", "Этап определения"}, []float64{40, 80, 70}, courseRows)
+
+	// 5. Порядок распределения общих мест
+	pdfSection(pdf, "4. Распределение общих мест")
+	if commonResult == nil {
+		pdf.SetFont(pdfFont, "", 10)
+		pdf.MultiCell(0, pdfLineHeight, "Общие места не распределялись.", "", "L", false)
+	} else {
+		commonRows := make([][]string, 0, len(commonResult.WinnerCandidateID))
+		for i, candidateID := range commonResult.WinnerCandidateID {
+			candidate := candidatesByID[candidateID]
+			commonRows = append(commonRows, []string{strconv.Itoa(i + 1), candidate.Name, candidate.Course})
+		}
+		pdfTable(pdf, []string{"Место", "ФИО", "Курс"}, []float64{20, 110, 60}, commonRows)
+	}
+
+	// 6. Матрицы парных предпочтений и сильнейших путей
+	matrixResults := courseResults
+	if commonResult != nil {
+		matrixResults = append(matrixResults, *commonResult)
+	}
+	for i, result := range matrixResults {
+		pdf.AddPage()
+		pdfSection(pdf, fmt.Sprintf("5.%d. Матрицы: %s", i+1, result.Course))
+		order := matrixOrder(result.Preferences)
+		pdfLegend(pdf, order, candidatesByID)
+		pdf.SetFont(pdfFont, "B", 10)
+		pdf.CellFormat(0, pdfLineHeight, "Таблица парных предпочтений d[строка][столбец]", "", 1, "L", false, 0, "")
+		pdfMatrix(pdf, result.Preferences, order)
+		pdf.SetFont(pdfFont, "B", 10)
+		pdf.CellFormat(0, pdfLineHeight, "Таблица сильнейших путей p[строка][столбец]", "", 1, "L", false, 0, "")
+		pdfMatrix(pdf, result.StrongestPaths, order)
+	}
+
+	// 7. Подписи членов комиссии
+	pdfSignatures(pdf)
+
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, fmt.Errorf("BuildProtocolPDF: failed to render pdf: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// splitResults разделяет результаты на итоги по курсам и общие места
+func splitResults(results []models.Result) ([]models.Result, *models.Result) {
+	var courseResults []models.Result
+	var commonResult *models.Result
+	for i := range results {
+		if results[i].Stage == "common" {
+			commonResult = &results[i]
+			continue
+		}
+		courseResults = append(courseResults, results[i])
+	}
+	sort.Slice(courseResults, func(i, j int) bool {
+		return courseResults[i].Course < courseResults[j].Course
+	})
+	return courseResults, commonResult
+}
+
+// turnoutRows считает явку по группам делегатов
+func turnoutRows(delegates []models.Delegate) [][]string {
+	type groupTurnout struct {
+		total, registered, voted int
+	}
+	byGroup := make(map[string]*groupTurnout)
+	groups := make([]string, 0)
+	for _, delegate := range delegates {
+		turnout, ok := byGroup[delegate.Group]
+		if !ok {
+			turnout = &groupTurnout{}
+			byGroup[delegate.Group] = turnout
+			groups = append(groups, delegate.Group)
+		}
+		turnout.total++
+		if delegate.TelegramID.Valid {
+			turnout.registered++
+		}
+		if delegate.HasVoted {
+			turnout.voted++
+		}
+	}
+	slices.Sort(groups)
+
+	rows := make([][]string, 0, len(groups)+1)
+	var total groupTurnout
+	for _, group := range groups {
+		turnout := byGroup[group]
+		total.total += turnout.total
+		total.registered += turnout.registered
+		total.voted += turnout.voted
+		rows = append(rows, []string{
+			group, strconv.Itoa(turnout.total), strconv.Itoa(turnout.registered),
+			strconv.Itoa(turnout.voted), formatTurnout(turnout.voted, turnout.total),
+		})
+	}
+	rows = append(rows, []string{
+		"Итого", strconv.Itoa(total.total), strconv.Itoa(total.registered),
+		strconv.Itoa(total.voted), formatTurnout(total.voted, total.total),
+	})
+	return rows
+}
+
+// formatTurnout форматирует явку в виде "n (x.x%)"
+func formatTurnout(voted, total int) string {
+	if total == 0 {
+		return "0 (0.0%)"
+	}
+	return fmt.Sprintf("%d (%.1f%%)", voted, float64(voted)*100/float64(total))
+}
+
+// sortedCandidates возвращает кандидатов, отсортированных по курсу и имени
+func sortedCandidates(candidates []models.Candidate) []models.Candidate {
+	sorted := make([]models.Candidate, len(candidates))
+	copy(sorted, candidates)
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].Course != sorted[j].Course {
+			return sorted[i].Course < sorted[j].Course
+		}
+		return sorted[i].Name < sorted[j].Name
+	})
+	return sorted
+}
+
+// winnersToString перечисляет имена победителей через запятую
+func winnersToString(winnerIDs []int, candidatesByID map[int]models.Candidate) string {
+	var winners string
+	for i, winnerID := range winnerIDs {
+		if i > 0 {
+			winners += ", "
+		}
+		if candidate, ok := candidatesByID[winnerID]; ok {
+			winners += candidate.Name
+		} else {
+			winners += "st" + idtos(winnerID)
+		}
+	}
+	return winners
+}
+
+// matrixOrder возвращает отсортированный порядок кандидатов матрицы
+func matrixOrder(matrix map[int]map[int]int) []int {
+	order := make([]int, 0, len(matrix))
+	for candidateID := range matrix {
+		order = append(order, candidateID)
+	}
+	slices.Sort(order)
+	return order
+}
+
+func pdfSection(pdf *gofpdf.Fpdf, title string) {
+	pdf.Ln(4)
+	pdf.SetFont(pdfFont, "B", 12)
+	pdf.MultiCell(0, 7, title, "", "L", false)
+	pdf.Ln(1)
+}
+
+func pdfKeyValue(pdf *gofpdf.Fpdf, key, value string) {
+	pdf.SetFont(pdfFont, "", 10)
+	pdf.CellFormat(80, pdfLineHeight, key+":", "", 0, "L", false, 0, "")
+	pdf.SetFont(pdfFont, "B", 10)
+	pdf.CellFormat(0, pdfLineHeight, value, "", 1, "L", false, 0, "")
+}
+
+// pdfTable выводит простую таблицу с заголовком
+func pdfTable(pdf *gofpdf.Fpdf, header []string, widths []float64, rows [][]string) {
+	pdf.SetFont(pdfFont, "B", 9)
+	pdf.SetFillColor(230, 230, 230)
+	for i, title := range header {
+		pdf.CellFormat(widths[i], 7, title, "1", 0, "C", true, 0, "")
+	}
+	pdf.Ln(-1)
+	pdf.SetFont(pdfFont, "", 9)
+	for _, row := range rows {
+		for i, cell := range row {
+			pdf.CellFormat(widths[i], 6, truncateToWidth(pdf, cell, widths[i]-2), "1", 0, "L", false, 0, "")
+		}
+		pdf.Ln(-1)
+	}
+}
+
+// pdfLegend выводит соответствие номеров столбцов матрицы кандидатам
+func pdfLegend(pdf *gofpdf.Fpdf, order []int, candidatesByID map[int]models.Candidate) {
+	pdf.SetFont(pdfFont, "", 9)
+	for i, candidateID := range order {
+		name := "st" + idtos(candidateID)
+		if candidate, ok := candidatesByID[candidateID]; ok {
+			name = fmt.Sprintf("%s (st%s)", candidate.Name, idtos(candidateID))
+		}
+		pdf.CellFormat(0, 5, fmt.Sprintf("%d — %s", i+1, name), "", 1, "L", false, 0, "")
+	}
+	pdf.Ln(2)
+}
+
+// pdfMatrix выводит матрицу кандидатов, подписывая строки и столбцы номерами из легенды
+func pdfMatrix(pdf *gofpdf.Fpdf, matrix map[int]map[int]int, order []int) {
+	labelWidth := 12.0
+	cellWidth := (pdfPageWidth - labelWidth) / float64(max(len(order), 1))
+	cellWidth = min(cellWidth, 18)
+	fontSize := min(9, max(5, cellWidth/1.6))
+
+	pdf.SetFont(pdfFont, "B", fontSize)
+	pdf.SetFillColor(230, 230, 230)
+	pdf.CellFormat(labelWidth, 6, "", "1", 0, "C", true, 0, "")
+	for i := range order {
+		pdf.CellFormat(cellWidth, 6, strconv.Itoa(i+1), "1", 0, "C", true, 0, "")
+	}
+	pdf.Ln(-1)
+	for i, rowID := range order {
+		pdf.SetFont(pdfFont, "B", fontSize)
+		pdf.CellFormat(labelWidth, 6, strconv.Itoa(i+1), "1", 0, "C", true, 0, "")
+		pdf.SetFont(pdfFont, "", fontSize)
+		for _, colID := range order {
+			value := "—"
+			if rowID != colID {
+				if v, ok := matrix[rowID][colID]; ok {
+					value = strconv.Itoa(v)
+				}
+			}
+			pdf.CellFormat(cellWidth, 6, value, "1", 0, "C", false, 0, "")
+		}
+		pdf.Ln(-1)
+	}
+	pdf.Ln(4)
+}
+
+// pdfSignatures выводит строки для подписей членов счётной комиссии
+func pdfSignatures(pdf *gofpdf.Fpdf) {
+	pdf.Ln(6)
+	if pdf.GetY() > 220 {
+		pdf.AddPage()
+	}
+	pdfSection(pdf, "Подписи членов счётной комиссии")
+	pdf.SetFont(pdfFont, "", 10)
+	roles := []string{"Председатель комиссии", "Секретарь комиссии", "Член комиссии", "Член комиссии", "Член комиссии"}
+	for _, role := range roles {
+		pdf.Ln(6)
+		pdf.CellFormat(60, pdfLineHeight, role, "", 0, "L", false, 0, "")
+		pdf.CellFormat(55, pdfLineHeight, "_______________", "", 0, "C", false, 0, "")
+		pdf.CellFormat(0, pdfLineHeight, "/ ______________________ /", "", 1, "C", false, 0, "")
+		pdf.SetFont(pdfFont, "", 7)
+		pdf.CellFormat(60, 4, "", "", 0, "L", false, 0, "")
+		pdf.CellFormat(55, 4, "подпись", "", 0, "C", false, 0, "")
+		pdf.CellFormat(0, 4, "расшифровка", "", 1, "C", false, 0, "")
+		pdf.SetFont(pdfFont, "", 10)
+	}
+	pdf.Ln(6)
+	pdf.CellFormat(0, pdfLineHeight, "Дата подписания: «____» ______________ 20____ г.", "", 1, "L", false, 0, "")
+}
+
+// truncateToWidth обрезает текст, чтобы он поместился в ячейку
+func truncateToWidth(pdf *gofpdf.Fpdf, text string, width float64) string {
+	if pdf.GetStringWidth(text) <= width {
+		return text
+	}
+	runes := []rune(text)
+	for len(runes) > 0 && pdf.GetStringWidth(string(runes)+"…") > width {
+		runes = runes[:len(runes)-1]
+	}
+	return string(runes) + "…"
+}
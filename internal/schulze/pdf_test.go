@@ -0,0 +1,133 @@
+package schulze
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/lsdpls/schulze_election_telegram_bot/internal/config"
+	"github.com/lsdpls/schulze_election_telegram_bot/internal/models"
+	mock "github.com/lsdpls/schulze_election_telegram_bot/internal/schulze/mocks"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// pdfFontDir возвращает каталог со шрифтами DejaVu: системный или из модуля gofpdf
+func pdfFontDir(t *testing.T) string {
+	t.Helper()
+	if _, err := os.Stat(filepath.Join(config.PDFFontDir, "DejaVuSansCondensed.ttf")); config.PDFFontDir != "" && err == nil {
+		return config.PDFFontDir
+	}
+	out, err := exec.Command("go", "list", "-m", "-f", "{{.Dir}}", "github.com/jung-kurt/gofpdf").Output()
+	if err != nil {
+		t.Skipf("DejaVu fonts not found: %v", err)
+	}
+	return filepath.Join(strings.TrimSpace(string(out)), "font")
+}
+
+func TestBuildProtocolPDF(t *testing.T) {
+	config.PDFFontDir = pdfFontDir(t)
+	config.TotalPlaces = 3
+
+	candidates := []models.Candidate{
+		{CandidateID: 111111, Name: "Иванов Иван", Course: "1 курс", IsEligible: true},
+		{CandidateID: 222222, Name: "Петров Петр", Course: "1 курс", IsEligible: true},
+		{CandidateID: 333333, Name: "Сидорова Анна", Course: "2 курс", IsEligible: false},
+	}
+	courseResult := models.Result{
+		Course:            "1 курс",
+		Stage:             "absolute",
+		WinnerCandidateID: []int{111111},
+		Preferences:       map[int]map[int]int{111111: {222222: 2}, 222222: {111111: 1}},
+		StrongestPaths:    map[int]map[int]int{111111: {222222: 2}, 222222: {111111: 0}},
+	}
+	tests := []struct {
+		name       string
+		results    []models.Result
+		candidates []models.Candidate
+		delegates  []models.Delegate
+		votes      []models.Vote
+	}{
+		{
+			name:       "CoursesAndCommonPlaces",
+			results:    []models.Result{courseResult, {Course: "common", Stage: "common", WinnerCandidateID: []int{222222}}},
+			candidates: candidates,
+			delegates: []models.Delegate{
+				{DelegateID: 100001, Group: "21.Б01-пу", TelegramID: sql.NullInt64{Int64: 1, Valid: true}, HasVoted: true},
+				{DelegateID: 100002, Group: "21.Б01-пу", TelegramID: sql.NullInt64{Int64: 2, Valid: true}, HasVoted: true},
+				{DelegateID: 100003, Group: "22.Б02-пу"},
+			},
+			votes: []models.Vote{
+				{DelegateID: 100001, CandidateRankings: []int{111111, 222222}},
+				{DelegateID: 100002, CandidateRankings: []int{222222, 111111}},
+			},
+		},
+		{
+			// Курс без кандидатов и голосов: пустые матрицы и нет победителя
+			name:       "EmptyCourse",
+			results:    []models.Result{courseResult, {Course: "2 курс", Stage: "tie"}},
+			candidates: candidates,
+			delegates:  []models.Delegate{{DelegateID: 100001, Group: "21.Б01-пу"}},
+		},
+		{
+			// Никто не зарегистрировался и не проголосовал
+			name:    "ZeroTurnout",
+			results: []models.Result{{Course: "1 курс", Stage: "tie"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockChain := mock.NewMockchain(ctrl)
+			mockChain.EXPECT().GetAllResults(gomock.Any()).Return(tt.results, nil)
+			mockChain.EXPECT().GetAllCandidates(gomock.Any()).Return(tt.candidates, nil)
+			mockChain.EXPECT().GetAllDelegates(gomock.Any()).Return(tt.delegates, nil)
+			mockChain.EXPECT().GetAllVotes(gomock.Any()).Return(tt.votes, nil)
+
+			s := &Schulze{voteChain: mockChain}
+			protocol, err := s.BuildProtocolPDF(context.Background())
+			require.NoError(t, err)
+			assert.True(t, bytes.HasPrefix(protocol, []byte("%PDF-")))
+			assert.Contains(t, string(bytes.TrimSpace(protocol[len(protocol)-16:])), "%%EOF")
+		})
+	}
+
+	t.Run("NoResults", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockChain := mock.NewMockchain(ctrl)
+		mockChain.EXPECT().GetAllResults(gomock.Any()).Return(nil, nil)
+
+		s := &Schulze{voteChain: mockChain}
+		_, err := s.BuildProtocolPDF(context.Background())
+		assert.Error(t, err)
+	})
+}
+
+func TestTurnoutRows(t *testing.T) {
+	t.Parallel()
+
+	rows := turnoutRows([]models.Delegate{
+		{Group: "22.Б02-пу"},
+		{Group: "21.Б01-пу", TelegramID: sql.NullInt64{Int64: 1, Valid: true}, HasVoted: true},
+		{Group: "21.Б01-пу", TelegramID: sql.NullInt64{Int64: 2, Valid: true}},
+	})
+	assert.Equal(t, [][]string{
+		{"21.Б01-пу", "2", "2", "1", "1 (50.0%)"},
+		{"22.Б02-пу", "1", "0", "0", "0 (0.0%)"},
+		{"Итого", "3", "2", "1", "1 (33.3%)"},
+	}, rows)
+
+	// Без делегатов остается только итоговая строка с нулевой явкой
+	assert.Equal(t, [][]string{{"Итого", "0", "0", "0", "0 (0.0%)"}}, turnoutRows(nil))
+}
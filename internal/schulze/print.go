@@ -106,3 +106,19 @@ func idtos(number int) string {
 	}
 	return numberStr
 }
+
+// StageDescription возвращает пояснение к этапу, на котором получен результат
+func StageDescription(stage string) string {
+	switch stage {
+	case "absolute":
+		return "единственный победитель по методу Шульце"
+	case "tie-breaker":
+		return "победитель определён разрешением ничьей"
+	case "tie":
+		return "ничья не разрешена, требуется решение комиссии"
+	case "common":
+		return "строгий порядок кандидатов на общие места"
+	default:
+		return stage
+	}
+}
@@ -0,0 +1,56 @@
+package schulze
+
+import (
+	"slices"
+
+	"github.com/lsdpls/schulze_election_telegram_bot/internal/models"
+)
+
+// Ranking возвращает полный порядок кандидатов результата по методу Шульце — группы кандидатов, делящих место.
+// Первыми идут объявленные победители: при ничьей одной группой, для общих мест — в строгом порядке результата.
+// Остальные кандидаты матрицы ранжируются повторным поиском потенциальных победителей среди оставшихся
+func Ranking(result models.Result) [][]int {
+	remaining := make([]models.Candidate, 0, len(result.StrongestPaths))
+	for candidateID := range result.StrongestPaths {
+		remaining = append(remaining, models.Candidate{CandidateID: candidateID})
+	}
+	slices.SortFunc(remaining, func(a, b models.Candidate) int { return a.CandidateID - b.CandidateID })
+	inResult := func(candidateID int) bool {
+		return slices.ContainsFunc(remaining, func(c models.Candidate) bool { return c.CandidateID == candidateID })
+	}
+
+	var ranking [][]int
+	winners := make([]int, 0, len(result.WinnerCandidateID))
+	for _, candidateID := range result.WinnerCandidateID {
+		if inResult(candidateID) {
+			winners = append(winners, candidateID)
+		}
+	}
+	switch {
+	case len(winners) == 0:
+	case result.Stage == "tie":
+		ranking = append(ranking, winners)
+	default:
+		for _, candidateID := range winners {
+			ranking = append(ranking, []int{candidateID})
+		}
+	}
+	for _, candidateID := range winners {
+		remaining = ignoreCandidate(remaining, candidateID)
+	}
+
+	for len(remaining) > 0 {
+		group := potentialWinners(result.StrongestPaths, remaining)
+		if len(group) == 0 {
+			// Отношение «побеждает по сильнейшим путям» транзитивно, поэтому так не бывает; оставшиеся делят место
+			group = remaining
+		}
+		ids := make([]int, 0, len(group))
+		for _, candidate := range group {
+			ids = append(ids, candidate.CandidateID)
+			remaining = ignoreCandidate(remaining, candidate.CandidateID)
+		}
+		ranking = append(ranking, ids)
+	}
+	return ranking
+}
@@ -0,0 +1,319 @@
+package schulze
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/lsdpls/schulze_election_telegram_bot/internal/models"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// xlsxStyles хранит идентификаторы стилей книги
+type xlsxStyles struct {
+	title    int // Заголовок листа
+	header   int // Шапка таблицы
+	diagonal int // Диагональ матрицы
+	win      int // Условный формат: победа в паре
+	loss     int // Условный формат: поражение в паре
+}
+
+// BuildResultsXLSX формирует книгу Excel с результатами голосования и списком делегатов
+func (s *Schulze) BuildResultsXLSX(ctx context.Context) ([]byte, error) {
+	results, err := s.voteChain.GetAllResults(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("BuildResultsXLSX: failed to get results: %w", err)
+	}
+	if len(results) == 0 {
+		return nil, fmt.Errorf("BuildResultsXLSX: no results found")
+	}
+	candidates, err := s.voteChain.GetAllCandidates(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("BuildResultsXLSX: failed to get candidates: %w", err)
+	}
+	delegates, err := s.voteChain.GetAllDelegates(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("BuildResultsXLSX: failed to get delegates: %w", err)
+	}
+
+	candidatesByID := make(map[int]models.Candidate, len(candidates))
+	for _, candidate := range candidates {
+		candidatesByID[candidate.CandidateID] = candidate
+	}
+	courseResults, commonResult := splitResults(results)
+
+	f := excelize.NewFile()
+	defer f.Close()
+	styles, err := newXLSXStyles(f)
+	if err != nil {
+		return nil, fmt.Errorf("BuildResultsXLSX: %w", err)
+	}
+
+	// Первый лист по умолчанию переименовывается в лист первого курса
+	defaultSheet := f.GetSheetName(0)
+	usedNames := make(map[string]bool)
+	for i, result := range courseResults {
+		sheet := xlsxSheetName(result.Course, usedNames)
+		if i == 0 {
+			err = f.SetSheetName(defaultSheet, sheet)
+		} else {
+			_, err = f.NewSheet(sheet)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("BuildResultsXLSX: failed to create sheet %q: %w", sheet, err)
+		}
+		if err := writeResultSheet(f, sheet, result, candidatesByID, styles); err != nil {
+			return nil, fmt.Errorf("BuildResultsXLSX: %w", err)
+		}
+	}
+	if len(courseResults) == 0 {
+		if err := f.DeleteSheet(defaultSheet); err != nil {
+			return nil, fmt.Errorf("BuildResultsXLSX: failed to delete default sheet: %w", err)
+		}
+	}
+
+	if commonResult != nil {
+		sheet := xlsxSheetName("Общие места", usedNames)
+		if _, err := f.NewSheet(sheet); err != nil {
+			return nil, fmt.Errorf("BuildResultsXLSX: failed to create sheet %q: %w", sheet, err)
+		}
+		if err := writeCommonSheet(f, sheet, *commonResult, candidatesByID, styles); err != nil {
+			return nil, fmt.Errorf("BuildResultsXLSX: %w", err)
+		}
+	}
+
+	turnoutSheet := xlsxSheetName("Явка", usedNames)
+	if _, err := f.NewSheet(turnoutSheet); err != nil {
+		return nil, fmt.Errorf("BuildResultsXLSX: failed to create sheet %q: %w", turnoutSheet, err)
+	}
+	if err := writeTable(f, turnoutSheet, 1,
+		[]string{"Группа", "Делегатов", "Зарегистрировано", "Проголосовало", "Явка"},
+		turnoutRows(delegates), styles); err != nil {
+		return nil, fmt.Errorf("BuildResultsXLSX: %w", err)
+	}
+
+	rosterSheet := xlsxSheetName("Делегаты", usedNames)
+	if _, err := f.NewSheet(rosterSheet); err != nil {
+		return nil, fmt.Errorf("BuildResultsXLSX: failed to create sheet %q: %w", rosterSheet, err)
+	}
+	if err := writeTable(f, rosterSheet, 1,
+		[]string{"ID", "ФИО", "Группа", "Зарегистрирован", "Проголосовал"},
+		rosterRows(delegates), styles); err != nil {
+		return nil, fmt.Errorf("BuildResultsXLSX: %w", err)
+	}
+
+	f.SetActiveSheet(0)
+	buf, err := f.WriteToBuffer()
+	if err != nil {
+		return nil, fmt.Errorf("BuildResultsXLSX: failed to render xlsx: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+func newXLSXStyles(f *excelize.File) (xlsxStyles, error) {
+	var styles xlsxStyles
+	var err error
+	border := []excelize.Border{
+		{Type: "left", Color: "#BFBFBF", Style: 1},
+		{Type: "right", Color: "#BFBFBF", Style: 1},
+		{Type: "top", Color: "#BFBFBF", Style: 1},
+		{Type: "bottom", Color: "#BFBFBF", Style: 1},
+	}
+	if styles.title, err = f.NewStyle(&excelize.Style{Font: &excelize.Font{Bold: true, Size: 13}}); err != nil {
+		return styles, fmt.Errorf("failed to create title style: %w", err)
+	}
+	if styles.header, err = f.NewStyle(&excelize.Style{
+		Font:      &excelize.Font{Bold: true},
+		Fill:      excelize.Fill{Type: "pattern", Color: []string{"#E6E6E6"}, Pattern: 1},
+		Border:    border,
+		Alignment: &excelize.Alignment{Horizontal: "center", Vertical: "center", WrapText: true},
+	}); err != nil {
+		return styles, fmt.Errorf("failed to create header style: %w", err)
+	}
+	if styles.diagonal, err = f.NewStyle(&excelize.Style{
+		Fill:      excelize.Fill{Type: "pattern", Color: []string{"#808080"}, Pattern: 1},
+		Border:    border,
+		Alignment: &excelize.Alignment{Horizontal: "center"},
+	}); err != nil {
+		return styles, fmt.Errorf("failed to create diagonal style: %w", err)
+	}
+	if styles.win, err = f.NewConditionalStyle(&excelize.Style{
+		Font: &excelize.Font{Bold: true, Color: "#006100"},
+		Fill: excelize.Fill{Type: "pattern", Color: []string{"#C6EFCE"}, Pattern: 1},
+	}); err != nil {
+		return styles, fmt.Errorf("failed to create win style: %w", err)
+	}
+	if styles.loss, err = f.NewConditionalStyle(&excelize.Style{
+		Font: &excelize.Font{Color: "#9C0006"},
+		Fill: excelize.Fill{Type: "pattern", Color: []string{"#FFC7CE"}, Pattern: 1},
+	}); err != nil {
+		return styles, fmt.Errorf("failed to create loss style: %w", err)
+	}
+	return styles, nil
+}
+
+// writeResultSheet заполняет лист курса: победитель и обе матрицы
+func writeResultSheet(f *excelize.File, sheet string, result models.Result, candidatesByID map[int]models.Candidate, styles xlsxStyles) error {
+	f.SetCellValue(sheet, "A1", "Курс: "+result.Course)
+	f.SetCellStyle(sheet, "A1", "A1", styles.title)
+	f.SetCellValue(sheet, "A2", "Победитель")
+	f.SetCellValue(sheet, "B2", winnersToString(result.WinnerCandidateID, candidatesByID))
+	f.SetCellValue(sheet, "A3", "Этап определения")
+	f.SetCellValue(sheet, "B3", StageDescription(result.Stage))
+	return writeMatrices(f, sheet, 5, result, candidatesByID, styles)
+}
+
+// writeCommonSheet заполняет лист общих мест: порядок распределения и обе матрицы
+func writeCommonSheet(f *excelize.File, sheet string, result models.Result, candidatesByID map[int]models.Candidate, styles xlsxStyles) error {
+	f.SetCellValue(sheet, "A1", "Распределение общих мест")
+	f.SetCellStyle(sheet, "A1", "A1", styles.title)
+	rows := make([][]string, 0, len(result.WinnerCandidateID))
+	for i, candidateID := range result.WinnerCandidateID {
+		candidate := candidatesByID[candidateID]
+		rows = append(rows, []string{fmt.Sprint(i + 1), candidate.Name, candidate.Course})
+	}
+	if err := writeTable(f, sheet, 2, []string{"Место", "ФИО", "Курс"}, rows, styles); err != nil {
+		return err
+	}
+	return writeMatrices(f, sheet, len(rows)+4, result, candidatesByID, styles)
+}
+
+// writeMatrices выводит таблицы парных предпочтений и сильнейших путей начиная со строки startRow
+func writeMatrices(f *excelize.File, sheet string, startRow int, result models.Result, candidatesByID map[int]models.Candidate, styles xlsxStyles) error {
+	order := matrixOrder(result.Preferences)
+	f.SetCellValue(sheet, cellName(1, startRow), "Таблица парных предпочтений d[строка][столбец]")
+	f.SetCellStyle(sheet, cellName(1, startRow), cellName(1, startRow), styles.title)
+	if err := writeMatrix(f, sheet, startRow+1, result.Preferences, order, candidatesByID, styles); err != nil {
+		return fmt.Errorf("failed to write preferences: %w", err)
+	}
+
+	startRow += len(order) + 3
+	f.SetCellValue(sheet, cellName(1, startRow), "Таблица сильнейших путей p[строка][столбец]")
+	f.SetCellStyle(sheet, cellName(1, startRow), cellName(1, startRow), styles.title)
+	if err := writeMatrix(f, sheet, startRow+1, result.StrongestPaths, order, candidatesByID, styles); err != nil {
+		return fmt.Errorf("failed to write strongest paths: %w", err)
+	}
+
+	f.SetColWidth(sheet, "A", "A", 36)
+	if len(order) > 0 {
+		f.SetColWidth(sheet, "B", columnName(len(order)+1), 16)
+	}
+	return nil
+}
+
+// writeMatrix выводит матрицу с шапкой в строке headerRow.
+// Ячейка [i][j] подсвечивается зелёным, если она больше симметричной [j][i], и красным, если меньше.
+func writeMatrix(f *excelize.File, sheet string, headerRow int, matrix map[int]map[int]int, order []int, candidatesByID map[int]models.Candidate, styles xlsxStyles) error {
+	if len(order) == 0 {
+		return nil
+	}
+	f.SetCellStyle(sheet, cellName(1, headerRow), cellName(1, headerRow), styles.header)
+	for i, candidateID := range order {
+		label := candidateLabel(candidateID, candidatesByID)
+		f.SetCellValue(sheet, cellName(i+2, headerRow), label)
+		f.SetCellValue(sheet, cellName(1, headerRow+i+1), label)
+	}
+	f.SetCellStyle(sheet, cellName(2, headerRow), cellName(len(order)+1, headerRow), styles.header)
+	f.SetCellStyle(sheet, cellName(1, headerRow+1), cellName(1, headerRow+len(order)), styles.header)
+
+	for i, rowID := range order {
+		for j, colID := range order {
+			cell := cellName(j+2, headerRow+i+1)
+			if i == j {
+				f.SetCellStyle(sheet, cell, cell, styles.diagonal)
+				continue
+			}
+			if v, ok := matrix[rowID][colID]; ok {
+				f.SetCellValue(sheet, cell, v)
+			}
+		}
+	}
+
+	// Симметричная ячейка находится через INDEX по всей матрице с переставленными индексами
+	first := cellName(2, headerRow+1)
+	last := cellName(len(order)+1, headerRow+len(order))
+	absRange := fmt.Sprintf("$%s$%d:$%s$%d", columnName(2), headerRow+1, columnName(len(order)+1), headerRow+len(order))
+	mirror := fmt.Sprintf("INDEX(%s,COLUMN()-1,ROW()-%d)", absRange, headerRow)
+	return f.SetConditionalFormat(sheet, first+":"+last, []excelize.ConditionalFormatOptions{
+		{Type: "formula", Format: styles.win, Criteria: fmt.Sprintf("AND(ISNUMBER(%s),%s>%s)", first, first, mirror)},
+		{Type: "formula", Format: styles.loss, Criteria: fmt.Sprintf("AND(ISNUMBER(%s),%s<%s)", first, first, mirror)},
+	})
+}
+
+// writeTable выводит таблицу с шапкой в строке headerRow
+func writeTable(f *excelize.File, sheet string, headerRow int, header []string, rows [][]string, styles xlsxStyles) error {
+	for i, title := range header {
+		f.SetCellValue(sheet, cellName(i+1, headerRow), title)
+	}
+	if err := f.SetCellStyle(sheet, cellName(1, headerRow), cellName(len(header), headerRow), styles.header); err != nil {
+		return fmt.Errorf("failed to style table header: %w", err)
+	}
+	for i, row := range rows {
+		for j, value := range row {
+			f.SetCellValue(sheet, cellName(j+1, headerRow+i+1), value)
+		}
+	}
+	f.SetColWidth(sheet, "A", columnName(len(header)), 20)
+	return nil
+}
+
+// rosterRows формирует список делегатов для листа с ростером
+func rosterRows(delegates []models.Delegate) [][]string {
+	yesNo := func(v bool) string {
+		if v {
+			return "да"
+		}
+		return "нет"
+	}
+	rows := make([][]string, 0, len(delegates))
+	for _, delegate := range delegates {
+		rows = append(rows, []string{
+			"st" + idtos(delegate.DelegateID), delegate.Name, delegate.Group,
+			yesNo(delegate.TelegramID.Valid), yesNo(delegate.HasVoted),
+		})
+	}
+	return rows
+}
+
+// candidateLabel возвращает подпись кандидата для заголовков матрицы
+func candidateLabel(candidateID int, candidatesByID map[int]models.Candidate) string {
+	if candidate, ok := candidatesByID[candidateID]; ok {
+		return fmt.Sprintf("%s (st%s)", candidate.Name, idtos(candidateID))
+	}
+	return "st" + idtos(candidateID)
+}
+
+// xlsxSheetName приводит название листа к ограничениям Excel и делает его уникальным
+func xlsxSheetName(name string, used map[string]bool) string {
+	name = strings.Map(func(r rune) rune {
+		if strings.ContainsRune(`[]:*?/\`, r) {
+			return '_'
+		}
+		return r
+	}, name)
+	if strings.TrimSpace(name) == "" {
+		name = "Лист"
+	}
+	runes := []rune(name)
+	if len(runes) > 28 {
+		runes = runes[:28]
+	}
+	base := string(runes)
+	name = base
+	for i := 2; used[strings.ToLower(name)]; i++ {
+		name = fmt.Sprintf("%s %d", base, i)
+	}
+	used[strings.ToLower(name)] = true
+	return name
+}
+
+func cellName(col, row int) string {
+	cell, _ := excelize.CoordinatesToCellName(col, row)
+	return cell
+}
+
+func columnName(col int) string {
+	name, _ := excelize.ColumnNumberToName(col)
+	return name
+}
@@ -0,0 +1,147 @@
+package schulze
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/lsdpls/schulze_election_telegram_bot/internal/models"
+	mock "github.com/lsdpls/schulze_election_telegram_bot/internal/schulze/mocks"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xuri/excelize/v2"
+)
+
+func TestBuildResultsXLSX(t *testing.T) {
+	t.Parallel()
+
+	// Два длинных названия с недопустимыми символами совпадают после усечения до 28 символов
+	const longInformatics = "Магистратура: Прикладная математика/информатика"
+	const longMechanics = "Магистратура: Прикладная математика/механика"
+	const longSheet = "Магистратура_ Прикладная мат"
+
+	candidates := []models.Candidate{
+		{CandidateID: 111111, Name: "Иванов Иван", Course: "1 курс", IsEligible: true},
+		{CandidateID: 222222, Name: "Петров Петр", Course: "1 курс", IsEligible: true},
+	}
+	results := []models.Result{
+		{Course: longMechanics, Stage: "tie"},
+		{Course: "common", Stage: "common", WinnerCandidateID: []int{222222}},
+		{
+			Course:            "1 курс",
+			Stage:             "absolute",
+			WinnerCandidateID: []int{111111},
+			Preferences:       map[int]map[int]int{111111: {222222: 2}, 222222: {111111: 1}},
+			StrongestPaths:    map[int]map[int]int{111111: {222222: 2}, 222222: {111111: 0}},
+		},
+		{Course: longInformatics, Stage: "tie"},
+	}
+	delegates := []models.Delegate{
+		{DelegateID: 100001, Name: "Смирнов Олег", Group: "21.Б01-пу", TelegramID: sql.NullInt64{Int64: 1, Valid: true}, HasVoted: true},
+		{DelegateID: 100002, Name: "Кузнецова Мария", Group: "22.Б02-пу"},
+	}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockChain := mock.NewMockchain(ctrl)
+	mockChain.EXPECT().GetAllResults(gomock.Any()).Return(results, nil)
+	mockChain.EXPECT().GetAllCandidates(gomock.Any()).Return(candidates, nil)
+	mockChain.EXPECT().GetAllDelegates(gomock.Any()).Return(delegates, nil)
+
+	s := &Schulze{voteChain: mockChain}
+	data, err := s.BuildResultsXLSX(context.Background())
+	require.NoError(t, err)
+
+	f, err := excelize.OpenReader(bytes.NewReader(data))
+	require.NoError(t, err)
+	defer f.Close()
+
+	sheets := f.GetSheetList()
+	assert.Equal(t, []string{"1 курс", longSheet, longSheet + " 2", "Общие места", "Явка", "Делегаты"}, sheets)
+	for _, sheet := range sheets {
+		assert.LessOrEqual(t, utf8.RuneCountInString(sheet), 31, sheet)
+	}
+
+	cells := func(sheet string, want map[string]string) {
+		t.Helper()
+		for cell, value := range want {
+			got, err := f.GetCellValue(sheet, cell)
+			require.NoError(t, err)
+			assert.Equal(t, value, got, "%s!%s", sheet, cell)
+		}
+	}
+
+	// Лист курса: победитель, таблица парных предпочтений с шапкой в 6-й строке и сильнейших путей с 11-й
+	cells("1 курс", map[string]string{
+		"A1":  "Курс: 1 курс",
+		"B2":  "Иванов Иван",
+		"B3":  StageDescription("absolute"),
+		"B6":  "Иванов Иван (st111111)",
+		"C6":  "Петров Петр (st222222)",
+		"A7":  "Иванов Иван (st111111)",
+		"A8":  "Петров Петр (st222222)",
+		"B7":  "",
+		"C7":  "2",
+		"B8":  "1",
+		"B11": "Иванов Иван (st111111)",
+		"C12": "2",
+		"B13": "0",
+	})
+	cells(longSheet, map[string]string{"A1": "Курс: " + longInformatics})
+	cells(longSheet+" 2", map[string]string{"A1": "Курс: " + longMechanics})
+	cells("Общие места", map[string]string{
+		"A2": "Место", "B2": "ФИО", "C2": "Курс",
+		"A3": "1", "B3": "Петров Петр", "C3": "1 курс",
+	})
+	cells("Явка", map[string]string{
+		"A1": "Группа", "E1": "Явка",
+		"A2": "21.Б01-пу", "D2": "1",
+	})
+	cells("Делегаты", map[string]string{
+		"A1": "ID", "B1": "ФИО", "E1": "Проголосовал",
+		"A2": "st100001", "B2": "Смирнов Олег", "D2": "да", "E2": "да",
+		"A3": "st100002", "D3": "нет", "E3": "нет",
+	})
+}
+
+func TestBuildResultsXLSXNoResults(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockChain := mock.NewMockchain(ctrl)
+	mockChain.EXPECT().GetAllResults(gomock.Any()).Return(nil, nil)
+
+	s := &Schulze{voteChain: mockChain}
+	_, err := s.BuildResultsXLSX(context.Background())
+	assert.Error(t, err)
+}
+
+func TestXLSXSheetName(t *testing.T) {
+	t.Parallel()
+
+	used := make(map[string]bool)
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{name: "Plain", in: "1 курс", want: "1 курс"},
+		{name: "CaseInsensitiveDuplicate", in: "1 КУРС", want: "1 КУРС 2"},
+		{name: "InvalidChars", in: `a[b]c:d*e?f/g\h`, want: "a_b_c_d_e_f_g_h"},
+		{name: "Empty", in: "  ", want: "Лист"},
+		{name: "Truncated", in: "абвгдеёжзийклмнопрстуфхцчшщъыьэюя", want: "абвгдеёжзийклмнопрстуфхцчшщъ"},
+		{name: "TruncatedDuplicate", in: "абвгдеёжзийклмнопрстуфхцчшщъыьэюя!", want: "абвгдеёжзийклмнопрстуфхцчшщъ 2"},
+	}
+	for _, tt := range tests {
+		got := xlsxSheetName(tt.in, used)
+		assert.Equal(t, tt.want, got, tt.name)
+		assert.LessOrEqual(t, utf8.RuneCountInString(got), 31, tt.name)
+	}
+}
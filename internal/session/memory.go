@@ -0,0 +1,86 @@
+package session
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+)
+
+type memoryEntry struct {
+	value     []byte
+	expiresAt time.Time // Нулевое время — бессрочно
+}
+
+// MemoryStore хранит сессии в памяти процесса. Используется в тестах и при локальной разработке
+type MemoryStore struct {
+	mu      sync.Mutex
+	entries map[string]memoryEntry
+	now     func() time.Time
+}
+
+// NewMemoryStore создает пустое хранилище сессий в памяти
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		entries: make(map[string]memoryEntry),
+		now:     time.Now,
+	}
+}
+
+// Get читает значение по ключу в dest. Возвращает false, если ключа нет или срок его действия истек
+func (m *MemoryStore) Get(_ context.Context, key string, dest any) (bool, error) {
+	m.mu.Lock()
+	entry, ok := m.entries[key]
+	if ok && !entry.expiresAt.IsZero() && !m.now().Before(entry.expiresAt) {
+		delete(m.entries, key)
+		ok = false
+	}
+	m.mu.Unlock()
+	if !ok {
+		return false, nil
+	}
+	if err := json.Unmarshal(entry.value, dest); err != nil {
+		return false, fmt.Errorf("session.MemoryStore.Get: unmarshal failed: %w", err)
+	}
+	return true, nil
+}
+
+// Set сохраняет значение по ключу. ttl <= 0 означает бессрочное хранение
+func (m *MemoryStore) Set(_ context.Context, key string, value any, ttl time.Duration) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("session.MemoryStore.Set: marshal failed: %w", err)
+	}
+	entry := memoryEntry{value: data}
+	if ttl > 0 {
+		entry.expiresAt = m.now().Add(ttl)
+	}
+	m.mu.Lock()
+	m.entries[key] = entry
+	m.mu.Unlock()
+	return nil
+}
+
+// Take читает значение по ключу в dest и удаляет его. Из параллельных вызовов значение получает только один
+func (m *MemoryStore) Take(_ context.Context, key string, dest any) (bool, error) {
+	m.mu.Lock()
+	entry, ok := m.entries[key]
+	delete(m.entries, key)
+	m.mu.Unlock()
+	if !ok || (!entry.expiresAt.IsZero() && !m.now().Before(entry.expiresAt)) {
+		return false, nil
+	}
+	if err := json.Unmarshal(entry.value, dest); err != nil {
+		return false, fmt.Errorf("session.MemoryStore.Take: unmarshal failed: %w", err)
+	}
+	return true, nil
+}
+
+// Delete удаляет значение по ключу
+func (m *MemoryStore) Delete(_ context.Context, key string) error {
+	m.mu.Lock()
+	delete(m.entries, key)
+	m.mu.Unlock()
+	return nil
+}
@@ -0,0 +1,73 @@
+package session
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryStore(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	now := time.Date(2024, 10, 14, 12, 0, 0, 0, time.UTC)
+	store := NewMemoryStore()
+	store.now = func() time.Time { return now }
+
+	type ballot struct {
+		Ranking []int `json:"ranking"`
+	}
+
+	// Отсутствующий ключ
+	var got ballot
+	ok, err := store.Get(ctx, "ballot:1", &got)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	// Значение с TTL читается до истечения срока
+	assert.NoError(t, store.Set(ctx, "ballot:1", ballot{Ranking: []int{3, 1, 2}}, time.Minute))
+	ok, err = store.Get(ctx, "ballot:1", &got)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, []int{3, 1, 2}, got.Ranking)
+
+	// Бессрочное значение
+	assert.NoError(t, store.Set(ctx, "voting", true, 0))
+
+	// После истечения TTL значение пропадает, бессрочное остается
+	now = now.Add(time.Minute)
+	ok, err = store.Get(ctx, "ballot:1", &got)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+	var active bool
+	ok, err = store.Get(ctx, "voting", &active)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.True(t, active)
+
+	// Удаление
+	assert.NoError(t, store.Delete(ctx, "voting"))
+	ok, err = store.Get(ctx, "voting", &active)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	// Take возвращает значение один раз
+	assert.NoError(t, store.Set(ctx, "confirm:1", ballot{Ranking: []int{2, 1}}, time.Minute))
+	got = ballot{}
+	ok, err = store.Take(ctx, "confirm:1", &got)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, []int{2, 1}, got.Ranking)
+	ok, err = store.Take(ctx, "confirm:1", &got)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	// Истекшее значение не возвращается
+	assert.NoError(t, store.Set(ctx, "confirm:2", ballot{}, time.Minute))
+	now = now.Add(time.Minute)
+	ok, err = store.Take(ctx, "confirm:2", &got)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+}
@@ -0,0 +1,107 @@
+package session
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/lsdpls/schulze_election_telegram_bot/internal/models"
+
+	log "github.com/sirupsen/logrus"
+)
+
+type chain interface {
+	SetSession(ctx context.Context, session models.Session) error
+	GetSession(ctx context.Context, key string) (*models.Session, error)
+	TakeSession(ctx context.Context, key string) (*models.Session, error)
+	DeleteSession(ctx context.Context, key string) error
+	DeleteExpiredSessions(ctx context.Context) (int64, error)
+}
+
+// PostgresStore хранит сессии в таблице sessions, поэтому они переживают перезапуск бота
+type PostgresStore struct {
+	voteChain chain
+}
+
+// NewPostgresStore создает хранилище сессий поверх цепочки базы данных
+func NewPostgresStore(voteChain chain) *PostgresStore {
+	return &PostgresStore{voteChain: voteChain}
+}
+
+// Get читает значение по ключу в dest. Возвращает false, если ключа нет или срок его действия истек
+func (p *PostgresStore) Get(ctx context.Context, key string, dest any) (bool, error) {
+	session, err := p.voteChain.GetSession(ctx, key)
+	if err != nil {
+		return false, fmt.Errorf("session.PostgresStore.Get: %w", err)
+	}
+	if session == nil {
+		return false, nil
+	}
+	if err := json.Unmarshal(session.Value, dest); err != nil {
+		return false, fmt.Errorf("session.PostgresStore.Get: unmarshal failed: %w", err)
+	}
+	return true, nil
+}
+
+// Set сохраняет значение по ключу. ttl <= 0 означает бессрочное хранение
+func (p *PostgresStore) Set(ctx context.Context, key string, value any, ttl time.Duration) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("session.PostgresStore.Set: marshal failed: %w", err)
+	}
+	now := time.Now()
+	session := models.Session{Key: key, Value: data, UpdatedAt: now}
+	if ttl > 0 {
+		session.ExpiresAt = sql.NullTime{Time: now.Add(ttl), Valid: true}
+	}
+	if err := p.voteChain.SetSession(ctx, session); err != nil {
+		return fmt.Errorf("session.PostgresStore.Set: %w", err)
+	}
+	return nil
+}
+
+// Take читает значение по ключу в dest и удаляет его. Из параллельных вызовов значение получает только один
+func (p *PostgresStore) Take(ctx context.Context, key string, dest any) (bool, error) {
+	session, err := p.voteChain.TakeSession(ctx, key)
+	if err != nil {
+		return false, fmt.Errorf("session.PostgresStore.Take: %w", err)
+	}
+	if session == nil {
+		return false, nil
+	}
+	if err := json.Unmarshal(session.Value, dest); err != nil {
+		return false, fmt.Errorf("session.PostgresStore.Take: unmarshal failed: %w", err)
+	}
+	return true, nil
+}
+
+// Delete удаляет значение по ключу
+func (p *PostgresStore) Delete(ctx context.Context, key string) error {
+	if err := p.voteChain.DeleteSession(ctx, key); err != nil {
+		return fmt.Errorf("session.PostgresStore.Delete: %w", err)
+	}
+	return nil
+}
+
+// RunCleanup периодически удаляет истекшие сессии до отмены контекста
+func (p *PostgresStore) RunCleanup(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			deleted, err := p.voteChain.DeleteExpiredSessions(ctx)
+			if err != nil {
+				log.Errorf("Failed to delete expired sessions: %v", err)
+				continue
+			}
+			if deleted > 0 {
+				log.Debugf("Deleted %d expired sessions", deleted)
+			}
+		}
+	}
+}
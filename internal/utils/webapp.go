@@ -0,0 +1,72 @@
+package utils
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	ErrInitDataInvalid = errors.New("invalid init data")
+	ErrInitDataExpired = errors.New("init data expired")
+)
+
+// ValidateWebAppInitData проверяет подпись initData, переданных Telegram Mini App, и возвращает telegramID пользователя.
+// Подпись считается по алгоритму Telegram: HMAC-SHA256 от data-check-string
+// с ключом HMAC-SHA256("WebAppData", botToken). Данные старше maxAge отклоняются
+func ValidateWebAppInitData(initData, botToken string, maxAge time.Duration) (int64, error) {
+	values, err := url.ParseQuery(initData)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %v", ErrInitDataInvalid, err)
+	}
+	hash := values.Get("hash")
+	if hash == "" {
+		return 0, fmt.Errorf("%w: hash is missing", ErrInitDataInvalid)
+	}
+
+	// data-check-string: все поля, кроме hash, в алфавитном порядке в формате key=value через \n
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		if key != "hash" {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	pairs := make([]string, 0, len(keys))
+	for _, key := range keys {
+		pairs = append(pairs, key+"="+values.Get(key))
+	}
+
+	secret := hmac.New(sha256.New, []byte("WebAppData"))
+	secret.Write([]byte(botToken))
+	h := hmac.New(sha256.New, secret.Sum(nil))
+	h.Write([]byte(strings.Join(pairs, "\n")))
+	expected := hex.EncodeToString(h.Sum(nil))
+	if !hmac.Equal([]byte(expected), []byte(hash)) {
+		return 0, fmt.Errorf("%w: hash mismatch", ErrInitDataInvalid)
+	}
+
+	authDate, err := strconv.ParseInt(values.Get("auth_date"), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%w: bad auth_date", ErrInitDataInvalid)
+	}
+	if maxAge > 0 && time.Since(time.Unix(authDate, 0)) > maxAge {
+		return 0, ErrInitDataExpired
+	}
+
+	var user struct {
+		ID int64 `json:"id"`
+	}
+	if err := json.Unmarshal([]byte(values.Get("user")), &user); err != nil || user.ID == 0 {
+		return 0, fmt.Errorf("%w: bad user", ErrInitDataInvalid)
+	}
+	return user.ID, nil
+}
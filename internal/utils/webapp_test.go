@@ -0,0 +1,104 @@
+package utils
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testBotToken = "123456:TEST-TOKEN"
+
+// signInitData подписывает поля так же, как это делает Telegram
+func signInitData(fields map[string]string, botToken string) string {
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	pairs := make([]string, 0, len(keys))
+	values := url.Values{}
+	for _, key := range keys {
+		pairs = append(pairs, key+"="+fields[key])
+		values.Set(key, fields[key])
+	}
+	secret := hmac.New(sha256.New, []byte("WebAppData"))
+	secret.Write([]byte(botToken))
+	h := hmac.New(sha256.New, secret.Sum(nil))
+	h.Write([]byte(strings.Join(pairs, "\n")))
+	values.Set("hash", hex.EncodeToString(h.Sum(nil)))
+	return values.Encode()
+}
+
+// tamperInitData подменяет значение поля, сохраняя исходную подпись
+func tamperInitData(initData, key, value string) string {
+	values, _ := url.ParseQuery(initData)
+	values.Set(key, value)
+	return values.Encode()
+}
+
+func TestValidateWebAppInitData(t *testing.T) {
+	t.Parallel()
+
+	now := strconv.FormatInt(time.Now().Unix(), 10)
+	old := strconv.FormatInt(time.Now().Add(-2*time.Hour).Unix(), 10)
+	user := `{"id":42,"first_name":"Иван"}`
+
+	tests := []struct {
+		name     string
+		initData string
+		wantID   int64
+		wantErr  error
+	}{
+		{
+			name:     "Valid",
+			initData: signInitData(map[string]string{"auth_date": now, "query_id": "AAH", "user": user}, testBotToken),
+			wantID:   42,
+		},
+		{
+			name:     "WrongToken",
+			initData: signInitData(map[string]string{"auth_date": now, "user": user}, "654321:OTHER"),
+			wantErr:  ErrInitDataInvalid,
+		},
+		{
+			name:     "TamperedUser",
+			initData: tamperInitData(signInitData(map[string]string{"auth_date": now, "user": user}, testBotToken), "user", `{"id":43}`),
+			wantErr:  ErrInitDataInvalid,
+		},
+		{
+			name:     "MissingHash",
+			initData: "auth_date=" + now + "&user=" + url.QueryEscape(user),
+			wantErr:  ErrInitDataInvalid,
+		},
+		{
+			name:     "Expired",
+			initData: signInitData(map[string]string{"auth_date": old, "user": user}, testBotToken),
+			wantErr:  ErrInitDataExpired,
+		},
+		{
+			name:     "NoUser",
+			initData: signInitData(map[string]string{"auth_date": now}, testBotToken),
+			wantErr:  ErrInitDataInvalid,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			id, err := ValidateWebAppInitData(tt.initData, testBotToken, time.Hour)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantID, id)
+		})
+	}
+}